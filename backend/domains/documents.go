@@ -4,6 +4,7 @@ package domains
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -23,6 +24,8 @@ type Document struct {
 	ContentsLength int          // File size in bytes
 	ContentsHash   string       // SHA-256 hash of document contents (hex, 64 chars) – used as dedupe key
 	Contents       []byte       // Current file contents
+	Revision       int          // revision number of the contents
+	Reason         string       // why the contents were uploaded; recorded with the revision
 	ModifiedAt     time.Time    // Document modification time, stored in UTC
 	CreatedAt      time.Time    // record creation time, stored in UTC
 	UpdatedAt      time.Time    // record update time, stored in UTC
@@ -51,6 +54,7 @@ func (dt DocumentType) IsValid() bool {
 var (
 	documentExtToDocumentType = map[string]DocumentType{
 		"docx": TurnReportFile,
+		"odt":  TurnReportFile,
 		"txt":  TurnReportExtract,
 		"wxx":  WorldographerMap,
 	}
@@ -72,11 +76,38 @@ func DocumentExtToDocumentType(ext string) DocumentType {
 func DocumentTypeToDocumentExt(dt DocumentType) string {
 	ext, ok := documentTypeToDocumentExt[dt]
 	if !ok {
-		panic(fmt.Sprintf("assert(type != %d)", dt))
+		panic(fmt.Sprintf("assert(type != %q)", dt))
 	}
 	return ext
 }
 
+// TurnIdFromName returns the turn id from a document name.
+// Our convention is {game}.{turn}.{unitId}.{documentType}, but the game
+// is often left off of extracts that aren't stored, so the turn id is the
+// first field that looks like one (YYYY-MM).
+// Returns an empty string if there isn't one.
+func TurnIdFromName(name string) string {
+	for _, field := range strings.Split(name, ".") {
+		if isTurnId(field) {
+			return field
+		}
+	}
+	return ""
+}
+
+// isTurnId returns true if the field looks like a turn id (YYYY-MM).
+func isTurnId(field string) bool {
+	if len(field) != 7 || field[4] != '-' {
+		return false
+	}
+	for i, ch := range field {
+		if i != 4 && (ch < '0' || '9' < ch) {
+			return false
+		}
+	}
+	return true
+}
+
 type MimeType string
 
 const (
@@ -86,6 +117,7 @@ const (
 	//  - 2025 Map: Content-Type: application/vnd.worldographer.map; version=2025
 
 	DOCXMimeType   MimeType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	ODTMimeType    MimeType = "application/vnd.oasis.opendocument.text"
 	ReportMimeType MimeType = "text/vnd.tribenet-turnreport.data"
 	WXXMimeType    MimeType = "application/vnd.worldographer.map"
 )
@@ -94,6 +126,8 @@ func (mt MimeType) IsValid() bool {
 	switch mt {
 	case DOCXMimeType:
 		return true
+	case ODTMimeType:
+		return true
 	case ReportMimeType:
 		return true
 	case WXXMimeType:
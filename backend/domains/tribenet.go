@@ -27,6 +27,8 @@ type UnitSection struct {
 
 	GoesTo *Location // optional, the location that a unit teleports to before moving
 
+	Follows UnitId // optional, the unit that this unit follows
+
 	MovementReport *MovementReport // optional, not every unit moves every turn
 
 	ScoutingReport []*ScoutingReport // optional, scouting results, one record per scout
@@ -52,8 +54,31 @@ type MovementReport struct {
 }
 
 type Step struct {
-	Location *Location // the location the step is reporting on
-	Results  []*Result // the results of the step, usually information on the "to" location and visible neighbors
+	Text      string    // the text of the step from the report, for diagnostics
+	Direction Direction // the direction the unit moved or tried to move; ignored if the outcome is unknown
+	Outcome   Outcome   // whether the unit moved
+	Location  *Location // the location the step is reporting on
+	Results   []*Result // the results of the step, usually information on the "to" location and visible neighbors
+}
+
+// Outcome is an enum describing the outcome of a step.
+type Outcome int
+
+const (
+	UnknownOutcome Outcome = iota // the step wasn't a move (e.g. a patrol or a find)
+	Succeeded                     // the unit moved
+	Failed                        // the unit tried to move but stayed in place
+)
+
+// String implements the fmt.Stringer interface.
+func (o Outcome) String() string {
+	switch o {
+	case Succeeded:
+		return "succeeded"
+	case Failed:
+		return "failed"
+	}
+	return "unknown"
 }
 
 type Result struct {
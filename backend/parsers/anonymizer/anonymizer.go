@@ -0,0 +1,368 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+// Package anonymizer removes the player's details from a turn report
+// extract so that it can be shared in a bug report or added to the
+// test corpus.
+//
+// The anonymizer remaps clan numbers (and with them every unit id,
+// including the c, e, f, and g suffixes), settlement names, and special
+// hex names. It can also shift the map by whole grids, which hides the
+// clan's location without changing the shape of the map. Unit names are
+// removed from the section headers.
+//
+// The mapping is consistent: an Anonymizer uses the same mapping for
+// every report it is given, so all the turns for a clan can be
+// anonymized together and still line up.
+//
+// The output is meant to parse exactly like the input. The text is never
+// reformatted; only the ids, names, and coordinates are replaced.
+package anonymizer
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/resources"
+)
+
+// Options controls the anonymizer.
+type Options struct {
+	Seed uint64 // seed for the clan and name mappings
+	// Clan is the new number for the clan that owns the first report.
+	// If zero, the number is picked from the seed.
+	Clan int
+	// GridRows and GridColumns shift every coordinate by whole grids.
+	// A grid is 30 columns by 21 rows, so the parity of the columns
+	// and the shape of the map don't change.
+	GridRows, GridColumns int
+}
+
+// Result is an anonymized report.
+type Result struct {
+	Text []byte
+	// Unchecked lists the units whose section couldn't be parsed.
+	// Settlement names that only show up in those sections aren't
+	// known to the anonymizer and must be checked by hand.
+	Unchecked []string
+}
+
+// Anonymizer holds the mappings. It is not safe for concurrent use.
+type Anonymizer struct {
+	opts   Options
+	rng    *rand.Rand
+	clans  []int             // clans[n] is the new number for clan n
+	pinned bool              // true once the owner of the first report is mapped to opts.Clan
+	names  map[string]string // lower case name to new name
+	used   map[string]bool   // new names in use
+}
+
+// New returns an anonymizer.
+func New(opts Options) (*Anonymizer, error) {
+	if opts.Clan < 0 || opts.Clan > 999 {
+		return nil, fmt.Errorf("clan %d: must be 1...999", opts.Clan)
+	}
+	a := &Anonymizer{
+		opts:  opts,
+		rng:   rand.New(rand.NewPCG(opts.Seed, opts.Seed^0xa707)),
+		names: map[string]string{},
+		used:  map[string]bool{},
+	}
+	// clan 0 is not a real clan and always maps to itself
+	a.clans = append([]int{0}, a.rng.Perm(999)...)
+	for n := 1; n < len(a.clans); n++ {
+		a.clans[n]++
+	}
+	return a, nil
+}
+
+var (
+	// section header, e.g. "Tribe 0987, Unit Name, Current Hex = ..."
+	reSection = regexp.MustCompile(`^(Courier|Element|Fleet|Garrison|Tribe) (\d{4}(?:[cefg][1-9])?), ([^,\n\r]*),`)
+	// coordinates or unit ids; coordinates are matched first so that
+	// the digits in "OO 0202" aren't taken for a unit id.
+	reToken = regexp.MustCompile(`([A-Z]{2}|##) (\d{4})\b|\b(\d)(\d{3})([cefg][1-9])?\b`)
+)
+
+// Clan returns the new number for the clan.
+func (a *Anonymizer) Clan(clan int) int {
+	if clan < 0 || clan >= len(a.clans) {
+		return clan
+	}
+	return a.clans[clan]
+}
+
+// UnitId returns the new id for the unit. The leading digit and the
+// suffix are kept; only the clan number changes.
+func (a *Anonymizer) UnitId(id bistre.UnitId_t) bistre.UnitId_t {
+	m := reToken.FindStringSubmatch(string(id))
+	if m == nil || m[3] == "" || m[0] != string(id) {
+		return id
+	}
+	return bistre.UnitId_t(a.unitId(m[3], m[4], m[5]))
+}
+
+func (a *Anonymizer) unitId(prefix, clan, suffix string) string {
+	n := 0
+	for _, ch := range clan {
+		n = n*10 + int(ch-'0')
+	}
+	return fmt.Sprintf("%s%03d%s", prefix, a.Clan(n), suffix)
+}
+
+// Name returns the new name for a settlement or special hex.
+// Names are matched without regard to case and the new name is
+// returned in the case of the old one (all lower, all upper, or as is).
+func (a *Anonymizer) Name(name string) string {
+	key := strings.ToLower(name)
+	newName, ok := a.names[key]
+	if !ok {
+		newName = a.newName()
+		a.names[key] = newName
+	}
+	switch name {
+	case strings.ToLower(name):
+		return strings.ToLower(newName)
+	case strings.ToUpper(name):
+		return strings.ToUpper(newName)
+	}
+	return newName
+}
+
+var (
+	consonants = []string{"b", "d", "f", "g", "k", "l", "m", "n", "p", "r", "s", "t", "v", "z"}
+	vowels     = []string{"a", "e", "i", "o", "u"}
+)
+
+// newName returns a name that isn't in use and can't be mistaken for
+// a resource, terrain, or direction by the parser.
+func (a *Anonymizer) newName() string {
+	for syllables := 2; ; syllables++ {
+		for tries := 0; tries < 50; tries++ {
+			var sb strings.Builder
+			for n := 0; n < syllables; n++ {
+				sb.WriteString(consonants[a.rng.IntN(len(consonants))])
+				sb.WriteString(vowels[a.rng.IntN(len(vowels))])
+			}
+			sb.WriteString(consonants[a.rng.IntN(len(consonants))])
+			name := strings.ToUpper(sb.String()[:1]) + sb.String()[1:]
+			if _, ok := resources.StringToEnum[name]; ok || a.used[name] {
+				continue
+			}
+			a.used[name] = true
+			return name
+		}
+	}
+}
+
+// Coords returns the coordinates shifted by the grid offsets.
+// Obscured ("##") and missing ("N/A") coordinates are not changed.
+func (a *Anonymizer) Coords(coords string) (string, error) {
+	grid, digits, ok := strings.Cut(coords, " ")
+	if !ok || len(grid) != 2 || grid == "##" || (a.opts.GridRows == 0 && a.opts.GridColumns == 0) {
+		return coords, nil
+	}
+	row, col := int(grid[0])+a.opts.GridRows, int(grid[1])+a.opts.GridColumns
+	if row < 'A' || row > 'Z' || col < 'A' || col > 'Z' {
+		return coords, fmt.Errorf("%s: shifted off the map", coords)
+	}
+	return fmt.Sprintf("%c%c %s", row, col, digits), nil
+}
+
+// FileName returns the file name with the clan remapped,
+// e.g. 0301.0899-12.0987.report.txt.
+func (a *Anonymizer) FileName(name string) string {
+	tid := domains.TurnIdFromName(name)
+	if tid == "" {
+		return name
+	}
+	// the clan follows the turn
+	fields := strings.Split(name, ".")
+	for i := 0; i+1 < len(fields); i++ {
+		if fields[i] == tid && isClan(fields[i+1]) {
+			fields[i+1] = a.unitId(fields[i+1][:1], fields[i+1][1:], "")
+			break
+		}
+	}
+	return strings.Join(fields, ".")
+}
+
+// isClan returns true if the field is a four digit clan id.
+func isClan(field string) bool {
+	if len(field) != 4 {
+		return false
+	}
+	for _, ch := range field {
+		if ch < '0' || '9' < ch {
+			return false
+		}
+	}
+	return true
+}
+
+// Anonymize returns the anonymized report. The name is only used for
+// error messages and to find the turn.
+func (a *Anonymizer) Anonymize(name string, input []byte, quiet, verbose, debug bool) (*Result, error) {
+	result := &Result{}
+
+	// find the clan that owns the report and give it the requested number
+	if !a.pinned && a.opts.Clan != 0 {
+		for _, line := range bytes.Split(input, []byte{'\n'}) {
+			if m := reSection.FindSubmatch(line); m != nil && m[1][0] == 'T' {
+				a.pin(int(m[2][1]-'0')*100 + int(m[2][2]-'0')*10 + int(m[2][3]-'0'))
+				break
+			}
+		}
+	}
+
+	// collect the settlement names. special hex names are only replaced on
+	// their own lines since they can be anything, even a direction.
+	var names []string
+	seen := map[string]bool{}
+	addName := func(name string) {
+		if key := strings.ToLower(name); name != "" && !seen[key] {
+			seen[key] = true
+			names = append(names, name)
+		}
+	}
+	settlements, unchecked := settlements(name, input, quiet, verbose, debug)
+	for _, s := range settlements {
+		addName(s)
+	}
+	result.Unchecked = unchecked
+	var reName *regexp.Regexp
+	if len(names) != 0 {
+		// longest first so that "Big Rock" wins over "Big"
+		sort.Slice(names, func(i, j int) bool {
+			if len(names[i]) != len(names[j]) {
+				return len(names[i]) > len(names[j])
+			}
+			return names[i] < names[j]
+		})
+		var alts []string
+		for _, n := range names {
+			alts = append(alts, regexp.QuoteMeta(n))
+		}
+		reName = regexp.MustCompile(`(?i)\b(?:` + strings.Join(alts, "|") + `)\b`)
+	}
+
+	out := &bytes.Buffer{}
+	lines := bytes.SplitAfter(input, []byte{'\n'})
+	for n, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+		// the turn line has dates that look like unit ids
+		if bytes.HasPrefix(line, []byte("Current Turn ")) {
+			out.Write(line)
+			continue
+		}
+		if m := reSection.FindSubmatchIndex(line); m != nil {
+			// drop the unit name
+			line = append(append([]byte{}, line[:m[6]]...), line[m[7]:]...)
+		}
+		if id, sname, ok := special(line); ok {
+			fmt.Fprintf(out, ">>>>%s>%s", a.Name(strings.ToLower(id)), a.Name(sname))
+			if bytes.HasSuffix(line, []byte{'\n'}) {
+				out.WriteByte('\n')
+			}
+			continue
+		}
+		var err error
+		line = reToken.ReplaceAllFunc(line, func(tok []byte) []byte {
+			m := reToken.FindSubmatch(tok)
+			if m[3] == nil {
+				coords, cerr := a.Coords(string(tok))
+				if cerr != nil && err == nil {
+					err = fmt.Errorf("%s: line %d: %w", name, n+1, cerr)
+				}
+				return []byte(coords)
+			}
+			return []byte(a.unitId(string(m[3]), string(m[4]), string(m[5])))
+		})
+		if err != nil {
+			return nil, err
+		}
+		if reName != nil {
+			line = reName.ReplaceAllFunc(line, func(s []byte) []byte {
+				return []byte(a.Name(string(s)))
+			})
+		}
+		out.Write(line)
+	}
+	result.Text = out.Bytes()
+	return result, nil
+}
+
+// pin swaps the mappings so that the clan maps to the requested number.
+func (a *Anonymizer) pin(clan int) {
+	a.pinned = true
+	for n, c := range a.clans {
+		if c == a.opts.Clan {
+			a.clans[n], a.clans[clan] = a.clans[clan], a.clans[n]
+			return
+		}
+	}
+}
+
+// special returns the id and name from a special hex line,
+// e.g. ">>>>big rock>BR".
+func special(line []byte) (id, name string, ok bool) {
+	rest, ok := bytes.CutPrefix(line, []byte(">>>>"))
+	if !ok {
+		return "", "", false
+	}
+	i, nm, _ := bytes.Cut(bytes.TrimRight(rest, "\r\n"), []byte{'>'})
+	return string(bytes.TrimSpace(i)), string(bytes.TrimSpace(nm)), true
+}
+
+// settlements returns the names of the settlements in the report.
+// Each unit section is parsed by itself so that a section the parser
+// rejects doesn't hide the settlements in the rest of the report.
+// The ids of the rejected units are returned as unchecked.
+func settlements(name string, input []byte, quiet, verbose, debug bool) (names, unchecked []string) {
+	tid := domains.TurnIdFromName(name)
+
+	var sections [][]byte
+	for _, line := range bytes.SplitAfter(input, []byte{'\n'}) {
+		if reSection.Match(line) || len(sections) == 0 {
+			sections = append(sections, nil)
+		}
+		sections[len(sections)-1] = append(sections[len(sections)-1], line...)
+	}
+	for _, section := range sections {
+		m := reSection.FindSubmatch(section)
+		if m == nil {
+			continue
+		}
+		turn, err := bistre.ParseInput(name, tid, section, false, debug, false, false, false, false, false, false, bistre.ParseConfig{Version: bistre.Version})
+		if err != nil || turn == nil {
+			if verbose {
+				log.Printf("[anonymizer] %s: %s: not parsed: %v\n", name, m[2], err)
+			}
+			unchecked = append(unchecked, string(m[2]))
+			continue
+		}
+		for _, moves := range turn.UnitMoves {
+			all := append([]*bistre.Move_t{}, moves.Moves...)
+			for _, scout := range moves.Scouts {
+				all = append(all, scout.Moves...)
+			}
+			for _, move := range all {
+				if move.Report == nil {
+					continue
+				}
+				for _, s := range move.Report.Settlements {
+					names = append(names, s.Name)
+				}
+			}
+		}
+	}
+	return names, unchecked
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package anonymizer_test
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/parsers/anonymizer"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre"
+	"github.com/playbymail/ottoapp/backend/parsers/synthetic"
+)
+
+func TestAnonymize(t *testing.T) {
+	w := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(w)
+
+	for seed := uint64(1); seed <= 20; seed++ {
+		m, err := synthetic.Generate(synthetic.Options{Seed: seed, Units: 12})
+		if err != nil {
+			t.Fatalf("%d: generate: %v", seed, err)
+		}
+		a, err := anonymizer.New(anonymizer.Options{Seed: seed, Clan: 123, GridRows: -1, GridColumns: 2})
+		if err != nil {
+			t.Fatalf("%d: new: %v", seed, err)
+		}
+		input := m.Text()
+		result, err := a.Anonymize("report.txt", input, true, false, false)
+		if err != nil {
+			t.Fatalf("%d: anonymize: %v", seed, err)
+		} else if len(result.Unchecked) != 0 {
+			t.Errorf("%d: unchecked: want none, got %v", seed, result.Unchecked)
+		}
+		if bytes.Contains(result.Text, []byte(m.Clan[1:]+",")) {
+			t.Errorf("%d: clan %s: still in the report", seed, m.Clan)
+		}
+		for _, sp := range m.Specials {
+			if bytes.Contains(bytes.ToLower(result.Text), []byte(sp.Id)) {
+				t.Errorf("%d: special %q: still in the report", seed, sp.Id)
+			}
+		}
+
+		want := parse(t, input)
+		got := parse(t, result.Text)
+		if len(got.UnitMoves) != len(want.UnitMoves) {
+			t.Fatalf("%d: units: want %d, got %d", seed, len(want.UnitMoves), len(got.UnitMoves))
+		}
+		for id, wm := range want.UnitMoves {
+			if id[1:4] == m.Clan[1:] && a.UnitId(id)[1:4] != "123" {
+				t.Errorf("%d: %s: want clan 123, got %s", seed, id, a.UnitId(id))
+			}
+			gm, ok := got.UnitMoves[a.UnitId(id)]
+			if !ok {
+				t.Errorf("%d: %s: missing as %s", seed, id, a.UnitId(id))
+				continue
+			}
+			if hex, _ := a.Coords(wm.CurrentHex); gm.CurrentHex != hex {
+				t.Errorf("%d: %s: current hex: want %q, got %q", seed, id, hex, gm.CurrentHex)
+			}
+			if len(gm.Moves) != len(wm.Moves) || len(gm.Scouts) != len(wm.Scouts) {
+				t.Errorf("%d: %s: moves: want %d, got %d", seed, id, len(wm.Moves), len(gm.Moves))
+				continue
+			}
+			for n, move := range wm.Moves {
+				if len(move.Report.Settlements) != len(gm.Moves[n].Report.Settlements) {
+					t.Errorf("%d: %s: move %d: settlements: want %d, got %d", seed, id, n+1, len(move.Report.Settlements), len(gm.Moves[n].Report.Settlements))
+				} else if len(move.Report.Settlements) != 0 && move.Report.Settlements[0].Name == gm.Moves[n].Report.Settlements[0].Name {
+					t.Errorf("%d: %s: move %d: settlement %q: not renamed", seed, id, n+1, move.Report.Settlements[0].Name)
+				}
+			}
+		}
+		if len(got.SpecialNames) != len(want.SpecialNames) {
+			t.Errorf("%d: specials: want %d, got %d", seed, len(want.SpecialNames), len(got.SpecialNames))
+		}
+	}
+}
+
+func TestFileName(t *testing.T) {
+	a, err := anonymizer.New(anonymizer.Options{Clan: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.Anonymize("0899-12.0987.report.txt", []byte("Tribe 0987, , Current Hex = OO 0202, (Previous Hex = N/A)\n"), true, false, false); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct{ name, want string }{
+		{"0899-12.0987.report.txt", "0899-12.0042.report.txt"},
+		{"0301.0899-12.0987.report.txt", "0301.0899-12.0042.report.txt"},
+		{"notes.txt", "notes.txt"},
+	} {
+		if got := a.FileName(tc.name); got != tc.want {
+			t.Errorf("%s: want %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
+
+func parse(t *testing.T, input []byte) *bistre.Turn_t {
+	t.Helper()
+	turn, err := bistre.ParseInput("report.txt", "", input, false, false, false, false, false, false, false, false, bistre.ParseConfig{Version: bistre.Version})
+	if err != nil {
+		t.Fatalf("parse: %v\n%s", err, strings.TrimSpace(string(input)))
+	}
+	return turn
+}
@@ -38,6 +38,12 @@ func Parse(source string, input []byte, quiet, verbose, debug bool) (Report, err
 		return Report{}, fmt.Errorf("azul: parse: %w", err)
 	}
 
+	return ParseText(source, d.Text, quiet, verbose, debug)
+}
+
+// ParseText parses the text of a turn report (for example, a report extract)
+// and returns the sections for every unit in it.
+func ParseText(source string, text []byte, quiet, verbose, debug bool) (Report, error) {
 	r := Report{
 		Path: filepath.Dir(source),
 		Name: filepath.Base(source),
@@ -45,7 +51,7 @@ func Parse(source string, input []byte, quiet, verbose, debug bool) (Report, err
 
 	var section *Section
 
-	for _, line := range bytes.Split(d.Text, []byte{LF}) {
+	for _, line := range bytes.Split(text, []byte{LF}) {
 		line = bytes.TrimSpace(line)
 		if len(line) == 0 {
 			continue
@@ -153,7 +159,9 @@ func Parse(source string, input []byte, quiet, verbose, debug bool) (Report, err
 				}
 				continue
 			}
-			log.Printf("%s: location %+v\n", r.Name, l)
+			if verbose {
+				log.Printf("%s: location %+v\n", r.Name, l)
+			}
 			section = &Section{
 				UnitId:         l.UnitId,
 				Kind:           "fleet",
@@ -274,11 +282,17 @@ func Parse(source string, input []byte, quiet, verbose, debug bool) (Report, err
 			panic("!implemented")
 		}
 
-		if reFleetMovement.Match(line) {
+		if m := reFleetMovement.FindSubmatch(line); m != nil {
 			if debug {
 				log.Printf("input %q\n", string(line))
 			}
-			panic("!implemented")
+			// the steps are not parsed yet; keep the line for the caller
+			section.WaterMovement = &WaterMovement{
+				Wind:      string(m[1]),
+				Direction: string(m[2]),
+				Line:      bdup(line),
+			}
+			continue
 		} else if reTribeFollows.Match(line) {
 			if debug {
 				log.Printf("input %q\n", string(line))
@@ -319,7 +333,22 @@ func Parse(source string, input []byte, quiet, verbose, debug bool) (Report, err
 			if debug {
 				log.Printf("input %q\n", string(line))
 			}
-			panic("!implemented")
+			// the steps are not parsed yet; keep the line for the caller
+			section.LandMovement = &LandMovement{
+				Line: bdup(line),
+			}
+			continue
+		}
+
+		if reClanStatus.Match(line) || reCourierStatus.Match(line) || reElementStatus.Match(line) || reFleetStatus.Match(line) || reGarrisonStatus.Match(line) || reTribeStatus.Match(line) {
+			if debug {
+				log.Printf("input %q\n", string(line))
+			}
+			// the status is not parsed yet; keep the line for the caller
+			section.Status = &Status{
+				Line: bdup(line),
+			}
+			continue
 		}
 
 		if reScout.Match(line) {
@@ -336,7 +365,9 @@ func Parse(source string, input []byte, quiet, verbose, debug bool) (Report, err
 				}
 				continue
 			}
-			log.Printf("scout %+v\n", s)
+			if verbose {
+				log.Printf("scout %+v\n", s)
+			}
 			continue
 		}
 
@@ -456,7 +487,9 @@ func Parse(source string, input []byte, quiet, verbose, debug bool) (Report, err
 	}
 
 	for _, section := range r.Sections {
-		log.Printf("%s: from %-9q to %-9q\n", r.Name, section.PreviousCoords, section.CurrentCoords)
+		if verbose {
+			log.Printf("%s: from %-9q to %-9q\n", r.Name, section.PreviousCoords, section.CurrentCoords)
+		}
 		if r.TurnNo == "" {
 			r.TurnNo = section.TurnNo
 		}
@@ -472,6 +505,11 @@ func Parse(source string, input []byte, quiet, verbose, debug bool) (Report, err
 	return r, nil
 }
 
+// bdup returns a copy of the slice.
+func bdup(b []byte) []byte {
+	return append([]byte{}, b...)
+}
+
 const (
 	CR = 0x0d // carriage return
 	LF = 0x0a // line feed
@@ -498,7 +536,7 @@ var (
 	reTribeScry    = regexp.MustCompile(`^\d{4}\sScry:`)
 
 	// CALM NE Fleet Movement:
-	reFleetMovement = regexp.MustCompile(`^(CALM|MILD|STRONG|GALE)\s[NS][EW]?\sFleet\sMovement:`)
+	reFleetMovement = regexp.MustCompile(`^(CALM|MILD|STRONG|GALE)\s([NS][EW]?)\sFleet\sMovement:`)
 
 	// Tribe Follows 0987e1
 	reTribeFollows = regexp.MustCompile(`^Tribe Follows\s`)
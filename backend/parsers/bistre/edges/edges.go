@@ -17,6 +17,8 @@ const (
 	Pass
 	River
 	StoneRoad
+	RuneRoad
+	DirtRoad
 )
 
 // MarshalJSON implements the json.Marshaler interface.
@@ -36,6 +38,11 @@ func (e *Edge_e) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// IsRoad returns true if the edge is a road.
+func (e Edge_e) IsRoad() bool {
+	return e == StoneRoad || e == RuneRoad || e == DirtRoad
+}
+
 // String implements the fmt.Stringer interface.
 func (e Edge_e) String() string {
 	if str, ok := EnumToString[e]; ok {
@@ -53,6 +60,8 @@ var (
 		Pass:      "Pass",
 		River:     "River",
 		StoneRoad: "Stone Road",
+		RuneRoad:  "Rune Road",
+		DirtRoad:  "Dirt Road",
 	}
 	// StringToEnum is a helper map for unmarshalling the enum
 	StringToEnum = map[string]Edge_e{
@@ -62,5 +71,7 @@ var (
 		"Pass":       Pass,
 		"River":      River,
 		"Stone Road": StoneRoad,
+		"Rune Road":  RuneRoad,
+		"Dirt Road":  DirtRoad,
 	}
 )
@@ -38,8 +38,18 @@ func (d Date_t) IsZero() bool {
 }
 
 type TurnInfo_t struct {
-	CurrentTurn Date_t
-	NextTurn    Date_t
+	CurrentTurn   Date_t
+	CurrentTurnNo int
+	NextTurn      Date_t
+	NextTurnNo    int
+	NextTurnDate  Date_t // from the report; the day the next turn is due
+}
+
+// turnNo_t is a turn and its number, e.g. "899-12 (#0)".
+type turnNo_t struct {
+	turn   Date_t
+	turnNo int
+	date   Date_t
 }
 
 func bdup(src []byte) []byte {
@@ -6206,16 +6216,16 @@ func (p *parser) callonTribeMovement1() (any, error) {
 }
 
 func (c *current) onTurnInfo1(cd, nt any) (any, error) {
+	ct := cd.(turnNo_t)
+	ti := TurnInfo_t{
+		CurrentTurn:   ct.turn,
+		CurrentTurnNo: ct.turnNo,
+	}
 	if nt != nil {
-		return TurnInfo_t{
-			CurrentTurn: cd.(Date_t),
-			NextTurn:    nt.(Date_t),
-		}, nil
+		nx := nt.(turnNo_t)
+		ti.NextTurn, ti.NextTurnNo, ti.NextTurnDate = nx.turn, nx.turnNo, nx.date
 	}
-
-	return TurnInfo_t{
-		CurrentTurn: cd.(Date_t),
-	}, nil
+	return ti, nil
 }
 
 func (p *parser) callonTurnInfo1() (any, error) {
@@ -6225,7 +6235,12 @@ func (p *parser) callonTurnInfo1() (any, error) {
 }
 
 func (c *current) onCurrentTurn1(cd any) (any, error) {
-	return cd, nil
+	text := string(c.text)
+	ct := turnNo_t{turn: cd.(Date_t)}
+	if _, err := fmt.Sscanf(text[strings.LastIndex(text, "(#"):], "(#%d)", &ct.turnNo); err != nil {
+		return nil, err
+	}
+	return ct, nil
 }
 
 func (p *parser) callonCurrentTurn1() (any, error) {
@@ -6235,7 +6250,12 @@ func (p *parser) callonCurrentTurn1() (any, error) {
 }
 
 func (c *current) onNextTurn1(nd any) (any, error) {
-	return nd, nil
+	text := string(c.text)
+	nt := turnNo_t{turn: nd.(Date_t)}
+	if _, err := fmt.Sscanf(text[strings.LastIndex(text, "(#"):], "(#%d),%d/%d/%d", &nt.turnNo, &nt.date.Day, &nt.date.Month, &nt.date.Year); err != nil {
+		return nil, err
+	}
+	return nt, nil
 }
 
 func (p *parser) callonNextTurn1() (any, error) {
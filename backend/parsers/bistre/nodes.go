@@ -150,7 +150,7 @@ func hexReportToNodes(hexReport []byte, debugNodes bool, experimentalUnitSplit b
 				tmp.addText(tmp.next)
 				tmp.next = tmp.next.next
 			}
-		} else if tmp.isStoneRoadEdge() {
+		} else if tmp.isRoadEdge() {
 			for tmp.next.isDirection() {
 				tmp.addText(tmp.next)
 				tmp.next = tmp.next.next
@@ -313,8 +313,8 @@ func (n *node) isRiverEdge() bool {
 	return n.hasPrefix("RIVER ")
 }
 
-func (n *node) isStoneRoadEdge() bool {
-	return n.hasPrefix("STONE ROAD ")
+func (n *node) isRoadEdge() bool {
+	return n != nil && IsRoads(n.text)
 }
 
 func (n *node) isUnitId() bool {
@@ -41,7 +41,12 @@ const (
 
 type ParseConfig struct {
 	Version semver.Version
-	Ignore  struct {
+	// Roads are the GM's road types for ambiguous roads. They are applied
+	// to the roads on status lines, since the status line is for the hex
+	// that the unit ends the turn in. Roads seen while moving are in hexes
+	// that aren't known until the moves are walked.
+	Roads  RoadOverrides_t
+	Ignore struct {
 		Scouts bool
 		Logged struct {
 			Scouts bool
@@ -133,6 +138,7 @@ func ParseInput(fid, tid string, input []byte, acceptLoneDash, debugParser, debu
 			}
 			moves = &Moves_t{TurnId: t.Id, UnitId: unitId, PreviousHex: location.PreviousHex, CurrentHex: location.CurrentHex}
 			t.UnitMoves[moves.UnitId] = moves
+			scriesLinePrefix = []byte(fmt.Sprintf("%s Scry: ", unitId))
 			statusLinePrefix = []byte(fmt.Sprintf("%s Status: ", unitId))
 		} else if rxGarrisonSection.Match(line) {
 			unitId = UnitId_t(line[9:15])
@@ -280,6 +286,11 @@ func ParseInput(fid, tid string, input []byte, acceptLoneDash, debugParser, debu
 			if err != nil {
 				return t, err
 			}
+			for _, move := range statusMoves {
+				if move.Report != nil {
+					cfg.Roads.Apply(moves.CurrentHex, move.Report.Roads)
+				}
+			}
 			if len(statusMoves) > 0 {
 				moves.Moves = append(moves.Moves, statusMoves...)
 			}
@@ -845,6 +856,11 @@ func parseMovementLine(fid, tid string, unitId UnitId_t, lineNo int, line []byte
 				return false
 			})
 		}
+		if len(move.Report.Roads) != 0 {
+			sort.Slice(move.Report.Roads, func(i, j int) bool {
+				return move.Report.Roads[i].Direction < move.Report.Roads[j].Direction
+			})
+		}
 		if len(move.Report.Resources) != 0 {
 			sort.Slice(move.Report.Resources, func(i, j int) bool {
 				return move.Report.Resources[i] < move.Report.Resources[j]
@@ -903,7 +919,14 @@ func parseMove(fid, tid string, unitId UnitId_t, lineNo, stepNo int, line []byte
 		}
 
 		var obj any
-		if obj, err = Parse("step", subStep, Entrypoint("Step")); err != nil {
+		if IsRoads(subStep) {
+			// roads are parsed by hand because the legacy text can be ambiguous
+			if obj, err = ParseRoads(subStep); err != nil {
+				log.Printf("%s: %s: %d: step %d: sub %d: %q\n", fid, unitId, lineNo, stepNo, subStepNo, subStep)
+				log.Printf("error: %v\n", err)
+				return nil, fmt.Errorf("error parsing step")
+			}
+		} else if obj, err = Parse("step", subStep, Entrypoint("Step")); err != nil {
 			if bytes.HasPrefix(subStep, []byte{'-'}) {
 				if len(subStep) == 1 {
 					// what do we do with lone dashes?
@@ -1048,6 +1071,18 @@ func parseMove(fid, tid string, unitId UnitId_t, lineNo, stepNo int, line []byte
 				Direction: v.Direction,
 				Terrain:   v.Terrain,
 			})
+		case []*Road_t:
+			if m.Result == results.Unknown {
+				log.Printf("%s: %s: %d: step %d: sub %d: %q\n", fid, unitId, lineNo, stepNo, subStepNo, subStep)
+				return nil, fmt.Errorf("roads forbidden at beginning of step")
+			}
+			for _, road := range v {
+				m.Report.MergeBorders(&Border_t{
+					Direction: road.Direction,
+					Edge:      road.Road,
+				})
+				m.Report.MergeRoads(road)
+			}
 		case resources.Resource_e:
 			if m.Result == results.Unknown {
 				log.Printf("%s: %s: %d: step %d: sub %d: %q\n", fid, unitId, lineNo, stepNo, subStepNo, subStep)
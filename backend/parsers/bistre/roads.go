@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package bistre
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/direction"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/edges"
+)
+
+// IsRoads returns true if the text starts with a road type.
+func IsRoads(text []byte) bool {
+	_, ok := roadType(text)
+	return ok
+}
+
+// ParseRoads parses the roads component of a hex report.
+//
+// The legacy generator walks the road mask in N NE SE S SW NW order. For each
+// direction, it prints "Type Road DIR" the first time a road type is used and
+// just " DIR" after that. That means a bare direction can belong to any road
+// type printed before it. We return the literal grouping (the bare direction
+// goes with the closest type to its left) and flag the road as ambiguous when
+// more than one type could own it.
+func ParseRoads(text []byte) ([]*Road_t, error) {
+	var roads []*Road_t
+	var seen []edges.Edge_e // road types in the order they were printed
+	var current edges.Edge_e
+	named := false // true if the next direction follows a road type
+	for fields := bytes.Fields(text); len(fields) != 0; {
+		if road, ok := roadType(bytes.Join(fields, []byte{' '})); ok {
+			current, named = road, true
+			if !slices.Contains(seen, road) {
+				seen = append(seen, road)
+			}
+			fields = fields[2:]
+			continue
+		}
+		d, ok := direction.StringToEnum[string(bytes.ToUpper(fields[0]))]
+		if !ok || d == direction.Unknown {
+			return nil, fmt.Errorf("roads: invalid direction %q", fields[0])
+		} else if current == edges.None {
+			return nil, fmt.Errorf("roads: direction %q before road type", fields[0])
+		}
+		for _, r := range roads {
+			if r.Direction == d {
+				return nil, fmt.Errorf("roads: duplicate direction %q", fields[0])
+			}
+		}
+		road := &Road_t{Direction: d, Road: current}
+		if !named && len(seen) > 1 {
+			road.Ambiguous = true
+			road.Alternatives = append([]edges.Edge_e{}, seen...)
+		}
+		roads = append(roads, road)
+		named = false
+		fields = fields[1:]
+	}
+	if len(roads) == 0 {
+		return nil, fmt.Errorf("roads: missing directions")
+	}
+	return roads, nil
+}
+
+// roadType returns the road type if the text starts with "Type Road".
+// The comparison is case-insensitive since the grammar is.
+func roadType(text []byte) (edges.Edge_e, bool) {
+	for _, road := range []edges.Edge_e{edges.DirtRoad, edges.RuneRoad, edges.StoneRoad} {
+		pfx := road.String()
+		if len(text) < len(pfx) || !bytes.EqualFold(text[:len(pfx)], []byte(pfx)) {
+			continue
+		} else if len(text) == len(pfx) || text[len(pfx)] == ' ' {
+			return road, true
+		}
+	}
+	return edges.None, false
+}
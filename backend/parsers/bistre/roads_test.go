@@ -0,0 +1,141 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package bistre_test
+
+import (
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/parsers/bistre"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/direction"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/edges"
+)
+
+func TestParseRoads(t *testing.T) {
+	for _, tc := range []struct {
+		id    int
+		input string
+		want  string
+	}{
+		{id: 1, input: "Stone Road N", want: "N-Stone Road"},
+		{id: 2, input: "Stone Road N NE SW", want: "N-Stone Road NE-Stone Road SW-Stone Road"},
+		{id: 3, input: "Rune Road N Dirt Road NE", want: "N-Rune Road NE-Dirt Road"},
+		{id: 4, input: "Rune Road N Dirt Road NE SW", want: "N-Rune Road NE-Dirt Road SW-Dirt Road?"},
+		{id: 5, input: "Rune Road N NE Dirt Road SE S Stone Road SW NW", want: "N-Rune Road NE-Rune Road SE-Dirt Road S-Dirt Road? SW-Stone Road NW-Stone Road?"},
+		{id: 6, input: "dirt road s", want: "S-Dirt Road"},
+	} {
+		roads, err := bistre.ParseRoads([]byte(tc.input))
+		if err != nil {
+			t.Errorf("%d: want nil, got %v\n", tc.id, err)
+			continue
+		}
+		var got string
+		for n, road := range roads {
+			if n > 0 {
+				got += " "
+			}
+			got += road.String()
+		}
+		if tc.want != got {
+			t.Errorf("%d: want %q, got %q\n", tc.id, tc.want, got)
+		}
+	}
+}
+
+func TestParseRoads_Alternatives(t *testing.T) {
+	roads, err := bistre.ParseRoads([]byte("Rune Road N Dirt Road NE SW"))
+	if err != nil {
+		t.Fatalf("want nil, got %v\n", err)
+	} else if len(roads) != 3 {
+		t.Fatalf("roads: want 3, got %d\n", len(roads))
+	}
+	sw := roads[2]
+	if len(sw.Alternatives) != 2 || sw.Alternatives[0] != edges.RuneRoad || sw.Alternatives[1] != edges.DirtRoad {
+		t.Errorf("alternatives: want [Rune Road Dirt Road], got %v\n", sw.Alternatives)
+	}
+
+	overrides := bistre.RoadOverrides_t{
+		{Hex: "AB 0102", Direction: direction.SouthWest}: edges.StoneRoad, // not an alternative
+		{Hex: "AB 0102", Direction: direction.North}:     edges.DirtRoad,  // not ambiguous
+	}
+	if resolved := overrides.Apply("AB 0102", roads); resolved != 0 {
+		t.Errorf("resolved: want 0, got %d\n", resolved)
+	}
+	if !sw.Ambiguous || roads[0].Road != edges.RuneRoad {
+		t.Errorf("override: want N-Rune Road SW-Dirt Road?, got %s %s\n", roads[0], sw)
+	}
+	overrides[bistre.RoadOverrideKey_t{Hex: "AB 0102", Direction: direction.SouthWest}] = edges.RuneRoad
+	if resolved := overrides.Apply("AB 0103", roads); resolved != 0 {
+		t.Errorf("other hex: want 0, got %d\n", resolved)
+	}
+	if resolved := overrides.Apply("AB 0102", roads); resolved != 1 {
+		t.Errorf("resolved: want 1, got %d\n", resolved)
+	}
+	if sw.Ambiguous || sw.Road != edges.RuneRoad {
+		t.Errorf("override: want SW-Rune Road, got %s\n", sw)
+	}
+}
+
+func TestParseRoads_Errors(t *testing.T) {
+	for _, input := range []string{
+		"Rune Road",
+		"Rune Road X",
+		"Rune Road N N",
+	} {
+		if _, err := bistre.ParseRoads([]byte(input)); err == nil {
+			t.Errorf("%q: want error, got nil\n", input)
+		}
+	}
+}
+
+func TestParseStatusLine_Roads(t *testing.T) {
+	line := []byte("0987 Status: PRAIRIE, Rune Road N Dirt Road NE SW, 0987")
+	moves, err := bistre.ParseStatusLine("test", "0901-01", "0987", 1, line, false, false, false, false)
+	if err != nil {
+		t.Fatalf("want nil, got %v\n", err)
+	} else if len(moves) != 1 {
+		t.Fatalf("moves: want 1, got %d\n", len(moves))
+	}
+	report := moves[0].Report
+	if len(report.Settlements) != 0 {
+		t.Errorf("settlements: want 0, got %v\n", report.Settlements)
+	}
+	if len(report.Roads) != 3 {
+		t.Fatalf("roads: want 3, got %d\n", len(report.Roads))
+	} else if !report.Roads[2].Ambiguous {
+		t.Errorf("roads: want SW ambiguous, got %s\n", report.Roads[2])
+	}
+}
+
+func TestParseInput_RoadOverrides(t *testing.T) {
+	input := []byte("Tribe 0987, , Current Hex = AB 0102, (Previous Hex = AB 0102)\n" +
+		"Current Turn 901-01 (#13), Winter, FINE\n" +
+		"0987 Status: PRAIRIE, Rune Road N Dirt Road NE SW, 0987\n")
+	cfg := bistre.ParseConfig{
+		Version: bistre.Version,
+		Roads: bistre.RoadOverrides_t{
+			{Hex: "AB 0102", Direction: direction.SouthWest}: edges.RuneRoad,
+		},
+	}
+	turn, err := bistre.ParseInput("test", "0901-01", input, false, false, false, false, false, false, false, false, cfg)
+	if err != nil {
+		t.Fatalf("want nil, got %v\n", err)
+	}
+	moves := turn.UnitMoves["0987"]
+	if moves == nil || len(moves.Moves) != 1 {
+		t.Fatalf("moves: want 1 status move\n")
+	}
+	roads := moves.Moves[0].Report.Roads
+	if len(roads) != 3 {
+		t.Fatalf("roads: want 3, got %d\n", len(roads))
+	}
+	var got string
+	for n, road := range roads {
+		if n > 0 {
+			got += " "
+		}
+		got += road.String()
+	}
+	if want := "N-Rune Road NE-Dirt Road SW-Rune Road"; got != want {
+		t.Errorf("roads: want %q, got %q\n", want, got)
+	}
+}
@@ -35,7 +35,7 @@ type Turn_t struct {
 	// They are added to the map when parsing and are forced to lower case.
 	SpecialNames map[string]*Special_t
 
-	Next, Prev *Turn_t
+	Next, Prev *Turn_t `json:"-"`
 }
 
 func (t *Turn_t) FromMayBeObscured() bool {
@@ -156,7 +156,7 @@ type Move_t struct {
 		FleetMoves bool
 		PriorMove  *Move_t
 		NextMove   *Move_t
-	}
+	} `json:"-"`
 }
 
 // Report_t represents the observations made by a unit.
@@ -170,6 +170,7 @@ type Report_t struct {
 	// permanent items in this hex
 	Terrain terrain.Terrain_e
 	Borders []*Border_t
+	Roads   []*Road_t
 
 	// transient items in this hex
 	Encounters  []*Encounter_t // other units in the hex
@@ -245,6 +246,20 @@ func (r *Report_t) MergeResources(rs resources.Resource_e) bool {
 	return true
 }
 
+// MergeRoads adds a new road to the list if the direction is not already in the list
+func (r *Report_t) MergeRoads(rd *Road_t) bool {
+	if rd == nil {
+		return false
+	}
+	for _, l := range r.Roads {
+		if l.Direction == rd.Direction {
+			return false
+		}
+	}
+	r.Roads = append(r.Roads, rd)
+	return true
+}
+
 // MergeSettlements adds a new settlement to the list if it's not already in the list
 func (r *Report_t) MergeSettlements(s *Settlement_t) bool {
 	if s == nil {
@@ -371,6 +386,68 @@ func (p *ProhibitedFrom_t) String() string {
 	return fmt.Sprintf("p(%s-%s)", p.Direction, p.Terrain)
 }
 
+// Road_t is a road leaving the hex in a single direction.
+//
+// The legacy report generator drops the road type for a direction when the
+// type has already been printed, so "Rune Road N Dirt Road NE SW" could mean
+// that SW is either a Rune or a Dirt road (see docs/BUGS.md). Road is always
+// the literal grouping from the text. When the text can't be decoded, Ambiguous
+// is set and Alternatives lists every road type the direction could have.
+type Road_t struct {
+	Direction    direction.Direction_e
+	Road         edges.Edge_e   // road type from the literal grouping
+	Ambiguous    bool           // true if the road type can't be decoded from the text
+	Alternatives []edges.Edge_e // possible road types, set only if ambiguous
+}
+
+func (r *Road_t) String() string {
+	if r == nil {
+		return ""
+	} else if r.Ambiguous {
+		return fmt.Sprintf("%s-%s?", r.Direction, r.Road)
+	}
+	return fmt.Sprintf("%s-%s", r.Direction, r.Road)
+}
+
+// Resolve sets the road type from an override, clearing the ambiguity flag.
+// It returns false if the road type isn't one of the alternatives.
+func (r *Road_t) Resolve(road edges.Edge_e) bool {
+	if !r.Ambiguous {
+		return r.Road == road
+	}
+	for _, alt := range r.Alternatives {
+		if alt == road {
+			r.Road, r.Ambiguous, r.Alternatives = road, false, nil
+			return true
+		}
+	}
+	return false
+}
+
+// RoadOverrideKey_t identifies a road on the map.
+type RoadOverrideKey_t struct {
+	Hex       string // grid coordinates, like "AB 0102"
+	Direction direction.Direction_e
+}
+
+// RoadOverrides_t is the table of road types that the GM has confirmed
+// for ambiguous roads.
+type RoadOverrides_t map[RoadOverrideKey_t]edges.Edge_e
+
+// Apply resolves the ambiguous roads in the hex using the overrides.
+// Overrides that aren't road types are ignored.
+// It returns the number of roads that were resolved.
+func (ro RoadOverrides_t) Apply(hex string, roads []*Road_t) (resolved int) {
+	for _, road := range roads {
+		if !road.Ambiguous {
+			continue
+		} else if override, ok := ro[RoadOverrideKey_t{Hex: hex, Direction: road.Direction}]; ok && override.IsRoad() && road.Resolve(override) {
+			resolved++
+		}
+	}
+	return resolved
+}
+
 // Scout_t represents a scout sent out by a unit.
 type Scout_t struct {
 	No     int // usually from 1..8
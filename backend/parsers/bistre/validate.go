@@ -0,0 +1,136 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package bistre
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// Violation_e is the rule that a report broke.
+type Violation_e string
+
+const (
+	// NoHeading means the report has no unit sections, or the first
+	// section is not for the clan's tribe.
+	NoHeading Violation_e = "heading"
+	// NotInClan means a unit section is for a unit in another clan.
+	NotInClan Violation_e = "clan"
+	// TurnMismatch means a "Current Turn" line is for a different turn
+	// than the first one, or can't be parsed.
+	TurnMismatch Violation_e = "turn"
+	// FollowsMissing means a unit follows a unit that has no section.
+	FollowsMissing Violation_e = "follows"
+)
+
+// Violation_t is a problem found by ValidateReport.
+type Violation_t struct {
+	Rule    Violation_e `json:"rule"`
+	LineNo  int         `json:"line"`
+	UnitId  UnitId_t    `json:"unitId,omitempty"`
+	Message string      `json:"message"`
+}
+
+func (v *Violation_t) String() string {
+	if v.UnitId == "" {
+		return fmt.Sprintf("%d: %s: %s", v.LineNo, v.Rule, v.Message)
+	}
+	return fmt.Sprintf("%d: %s: %s: %s", v.LineNo, v.Rule, v.UnitId, v.Message)
+}
+
+// ValidateReport checks the rules that the turn_reports table relies on.
+// Every section in a report must be for the same turn and every unit
+// must belong to the clan from the heading (the first section). Units
+// can only follow units that have a section in the report.
+//
+// It returns the clan from the heading and the violations, in line order.
+// The clan is empty if the report has no unit sections.
+func ValidateReport(fid string, input []byte) (UnitId_t, []*Violation_t) {
+	type follows struct {
+		lineNo  int
+		unitId  UnitId_t
+		follows UnitId_t
+	}
+	var violations []*Violation_t
+	var clan, unitId UnitId_t
+	var turnId string
+	var followers []follows
+	units := map[UnitId_t]bool{}
+
+	// reports saved on Windows may start with a byte order mark and end lines with CR-LF
+	input = bytes.TrimPrefix(input, []byte("\xef\xbb\xbf"))
+	input = bytes.ReplaceAll(input, []byte{'\r', '\n'}, []byte{'\n'})
+	input = bytes.ReplaceAll(input, []byte{'\r'}, []byte{'\n'})
+
+	for n, line := range bytes.Split(input, []byte("\n")) {
+		lineNo := n + 1
+		if id, ok := sectionUnitId(line); ok {
+			unitId = id
+			units[unitId] = true
+			if clan == "" {
+				if len(unitId) == 4 {
+					clan = unitId.Parent()
+				} else {
+					clan = unitId.Parent().Parent()
+				}
+				if unitId != clan {
+					violations = append(violations, &Violation_t{Rule: NoHeading, LineNo: lineNo, UnitId: unitId, Message: fmt.Sprintf("first section should be for tribe %s", clan)})
+				}
+			} else if !unitId.InClan(clan) {
+				violations = append(violations, &Violation_t{Rule: NotInClan, LineNo: lineNo, UnitId: unitId, Message: fmt.Sprintf("unit is not in clan %s", clan)})
+			}
+		} else if unitId == "" {
+			continue
+		} else if bytes.HasPrefix(line, []byte("Current Turn ")) {
+			va, err := Parse(fid, line, Entrypoint("TurnInfo"))
+			turnInfo, ok := va.(TurnInfo_t)
+			if err != nil || !ok {
+				violations = append(violations, &Violation_t{Rule: TurnMismatch, LineNo: lineNo, UnitId: unitId, Message: "can't parse current turn"})
+				continue
+			}
+			id := fmt.Sprintf("%04d-%02d", turnInfo.CurrentTurn.Year, turnInfo.CurrentTurn.Month)
+			if turnId == "" {
+				turnId = id
+			} else if id != turnId {
+				violations = append(violations, &Violation_t{Rule: TurnMismatch, LineNo: lineNo, UnitId: unitId, Message: fmt.Sprintf("current turn is %s, report is for %s", id, turnId)})
+			}
+		} else if bytes.HasPrefix(line, []byte("Tribe Follows ")) {
+			va, err := Parse(fid, line, Entrypoint("TribeFollows"))
+			if mt, ok := va.(Movement_t); err == nil && ok {
+				followers = append(followers, follows{lineNo: lineNo, unitId: unitId, follows: mt.Follows})
+			}
+		}
+	}
+
+	if clan == "" {
+		return "", []*Violation_t{{Rule: NoHeading, LineNo: 1, Message: "no unit sections"}}
+	}
+
+	// followed units can be anywhere in the report, so check them last
+	for _, f := range followers {
+		if !units[f.follows] {
+			violations = append(violations, &Violation_t{Rule: FollowsMissing, LineNo: f.lineNo, UnitId: f.unitId, Message: fmt.Sprintf("follows %s, which is not in the report", f.follows)})
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].LineNo < violations[j].LineNo
+	})
+
+	return clan, violations
+}
+
+// sectionUnitId returns the unit id if the line starts a unit section.
+func sectionUnitId(line []byte) (UnitId_t, bool) {
+	switch {
+	case rxCourierSection.Match(line), rxElementSection.Match(line):
+		return UnitId_t(line[8:14]), true
+	case rxFleetSection.Match(line):
+		return UnitId_t(line[6:12]), true
+	case rxGarrisonSection.Match(line):
+		return UnitId_t(line[9:15]), true
+	case rxTribeSection.Match(line):
+		return UnitId_t(line[6:10]), true
+	}
+	return "", false
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package bistre_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/parsers/bistre"
+)
+
+func TestValidateReport(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		clan  bistre.UnitId_t
+		want  []string
+	}{
+		{
+			name: "valid",
+			input: `Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)
+Current Turn 899-12 (#0), Winter, FINE
+Tribe 1987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)
+Current Turn 899-12 (#0), Winter, FINE
+Tribe Follows 1987e1
+Element 1987e1, , Current Hex = QQ 1202, (Previous Hex = QQ 1203)
+Current Turn 899-12 (#0), Winter, FINE
+`,
+			clan: "0987",
+		},
+		{
+			name: "violations",
+			input: `Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)
+Current Turn 899-12 (#0), Winter, FINE
+Tribe Follows 0987c1
+Element 0138e1, , Current Hex = QQ 1202, (Previous Hex = QQ 1203)
+Current Turn 900-01 (#1), Spring, FINE
+`,
+			clan: "0987",
+			want: []string{
+				"3: follows: 0987: follows 0987c1, which is not in the report",
+				"4: clan: 0138e1: unit is not in clan 0987",
+				"5: turn: 0138e1: current turn is 0900-01, report is for 0899-12",
+			},
+		},
+		{
+			name: "heading",
+			input: `Courier 0987c1, , Current Hex = QQ 1202, (Previous Hex = QQ 1203)
+Current Turn 899-12 (#0), Winter, FINE
+`,
+			clan: "0987",
+			want: []string{"1: heading: 0987c1: first section should be for tribe 0987"},
+		},
+		{
+			name:  "crlf",
+			input: "Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)\r\nCurrent Turn 899-12 (#0), Winter, FINE\r\nElement 0987e1, , Current Hex = QQ 1202, (Previous Hex = QQ 1203)\r\nCurrent Turn 899-12 (#0), Winter, FINE\r\n",
+			clan:  "0987",
+		},
+		{
+			name:  "bom",
+			input: "\ufeffTribe 0987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)\nCurrent Turn 899-12 (#0), Winter, FINE\nElement 0987e1, , Current Hex = QQ 1202, (Previous Hex = QQ 1203)\nCurrent Turn 899-12 (#0), Winter, FINE\n",
+			clan:  "0987",
+		},
+		{
+			name:  "empty",
+			input: "Humans 1000\n",
+			want:  []string{"1: heading: no unit sections"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			clan, violations := bistre.ValidateReport(tc.name, []byte(tc.input))
+			if clan != tc.clan {
+				t.Errorf("clan: want %q, got %q", tc.clan, clan)
+			}
+			var got []string
+			for _, v := range violations {
+				got = append(got, v.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Errorf("violations:\nwant %q\n got %q", tc.want, got)
+			}
+		})
+	}
+}
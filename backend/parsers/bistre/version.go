@@ -0,0 +1,15 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package bistre
+
+import "github.com/maloquacious/semver"
+
+// Version is the version of the parser.
+//
+// Bump it whenever a change to the parser changes the Turn_t it returns.
+// Stored parses made by an older version are re-parsed on upgrade.
+var Version = semver.Version{
+	Major: 1,
+	Minor: 0,
+	Patch: 1,
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package parsers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/maloquacious/semver"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre"
+)
+
+// Format is the version of the turn report format.
+// The values match the MIME types in the document_types table.
+type Format string
+
+const (
+	TN30 Format = "tn-3.0"
+	TN31 Format = "tn-3.1"
+
+	// DefaultFormat is used when the caller doesn't know the format of a report.
+	DefaultFormat = TN31
+)
+
+func (f Format) IsValid() bool {
+	switch f {
+	case TN30, TN31:
+		return true
+	}
+	return false
+}
+
+// ContentType returns the MIME type for the format.
+func (f Format) ContentType() string {
+	return "application/" + string(f)
+}
+
+// ParseFunc parses the text of a turn report.
+// Name is the name of the report (used in error messages).
+// TurnId is the turn the report is for (e.g. "0899-12").
+type ParseFunc func(name, turnId string, input []byte) (any, error)
+
+// Dialect is a parser for one or more turn report formats.
+//
+// The Name and Version are recorded with every stored parse so that
+// we can find parses that were made by an older version of the parser.
+type Dialect struct {
+	Name    string
+	Version semver.Version
+	Parse   ParseFunc
+}
+
+// Result is the output of a dialect along with the metadata
+// we need to record which parser produced it.
+type Result struct {
+	Format  Format
+	Game    string
+	Parser  string
+	Version semver.Version
+	Output  any
+}
+
+// Registry maps a report format and game to the dialect that parses it.
+//
+// Dialects registered for a specific game take precedence over dialects
+// registered for all games (game code "").
+type Registry struct {
+	sync.RWMutex
+	dialects map[dialectKey]*Dialect
+}
+
+type dialectKey struct {
+	format Format
+	game   string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{dialects: map[dialectKey]*Dialect{}}
+}
+
+// Register adds a dialect to the registry, replacing any dialect
+// already registered for the format and game.
+// Use game "" to register the dialect for all games.
+func (r *Registry) Register(format Format, game string, d *Dialect) error {
+	if !format.IsValid() {
+		return fmt.Errorf("%q: %w", format, ErrUnknownFormat)
+	} else if d == nil || d.Name == "" || d.Parse == nil {
+		return ErrInvalidDialect
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.dialects[dialectKey{format: format, game: strings.ToLower(game)}] = d
+	return nil
+}
+
+// Lookup returns the dialect for the format and game.
+// Falls back to the dialect registered for all games.
+func (r *Registry) Lookup(format Format, game string) (*Dialect, error) {
+	r.RLock()
+	defer r.RUnlock()
+	if d, ok := r.dialects[dialectKey{format: format, game: strings.ToLower(game)}]; ok {
+		return d, nil
+	} else if d, ok = r.dialects[dialectKey{format: format}]; ok {
+		return d, nil
+	}
+	return nil, fmt.Errorf("%s: %q: %w", format, game, ErrNoDialect)
+}
+
+// Dialects returns the registered dialects, sorted by name and version.
+// A dialect registered for several formats is returned once.
+func (r *Registry) Dialects() []*Dialect {
+	r.RLock()
+	defer r.RUnlock()
+	seen := map[*Dialect]bool{}
+	var list []*Dialect
+	for _, d := range r.dialects {
+		if !seen[d] {
+			seen[d] = true
+			list = append(list, d)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Name != list[j].Name {
+			return list[i].Name < list[j].Name
+		}
+		return list[i].Version.Less(list[j].Version)
+	})
+	return list
+}
+
+// Parse is the single entry point for parsing turn reports.
+// It picks the dialect for the format and game and runs it.
+func (r *Registry) Parse(format Format, game, name, turnId string, input []byte) (*Result, error) {
+	d, err := r.Lookup(format, game)
+	if err != nil {
+		return nil, err
+	}
+	output, err := d.Parse(name, turnId, input)
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		Format:  format,
+		Game:    game,
+		Parser:  d.Name,
+		Version: d.Version,
+		Output:  output,
+	}, nil
+}
+
+var (
+	defaultRegistry     *Registry
+	defaultRegistryOnce sync.Once
+)
+
+// DefaultRegistry returns the registry with the production dialects.
+func DefaultRegistry() *Registry {
+	defaultRegistryOnce.Do(func() {
+		defaultRegistry = NewRegistry()
+		for _, format := range []Format{TN30, TN31} {
+			if err := defaultRegistry.Register(format, "", BistreDialect); err != nil {
+				panic(fmt.Sprintf("assert(register bistre %s: %v)", format, err))
+			}
+		}
+	})
+	return defaultRegistry
+}
+
+// BistreDialect is the production parser.
+// It returns a *bistre.Turn_t.
+var BistreDialect = &Dialect{
+	Name:    "bistre",
+	Version: bistre.Version,
+	Parse: func(name, turnId string, input []byte) (any, error) {
+		return bistre.ParseInput(name, turnId, input, false, false, false, false, false, false, false, false, bistre.ParseConfig{Version: bistre.Version})
+	},
+}
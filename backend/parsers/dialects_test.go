@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package parsers_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/maloquacious/semver"
+	"github.com/playbymail/ottoapp/backend/parsers"
+)
+
+func TestRegistry_Lookup(t *testing.T) {
+	newDialect := func(name string) *parsers.Dialect {
+		return &parsers.Dialect{
+			Name:    name,
+			Version: semver.Version{Major: 1},
+			Parse: func(_, _ string, input []byte) (any, error) {
+				return name + ":" + string(input), nil
+			},
+		}
+	}
+	r := parsers.NewRegistry()
+	if err := r.Register(parsers.TN30, "", newDialect("all")); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := r.Register(parsers.TN30, "0301", newDialect("game")); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := r.Register(parsers.Format("tn-9.9"), "", newDialect("bad")); !errors.Is(err, parsers.ErrUnknownFormat) {
+		t.Errorf("register unknown format: want %v, got %v", parsers.ErrUnknownFormat, err)
+	}
+
+	for _, tc := range []struct {
+		game string
+		want string
+	}{
+		{game: "0301", want: "game"},
+		{game: "0300", want: "all"},
+		{game: "", want: "all"},
+	} {
+		result, err := r.Parse(parsers.TN30, tc.game, "test", "0899-12", []byte("input"))
+		if err != nil {
+			t.Errorf("%q: parse: %v", tc.game, err)
+			continue
+		}
+		if result.Parser != tc.want {
+			t.Errorf("%q: parser: want %q, got %q", tc.game, tc.want, result.Parser)
+		}
+		if got := result.Output.(string); got != tc.want+":input" {
+			t.Errorf("%q: output: want %q, got %q", tc.game, tc.want+":input", got)
+		}
+	}
+
+	if _, err := r.Lookup(parsers.TN31, "0301"); !errors.Is(err, parsers.ErrNoDialect) {
+		t.Errorf("lookup tn-3.1: want %v, got %v", parsers.ErrNoDialect, err)
+	}
+	if got := len(r.Dialects()); got != 2 {
+		t.Errorf("dialects: want 2, got %d", got)
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	for _, format := range []parsers.Format{parsers.TN30, parsers.TN31} {
+		d, err := parsers.DefaultRegistry().Lookup(format, "0301")
+		if err != nil {
+			t.Fatalf("%s: lookup: %v", format, err)
+		}
+		if d.Name != "bistre" {
+			t.Errorf("%s: want bistre, got %q", format, d.Name)
+		}
+	}
+}
@@ -13,3 +13,9 @@ const (
 	ErrNotAWordDocument = Error("not a word document")
 	ErrNotATurnReport   = Error("not a turn report")
 )
+
+const (
+	ErrInvalidDialect = Error("invalid dialect")
+	ErrNoDialect      = Error("no dialect for format")
+	ErrUnknownFormat  = Error("unknown report format")
+)
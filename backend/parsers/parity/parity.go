@@ -0,0 +1,413 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+// Package parity compares the output of the azul and bistre parsers.
+//
+// Both parsers are run over the same report text. The results are
+// normalized into a domains.TurnReport and compared unit by unit and
+// step by step. Steps are compared on their direction, outcome, terrain,
+// borders, and encounters.
+//
+// Azul splits the movement line into steps but doesn't parse them, so
+// its steps are decoded here from the text. The decoder only knows the
+// common forms; anything else is left unknown and will show up as a
+// difference. Terrain and direction codes are looked up in the bistre
+// tables, which are the codes from the report, not parser output.
+package parity
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/parsers/azul"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/edges"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/norm"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/results"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/terrain"
+)
+
+// Diff is a single difference between the parsers.
+// Step is zero for differences in the unit section.
+type Diff struct {
+	UnitId domains.UnitId
+	Step   int
+	Field  string
+	Azul   string
+	Bistre string
+}
+
+func (d Diff) String() string {
+	if d.UnitId == "" {
+		return fmt.Sprintf("%s: azul %q: bistre %q", d.Field, d.Azul, d.Bistre)
+	} else if d.Step == 0 {
+		return fmt.Sprintf("%s: %s: azul %q: bistre %q", d.UnitId, d.Field, d.Azul, d.Bistre)
+	}
+	return fmt.Sprintf("%s: step %d: %s: azul %q: bistre %q", d.UnitId, d.Step, d.Field, d.Azul, d.Bistre)
+}
+
+// Result is the outcome of checking one report.
+type Result struct {
+	Name      string
+	Azul      *domains.TurnReport // nil if azul failed
+	Bistre    *domains.TurnReport // nil if bistre failed
+	AzulErr   error
+	BistreErr error
+	Diffs     []Diff
+}
+
+// Ok returns true if both parsers succeeded and agree.
+func (r *Result) Ok() bool {
+	return r.AzulErr == nil && r.BistreErr == nil && len(r.Diffs) == 0
+}
+
+// Check runs both parsers over the text of a report and compares the results.
+// TurnId is the turn the report is for (e.g. "0899-12").
+// Panics in either parser are recovered and reported as errors.
+func Check(name, turnId string, text []byte) *Result {
+	r := &Result{Name: name}
+
+	func() {
+		defer func() {
+			if p := recover(); p != nil {
+				r.AzulErr = fmt.Errorf("azul: panic: %v", p)
+			}
+		}()
+		rpt, err := azul.ParseText(name, text, true, false, false)
+		if err != nil {
+			r.AzulErr = err
+			return
+		}
+		r.Azul = FromAzul(rpt)
+	}()
+
+	func() {
+		defer func() {
+			if p := recover(); p != nil {
+				r.BistreErr = fmt.Errorf("bistre: panic: %v", p)
+			}
+		}()
+		turn, err := bistre.ParseInput(name, turnId, text, false, false, false, false, false, false, false, false, bistre.ParseConfig{Version: bistre.Version})
+		if err != nil {
+			r.BistreErr = err
+			return
+		}
+		r.Bistre = FromBistre(turn)
+	}()
+
+	if r.Azul != nil && r.Bistre != nil {
+		r.Diffs = Compare(r.Azul, r.Bistre)
+	}
+	return r
+}
+
+// FromAzul normalizes the azul report.
+func FromAzul(rpt azul.Report) *domains.TurnReport {
+	tr := &domains.TurnReport{Turn: domains.YearMonth(normalizeTurn(rpt.TurnNo))}
+	for _, section := range rpt.Sections {
+		us := &domains.UnitSection{
+			UnitId:           domains.UnitId(section.UnitId),
+			StartingLocation: &domains.Location{Hex: section.PreviousCoords},
+			CurrentLocation:  &domains.Location{Hex: section.CurrentCoords},
+		}
+		if section.GoesTo != nil {
+			us.GoesTo = &domains.Location{Hex: section.GoesTo.Coords}
+		}
+		if section.Follows != nil {
+			us.Follows = domains.UnitId(section.Follows.UnitId)
+		}
+		var line []byte
+		if section.LandMovement != nil {
+			line = section.LandMovement.Line
+		} else if section.WaterMovement != nil {
+			line = section.WaterMovement.Line
+		}
+		if line != nil {
+			us.MovementReport = &domains.MovementReport{UnitId: us.UnitId}
+			// steps start after the "Movement: Move" keywords
+			if _, after, ok := bytes.Cut(line, []byte(": Move")); ok {
+				after = bytes.TrimSpace(bytes.TrimRight(after, " \t\\,"))
+				if len(after) != 0 {
+					for _, text := range bytes.Split(after, []byte{'\\'}) {
+						us.MovementReport.Steps = append(us.MovementReport.Steps, decodeStep(normalizeStep(text)))
+					}
+				}
+			}
+		}
+		tr.UnitSections = append(tr.UnitSections, us)
+	}
+	return tr
+}
+
+// FromBistre normalizes the bistre turn.
+func FromBistre(turn *bistre.Turn_t) *domains.TurnReport {
+	tr := &domains.TurnReport{Turn: domains.YearMonth(turn.Id)}
+	var unitIds []bistre.UnitId_t
+	for unitId := range turn.UnitMoves {
+		unitIds = append(unitIds, unitId)
+	}
+	sort.Slice(unitIds, func(i, j int) bool {
+		return unitIds[i] < unitIds[j]
+	})
+	for _, unitId := range unitIds {
+		moves := turn.UnitMoves[unitId]
+		us := &domains.UnitSection{
+			UnitId:           domains.UnitId(unitId),
+			StartingLocation: &domains.Location{Hex: moves.PreviousHex},
+			CurrentLocation:  &domains.Location{Hex: moves.CurrentHex},
+			Follows:          domains.UnitId(moves.Follows),
+		}
+		if moves.GoesTo != "" {
+			us.GoesTo = &domains.Location{Hex: moves.GoesTo}
+		}
+		for _, move := range moves.Moves {
+			if move.Still || move.Follows != "" || move.GoesTo != "" {
+				// status lines, follows, and goes to are not movement steps
+				continue
+			}
+			if us.MovementReport == nil {
+				us.MovementReport = &domains.MovementReport{UnitId: us.UnitId}
+			}
+			us.MovementReport.Steps = append(us.MovementReport.Steps, bistreStep(move))
+		}
+		tr.UnitSections = append(tr.UnitSections, us)
+	}
+	return tr
+}
+
+// Compare returns the differences between the reports.
+func Compare(a, b *domains.TurnReport) (diffs []Diff) {
+	if a.Turn != b.Turn {
+		diffs = append(diffs, Diff{Field: "turn", Azul: string(a.Turn), Bistre: string(b.Turn)})
+	}
+
+	units := map[domains.UnitId][2]*domains.UnitSection{}
+	for _, us := range a.UnitSections {
+		pair := units[us.UnitId]
+		pair[0] = us
+		units[us.UnitId] = pair
+	}
+	for _, us := range b.UnitSections {
+		pair := units[us.UnitId]
+		pair[1] = us
+		units[us.UnitId] = pair
+	}
+	var unitIds []domains.UnitId
+	for unitId := range units {
+		unitIds = append(unitIds, unitId)
+	}
+	sort.Slice(unitIds, func(i, j int) bool {
+		return unitIds[i] < unitIds[j]
+	})
+
+	for _, unitId := range unitIds {
+		ua, ub := units[unitId][0], units[unitId][1]
+		if ua == nil {
+			diffs = append(diffs, Diff{UnitId: unitId, Field: "unit", Azul: "missing", Bistre: "present"})
+			continue
+		} else if ub == nil {
+			diffs = append(diffs, Diff{UnitId: unitId, Field: "unit", Azul: "present", Bistre: "missing"})
+			continue
+		}
+		compare := func(step int, field, va, vb string) {
+			if va != vb {
+				diffs = append(diffs, Diff{UnitId: unitId, Step: step, Field: field, Azul: va, Bistre: vb})
+			}
+		}
+		compare(0, "starting location", hexOf(ua.StartingLocation), hexOf(ub.StartingLocation))
+		compare(0, "current location", hexOf(ua.CurrentLocation), hexOf(ub.CurrentLocation))
+		compare(0, "goes to", hexOf(ua.GoesTo), hexOf(ub.GoesTo))
+		compare(0, "follows", string(ua.Follows), string(ub.Follows))
+
+		sa, sb := stepsOf(ua), stepsOf(ub)
+		compare(0, "steps", strconv.Itoa(len(sa)), strconv.Itoa(len(sb)))
+		for n := 0; n < len(sa) && n < len(sb); n++ {
+			a, b := sa[n], sb[n]
+			compare(n+1, "outcome", a.Outcome.String(), b.Outcome.String())
+			compare(n+1, "direction", directionOf(a), directionOf(b))
+			ra, rb := resultOf(a), resultOf(b)
+			compare(n+1, "terrain", terrainOf(ra), terrainOf(rb))
+			compare(n+1, "borders", bordersOf(ra), bordersOf(rb))
+			compare(n+1, "encounters", encountersOf(ra), encountersOf(rb))
+		}
+	}
+
+	return diffs
+}
+
+func hexOf(l *domains.Location) string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(l.Hex), " ")
+}
+
+func stepsOf(us *domains.UnitSection) []*domains.Step {
+	if us.MovementReport == nil {
+		return nil
+	}
+	return us.MovementReport.Steps
+}
+
+func directionOf(step *domains.Step) string {
+	if step.Outcome == domains.UnknownOutcome {
+		return ""
+	}
+	return step.Direction.String()
+}
+
+func resultOf(step *domains.Step) *domains.Result {
+	if len(step.Results) == 0 {
+		return &domains.Result{}
+	}
+	return step.Results[0]
+}
+
+func terrainOf(r *domains.Result) string {
+	return terrain.Terrain_e(r.Terrain).String()
+}
+
+// bordersOf returns the borders in direction order, like "NE River, S Ford River".
+func bordersOf(r *domains.Result) string {
+	var list []string
+	for _, d := range domains.Directions {
+		var names []string
+		for _, e := range r.Borders[d] {
+			names = append(names, e.String())
+		}
+		if len(names) != 0 {
+			sort.Strings(names)
+			list = append(list, d.String()+" "+strings.Join(names, " "))
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
+// encountersOf returns the sorted unit ids of the encounters.
+func encountersOf(r *domains.Result) string {
+	var list []string
+	for _, thing := range r.Things {
+		if thing.Units != "" {
+			list = append(list, string(thing.Units))
+		}
+	}
+	sort.Strings(list)
+	return strings.Join(list, " ")
+}
+
+// bistreStep maps a bistre move to a step.
+func bistreStep(move *bistre.Move_t) *domains.Step {
+	step := &domains.Step{Text: normalizeStep(move.Line)}
+	switch move.Result {
+	case results.Succeeded:
+		step.Outcome = domains.Succeeded
+	case results.Blocked, results.ExhaustedMovementPoints, results.Failed, results.Prohibited:
+		step.Outcome = domains.Failed
+	}
+	step.Direction = domains.StringToDirection[move.Advance.String()]
+	result := &domains.Result{Observation: domains.UnitInLocation}
+	if move.Report != nil {
+		if step.Outcome == domains.Succeeded {
+			result.Terrain = domains.Terrain(move.Report.Terrain)
+		}
+		for _, border := range move.Report.Borders {
+			if edge, ok := domainEdges[border.Edge]; ok {
+				addBorder(result, domains.StringToDirection[border.Direction.String()], edge)
+			}
+		}
+		for _, encounter := range move.Report.Encounters {
+			result.Things = append(result.Things, &domains.Thing{Units: domains.UnitId(encounter.UnitId)})
+		}
+	}
+	step.Results = append(step.Results, result)
+	return step
+}
+
+var (
+	// the forms of a step that azul's text is decoded from
+	reStepMoved  = regexp.MustCompile(`^(N|NE|SE|S|SW|NW)-([A-Z]+)$`)
+	reStepFailed = regexp.MustCompile(`\bto (N|NE|SE|S|SW|NW)\b`)
+	reStepEdges  = regexp.MustCompile(`^(Canal|Ford|Pass|River)((?: (?:N|NE|SE|S|SW|NW))+)$`)
+	reStepUnit   = regexp.MustCompile(`^\d{4}(?:[cefg][1-9])?$`)
+
+	// the edges that are borders; roads and neighbors aren't compared
+	domainEdges = map[edges.Edge_e]domains.Edge{
+		edges.Canal: domains.Canal,
+		edges.Ford:  domains.Ford,
+		edges.Pass:  domains.Pass,
+		edges.River: domains.River,
+	}
+)
+
+// decodeStep decodes the normalized text of a step from azul. The first
+// component is the move ("NE-PR" or a reason it failed); the rest are
+// borders, encounters, and other finds, which are skipped.
+func decodeStep(text string) *domains.Step {
+	step := &domains.Step{Text: text}
+	result := &domains.Result{Observation: domains.UnitInLocation}
+	for n, component := range strings.Split(text, ",") {
+		component = strings.TrimSpace(component)
+		if n == 0 {
+			if m := reStepMoved.FindStringSubmatch(component); m != nil {
+				if code, ok := terrain.StringToTerrain(m[2]); ok {
+					step.Outcome, step.Direction = domains.Succeeded, domains.StringToDirection[m[1]]
+					result.Terrain = domains.Terrain(code)
+					continue
+				}
+			} else if m := reStepFailed.FindStringSubmatch(component); m != nil {
+				step.Outcome, step.Direction = domains.Failed, domains.StringToDirection[m[1]]
+				continue
+			}
+		}
+		if m := reStepEdges.FindStringSubmatch(component); m != nil {
+			edge := domains.StringToEdge[m[1]]
+			for _, d := range strings.Fields(m[2]) {
+				addBorder(result, domains.StringToDirection[d], edge)
+			}
+		} else if reStepUnit.MatchString(component) {
+			result.Things = append(result.Things, &domains.Thing{Units: domains.UnitId(component)})
+		}
+	}
+	step.Results = append(step.Results, result)
+	return step
+}
+
+func addBorder(r *domains.Result, d domains.Direction, e domains.Edge) {
+	if r.Borders == nil {
+		r.Borders = map[domains.Direction][]domains.Edge{}
+	}
+	for _, have := range r.Borders[d] {
+		if have == e {
+			return
+		}
+	}
+	r.Borders[d] = append(r.Borders[d], e)
+}
+
+// normalizeStep applies the bistre normalizer and collapses white space
+// so that only differences in the content of the steps are reported.
+func normalizeStep(text []byte) string {
+	text = bytes.TrimSpace(bytes.TrimRight(bytes.TrimSpace(text), ","))
+	return strings.Join(strings.Fields(string(norm.NormalizeLine(text))), " ")
+}
+
+// normalizeTurn converts "899-12" to "0899-12".
+func normalizeTurn(turnNo string) string {
+	year, month, ok := strings.Cut(turnNo, "-")
+	if !ok {
+		return turnNo
+	}
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return turnNo
+	}
+	m, err := strconv.Atoi(month)
+	if err != nil {
+		return turnNo
+	}
+	return fmt.Sprintf("%04d-%02d", y, m)
+}
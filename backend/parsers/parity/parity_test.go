@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package parity_test
+
+import (
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/parsers/azul"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre"
+	"github.com/playbymail/ottoapp/backend/parsers/parity"
+)
+
+const (
+	name   = "0301.0899-12.0987.report.txt"
+	header = "Tribe 0987, , Current Hex = OO 0202, (Previous Hex = OO 0201)\n" +
+		"Current Turn 899-12 (#0), Winter, FINE\tNext Turn 900-01 (#1), 28/11/2025\n"
+)
+
+func TestCheck(t *testing.T) {
+	input := []byte(header +
+		"Tribe Movement: Move NE-PR,  River S, 0988\\SE-GH\\Can't Move on Ocean to N of HEX\n" +
+		"0987 Status: GRASSY HILLS, 0987\n")
+	result := parity.Check(name, "0899-12", input)
+	if result.AzulErr != nil {
+		t.Fatalf("azul: %v", result.AzulErr)
+	} else if result.BistreErr != nil {
+		t.Fatalf("bistre: %v", result.BistreErr)
+	}
+	for _, d := range result.Diffs {
+		t.Errorf("diff: %s", d)
+	}
+	steps := result.Bistre.UnitSections[0].MovementReport.Steps
+	if len(steps) != 3 {
+		t.Fatalf("steps: want 3, got %d", len(steps))
+	}
+	if r := steps[0].Results[0]; len(r.Borders[domains.South]) != 1 || len(r.Things) != 1 {
+		t.Errorf("step 1: want a border and an encounter, got %v %v", r.Borders, r.Things)
+	}
+	if steps[2].Outcome != domains.Failed || steps[2].Direction != domains.North {
+		t.Errorf("step 3: want failed N, got %s %s", steps[2].Outcome, steps[2].Direction)
+	}
+}
+
+// TestCompare runs each parser over a report that differs from the other
+// on the terrain of one step and expects exactly that difference.
+func TestCompare(t *testing.T) {
+	rpt, err := azul.ParseText(name, []byte(header+
+		"Tribe Movement: Move NE-PR,  River S\\SE-GH\n"+
+		"0987 Status: GRASSY HILLS, 0987\n"), true, false, false)
+	if err != nil {
+		t.Fatalf("azul: %v", err)
+	}
+	turn, err := bistre.ParseInput(name, "0899-12", []byte(header+
+		"Tribe Movement: Move NE-PR,  River S\\SE-PR\n"+
+		"0987 Status: GRASSY HILLS, 0987\n"), false, false, false, false, false, false, false, false, bistre.ParseConfig{Version: bistre.Version})
+	if err != nil {
+		t.Fatalf("bistre: %v", err)
+	}
+	want := parity.Diff{UnitId: "0987", Step: 2, Field: "terrain", Azul: "GH", Bistre: "PR"}
+	got := parity.Compare(parity.FromAzul(rpt), parity.FromBistre(turn))
+	if len(got) != 1 {
+		t.Fatalf("diffs: want 1, got %d: %v", len(got), got)
+	} else if got[0] != want {
+		t.Errorf("diff: want %s, got %s", want, got[0])
+	}
+}
+
+func TestCompare_Units(t *testing.T) {
+	azul := &domains.TurnReport{
+		Turn: "0899-12",
+		UnitSections: []*domains.UnitSection{
+			{UnitId: "0987", CurrentLocation: &domains.Location{Hex: "OO 0202"}},
+			{UnitId: "0987e1"},
+		},
+	}
+	bistre := &domains.TurnReport{
+		Turn: "0899-12",
+		UnitSections: []*domains.UnitSection{
+			{UnitId: "0987", CurrentLocation: &domains.Location{Hex: "OO  0202"}},
+		},
+	}
+	want := parity.Diff{UnitId: "0987e1", Field: "unit", Azul: "present", Bistre: "missing"}
+	got := parity.Compare(azul, bistre)
+	if len(got) != 1 {
+		t.Fatalf("diffs: want 1, got %d: %v", len(got), got)
+	} else if got[0] != want {
+		t.Errorf("diff: want %s, got %s", want, got[0])
+	}
+}
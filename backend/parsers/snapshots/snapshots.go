@@ -0,0 +1,190 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+// Package snapshots verifies the parser against a corpus of real reports.
+//
+// The corpus is a folder of report extracts (*.txt). Each extract has an
+// expected snapshot next to it (the same name with a .json extension)
+// that holds the Turn_t the parser returned when the snapshot was made.
+// Verify re-parses every extract and reports the units whose output
+// drifted from the snapshot.
+//
+// The corpus can be anonymized reports; the turn is taken from the file
+// name when present and from the "Current Turn" line otherwise.
+package snapshots
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/parsers"
+)
+
+// Status is the outcome of verifying one extract.
+type Status string
+
+const (
+	Match   Status = "match"   // output matches the snapshot
+	Drift   Status = "drift"   // output differs from the snapshot
+	Missing Status = "missing" // there is no snapshot for the extract
+	Updated Status = "updated" // the snapshot was created or replaced
+	Failed  Status = "failed"  // the extract could not be parsed
+)
+
+// Result is the outcome of verifying one extract.
+type Result struct {
+	Name     string // file name of the extract
+	Snapshot string // path to the snapshot
+	Status   Status
+	Drift    []*UnitDrift // set only if Status is Drift
+	Err      error        // set only if Status is Failed
+}
+
+// UnitDrift is the difference for a single unit.
+// Unit is empty for differences outside the unit moves.
+type UnitDrift struct {
+	Unit string
+	Diff string
+}
+
+var (
+	// turn id from the report, e.g. Current Turn 899-12 (#0)
+	reCurrentTurn = regexp.MustCompile(`Current Turn (\d{3,4})-(\d{2}) `)
+)
+
+// Verify parses every extract in the folder and compares the output with
+// the snapshots. If update is true, missing and drifted snapshots are
+// (re)written and reported as Updated.
+func Verify(dir string, update bool) ([]*Result, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, err
+	} else if len(paths) == 0 {
+		return nil, fmt.Errorf("%s: no report extracts", dir)
+	}
+	sort.Strings(paths)
+
+	var results []*Result
+	for _, path := range paths {
+		results = append(results, verify(path, update))
+	}
+	return results, nil
+}
+
+func verify(path string, update bool) *Result {
+	r := &Result{
+		Name:     filepath.Base(path),
+		Snapshot: strings.TrimSuffix(path, filepath.Ext(path)) + ".json",
+	}
+	input, err := os.ReadFile(path)
+	if err != nil {
+		r.Status, r.Err = Failed, err
+		return r
+	}
+	got, err := Snapshot(r.Name, input)
+	if err != nil {
+		r.Status, r.Err = Failed, err
+		return r
+	}
+
+	want, err := os.ReadFile(r.Snapshot)
+	if errors.Is(err, os.ErrNotExist) {
+		r.Status = Missing
+	} else if err != nil {
+		r.Status, r.Err = Failed, err
+		return r
+	} else if bytes.Equal(want, got) {
+		r.Status = Match
+		return r
+	} else if r.Drift, err = Compare(want, got); err != nil {
+		r.Status, r.Err = Failed, errors.Join(fmt.Errorf("%s: compare", r.Snapshot), err)
+		return r
+	} else if len(r.Drift) == 0 {
+		// only the formatting differs
+		r.Status = Match
+		return r
+	} else {
+		r.Status = Drift
+	}
+
+	if update {
+		if err := os.WriteFile(r.Snapshot, got, 0o644); err != nil {
+			r.Status, r.Err = Failed, err
+			return r
+		}
+		r.Status = Updated
+	}
+	return r
+}
+
+// Snapshot parses the extract and returns the snapshot of the output.
+func Snapshot(name string, input []byte) ([]byte, error) {
+	result, err := parsers.DefaultRegistry().Parse(parsers.DefaultFormat, "", name, TurnId(name, input), input)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.MarshalIndent(result.Output, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// TurnId returns the turn id from the file name or the report text.
+func TurnId(name string, input []byte) string {
+	if tid := domains.TurnIdFromName(name); tid != "" {
+		return tid
+	} else if m := reCurrentTurn.FindSubmatch(input); m != nil {
+		year, _ := strconv.Atoi(string(m[1]))
+		month, _ := strconv.Atoi(string(m[2]))
+		return fmt.Sprintf("%04d-%02d", year, month)
+	}
+	return ""
+}
+
+// Compare returns the drift between two snapshots, unit by unit.
+func Compare(want, got []byte) ([]*UnitDrift, error) {
+	var w, g map[string]any
+	if err := json.Unmarshal(want, &w); err != nil {
+		return nil, err
+	} else if err = json.Unmarshal(got, &g); err != nil {
+		return nil, err
+	}
+
+	var drift []*UnitDrift
+	wantUnits, _ := w["UnitMoves"].(map[string]any)
+	gotUnits, _ := g["UnitMoves"].(map[string]any)
+	delete(w, "UnitMoves")
+	delete(g, "UnitMoves")
+	if diff := cmp.Diff(w, g); diff != "" {
+		drift = append(drift, &UnitDrift{Diff: diff})
+	}
+
+	units := map[string]bool{}
+	for unit := range wantUnits {
+		units[unit] = true
+	}
+	for unit := range gotUnits {
+		units[unit] = true
+	}
+	var unitIds []string
+	for unit := range units {
+		unitIds = append(unitIds, unit)
+	}
+	sort.Strings(unitIds)
+	for _, unit := range unitIds {
+		if diff := cmp.Diff(wantUnits[unit], gotUnits[unit]); diff != "" {
+			drift = append(drift, &UnitDrift{Unit: unit, Diff: diff})
+		}
+	}
+	return drift, nil
+}
@@ -0,0 +1,46 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package snapshots_test
+
+import (
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/parsers/snapshots"
+)
+
+func TestCompare(t *testing.T) {
+	want := []byte(`{"Id":"0899-12","UnitMoves":{"0987":{"CurrentHex":"OO 0202"},"1987":{"CurrentHex":"OO 0203"}}}`)
+	got := []byte(`{"Id": "0899-12", "UnitMoves": {"0987": {"CurrentHex": "OO 0202"}, "1987": {"CurrentHex": "OO 0303"}}}`)
+
+	drift, err := snapshots.Compare(want, want)
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	} else if len(drift) != 0 {
+		t.Errorf("same: want no drift, got %d", len(drift))
+	}
+
+	drift, err = snapshots.Compare(want, got)
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	} else if len(drift) != 1 {
+		t.Fatalf("drift: want 1, got %d", len(drift))
+	} else if drift[0].Unit != "1987" {
+		t.Errorf("drift: want unit %q, got %q", "1987", drift[0].Unit)
+	}
+}
+
+func TestTurnId(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"0301.0899-12.0987.report.txt", "", "0899-12"},
+		{"anon.txt", "Current Turn 899-12 (#0), Winter, FINE\tNext Turn 900-01 (#1), 12/11/2023\n", "0899-12"},
+		{"anon.txt", "no turn here", ""},
+	} {
+		if got := snapshots.TurnId(tc.name, []byte(tc.input)); got != tc.want {
+			t.Errorf("%s: want %q, got %q", tc.name, tc.want, got)
+		}
+	}
+}
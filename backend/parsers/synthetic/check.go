@@ -0,0 +1,300 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package synthetic
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/playbymail/ottoapp/backend/parsers/bistre"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/direction"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/edges"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/resources"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/terrain"
+	"github.com/playbymail/ottoapp/backend/services/reports/office"
+)
+
+// RoundTrip renders the model as text and as a DOCX, extracts the text
+// from the DOCX, parses it with bistre, and returns every difference
+// between the parsed turn and the model. An error means that the report
+// couldn't be rendered or parsed at all.
+func RoundTrip(m *Model) ([]string, error) {
+	var diffs []string
+	text := m.Text()
+	docx, err := m.Docx()
+	if err != nil {
+		return nil, err
+	}
+	doc, err := office.Parse(bytes.NewReader(docx))
+	if err != nil {
+		return nil, err
+	} else if !bytes.Equal(doc.Text, text) {
+		diffs = append(diffs, "docx: extracted text does not match the report text")
+	}
+	name := fmt.Sprintf("%s.%s.report.txt", m.TurnId, m.Clan)
+	turn, err := bistre.ParseInput(name, m.TurnId, doc.Text, false, false, false, false, false, false, false, false, bistre.ParseConfig{Version: bistre.Version})
+	if err != nil {
+		return nil, err
+	}
+	return append(diffs, Check(m, turn)...), nil
+}
+
+// Check compares a parsed turn with the model that generated it.
+// It returns a description of every difference.
+func Check(m *Model, turn *bistre.Turn_t) []string {
+	var diffs []string
+	diff := func(format string, args ...any) {
+		diffs = append(diffs, fmt.Sprintf(format, args...))
+	}
+
+	if turn.Id != m.TurnId {
+		diff("turn: want %q, got %q", m.TurnId, turn.Id)
+	}
+	if len(turn.SpecialNames) != len(m.Specials) {
+		diff("specials: want %d, got %d", len(m.Specials), len(turn.SpecialNames))
+	}
+	for _, sp := range m.Specials {
+		if got, ok := turn.SpecialNames[sp.Id]; !ok {
+			diff("special %q: missing", sp.Id)
+		} else if got.Name != sp.Name {
+			diff("special %q: name: want %q, got %q", sp.Id, sp.Name, got.Name)
+		}
+	}
+
+	if len(turn.UnitMoves) != len(m.Units) {
+		diff("units: want %d, got %d", len(m.Units), len(turn.UnitMoves))
+	}
+	for _, u := range m.Units {
+		moves, ok := turn.UnitMoves[u.Id]
+		if !ok {
+			diff("%s: missing", u.Id)
+			continue
+		}
+		if moves.PreviousHex != u.PreviousHex {
+			diff("%s: previous hex: want %q, got %q", u.Id, u.PreviousHex, moves.PreviousHex)
+		}
+		if moves.CurrentHex != u.CurrentHex {
+			diff("%s: current hex: want %q, got %q", u.Id, u.CurrentHex, moves.CurrentHex)
+		}
+		if moves.Follows != u.Follows {
+			diff("%s: follows: want %q, got %q", u.Id, u.Follows, moves.Follows)
+		}
+		if moves.GoesTo != u.GoesTo {
+			diff("%s: goes to: want %q, got %q", u.Id, u.GoesTo, moves.GoesTo)
+		}
+
+		// follows and goes to are a single move, then the steps, then the status line
+		var want []*Step
+		got := moves.Moves
+		if u.Follows != "" || u.GoesTo != "" {
+			if len(got) == 0 {
+				diff("%s: moves: want follows or goes to, got none", u.Id)
+				continue
+			}
+			got = got[1:]
+		} else {
+			want = append(want, u.Steps...)
+		}
+		want = append(want, u.Status)
+		if len(got) != len(want) {
+			diff("%s: moves: want %d, got %d", u.Id, len(want), len(got))
+			continue
+		}
+		for n := range want {
+			for _, d := range checkStep(want[n], got[n]) {
+				diff("%s: move %d: %s", u.Id, n+1, d)
+			}
+		}
+
+		if len(moves.Scouts) != len(u.Scouts) {
+			diff("%s: scouts: want %d, got %d", u.Id, len(u.Scouts), len(moves.Scouts))
+			continue
+		}
+		for n, scout := range u.Scouts {
+			gs := moves.Scouts[n]
+			if gs.No != scout.No {
+				diff("%s: scout %d: no: want %d, got %d", u.Id, n+1, scout.No, gs.No)
+			}
+			if len(gs.Moves) != len(scout.Steps) {
+				diff("%s: scout %d: steps: want %d, got %d", u.Id, scout.No, len(scout.Steps), len(gs.Moves))
+				continue
+			}
+			for i := range scout.Steps {
+				for _, d := range checkStep(scout.Steps[i], gs.Moves[i]) {
+					diff("%s: scout %d: step %d: %s", u.Id, scout.No, i+1, d)
+				}
+			}
+		}
+	}
+	return diffs
+}
+
+// checkStep compares a step with the parsed move.
+func checkStep(want *Step, got *bistre.Move_t) []string {
+	var diffs []string
+	diff := func(format string, args ...any) {
+		diffs = append(diffs, fmt.Sprintf(format, args...))
+	}
+	if got.Advance != want.Advance {
+		diff("advance: want %q, got %q", want.Advance, got.Advance)
+	}
+	if got.Still != want.Still {
+		diff("still: want %v, got %v", want.Still, got.Still)
+	}
+	if got.Result != want.Result {
+		diff("result: want %q, got %q", want.Result, got.Result)
+	}
+	if got.Report == nil {
+		diff("report: missing")
+		return diffs
+	}
+	r := got.Report
+	if r.Terrain != want.Terrain {
+		diff("terrain: want %q, got %q", want.Terrain, r.Terrain)
+	}
+
+	var wantBorders, gotBorders []string
+	border := func(d direction.Direction_e, e edges.Edge_e, t terrain.Terrain_e) string {
+		return fmt.Sprintf("%s/%s/%s", d, e, t)
+	}
+	for _, b := range r.Borders {
+		gotBorders = append(gotBorders, border(b.Direction, b.Edge, b.Terrain))
+	}
+	for _, e := range want.Edges {
+		wantBorders = append(wantBorders, border(e.Direction, e.Edge, terrain.Blank))
+	}
+	for _, n := range append(append([]Neighbor{}, want.Neighbors...), want.Deck...) {
+		wantBorders = append(wantBorders, border(n.Direction, edges.None, n.Terrain))
+	}
+	wantRoads := legacyRoads(want.Roads)
+	for _, road := range wantRoads {
+		wantBorders = append(wantBorders, border(road.Direction, road.Road, terrain.Blank))
+	}
+	switch want.Failure {
+	case BlockedByRiver:
+		wantBorders = append(wantBorders, border(want.Advance, edges.River, terrain.Blank))
+	case Exhausted, ProhibitedWater, NoPass:
+		wantBorders = append(wantBorders, border(want.Advance, edges.None, want.Blocker))
+	}
+	if w, g := set(wantBorders), set(gotBorders); w != g {
+		diff("borders: want %s, got %s", w, g)
+	}
+
+	var gotRoads []string
+	for _, road := range r.Roads {
+		gotRoads = append(gotRoads, roadString(road))
+	}
+	var wantRoadText []string
+	for _, road := range wantRoads {
+		wantRoadText = append(wantRoadText, roadString(road))
+	}
+	if w, g := strings.Join(wantRoadText, " "), strings.Join(gotRoads, " "); w != g {
+		diff("roads: want %s, got %s", w, g)
+	}
+	// the legacy text loses the road type, but the true type must be one of the choices
+	for _, road := range want.Roads {
+		for _, gr := range r.Roads {
+			if gr.Direction == road.Direction && gr.Road != road.Road && !slices.Contains(gr.Alternatives, road.Road) {
+				diff("road %s: %s is not one of the choices", road.Direction, road.Road)
+			}
+		}
+	}
+
+	var gotSettlements []string
+	for _, s := range r.Settlements {
+		gotSettlements = append(gotSettlements, s.Name)
+	}
+	var wantSettlements []string
+	if want.Settlement != "" {
+		wantSettlements = append(wantSettlements, want.Settlement)
+	}
+	if w, g := set(wantSettlements), set(gotSettlements); w != g {
+		diff("settlements: want %s, got %s", w, g)
+	}
+
+	var wantResources, gotResources []string
+	if want.Resource != resources.None {
+		wantResources = append(wantResources, want.Resource.String())
+	}
+	for _, rs := range r.Resources {
+		gotResources = append(gotResources, rs.String())
+	}
+	if w, g := set(wantResources), set(gotResources); w != g {
+		diff("resources: want %s, got %s", w, g)
+	}
+
+	var wantUnits, gotUnits []string
+	for _, u := range want.Units {
+		wantUnits = append(wantUnits, string(u))
+	}
+	for _, e := range r.Encounters {
+		gotUnits = append(gotUnits, string(e.UnitId))
+	}
+	if w, g := set(wantUnits), set(gotUnits); w != g {
+		diff("encounters: want %s, got %s", w, g)
+	}
+
+	var wantHorizon, gotHorizon []string
+	for _, fh := range want.Horizon {
+		wantHorizon = append(wantHorizon, fmt.Sprintf("%s/%s", fh.Point, fh.Terrain))
+	}
+	for _, fh := range r.FarHorizons {
+		gotHorizon = append(gotHorizon, fmt.Sprintf("%s/%s", fh.Point, fh.Terrain))
+	}
+	if w, g := set(wantHorizon), set(gotHorizon); w != g {
+		diff("far horizons: want %s, got %s", w, g)
+	}
+
+	return diffs
+}
+
+// legacyRoads returns the roads as they should be read from the legacy text:
+// a bare direction takes the type printed closest to its left and is
+// ambiguous if more than one type was printed before it.
+func legacyRoads(roads []Road) []*bistre.Road_t {
+	var list []*bistre.Road_t
+	var printed []edges.Edge_e
+	current := edges.None
+	for _, d := range direction.Directions {
+		for _, r := range roads {
+			if r.Direction != d {
+				continue
+			}
+			road := &bistre.Road_t{Direction: d}
+			if !slices.Contains(printed, r.Road) {
+				printed = append(printed, r.Road)
+				current = r.Road
+				road.Road = r.Road
+			} else {
+				road.Road = current
+				if len(printed) > 1 {
+					road.Ambiguous = true
+					road.Alternatives = append([]edges.Edge_e{}, printed...)
+				}
+			}
+			list = append(list, road)
+		}
+	}
+	return list
+}
+
+func roadString(r *bistre.Road_t) string {
+	s := r.String()
+	if r.Ambiguous {
+		var alts []string
+		for _, alt := range r.Alternatives {
+			alts = append(alts, alt.String())
+		}
+		s += "(" + strings.Join(alts, "|") + ")"
+	}
+	return s
+}
+
+// set returns the sorted, unique values as a string.
+func set(values []string) string {
+	sort.Strings(values)
+	return "[" + strings.Join(slices.Compact(values), " ") + "]"
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package synthetic
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/compass"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/direction"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/edges"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/resources"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/terrain"
+)
+
+// Text renders the model as a turn report extract.
+func (m *Model) Text() []byte {
+	b := &bytes.Buffer{}
+	for n, u := range m.Units {
+		if n != 0 {
+			b.WriteByte('\n')
+		}
+		fmt.Fprintf(b, "%s %s, , Current Hex = %s, (Previous Hex = %s)\n", u.Kind, u.Id, u.CurrentHex, u.PreviousHex)
+		if n == 0 {
+			b.WriteString(m.turnLine())
+			for _, sp := range m.Specials {
+				fmt.Fprintf(b, ">>>>%s>%s\n", sp.Id, sp.Name)
+			}
+		}
+		switch {
+		case u.Follows != "":
+			fmt.Fprintf(b, "Tribe Follows %s\n", u.Follows)
+		case u.GoesTo != "":
+			fmt.Fprintf(b, "Tribe Goes to %s\n", u.GoesTo)
+		case u.Wind != nil:
+			fmt.Fprintf(b, "%s %s Fleet Movement: Move %s\n", u.Wind.Strength, u.Wind.From, stepsText(u.Steps, false))
+		default:
+			fmt.Fprintf(b, "Tribe Movement: Move %s\n", stepsText(u.Steps, false))
+		}
+		for _, scout := range u.Scouts {
+			fmt.Fprintf(b, "Scout %d:Scout %s\n", scout.No, stepsText(scout.Steps, true))
+		}
+		fmt.Fprintf(b, "%s Status: %s\n", u.Id, statusText(u.Status))
+	}
+	return b.Bytes()
+}
+
+// turnLine returns the "Current Turn" line. Turn 899-12 is turn zero.
+func (m *Model) turnLine() string {
+	no := (m.Year-899)*12 + m.Month - 12
+	ny, nm := m.Year, m.Month+1
+	if nm > 12 {
+		ny, nm = ny+1, 1
+	}
+	return fmt.Sprintf("Current Turn %d-%02d (#%d), %s, FINE\tNext Turn %d-%02d (#%d), %s\n", m.Year, m.Month, no, season(m.Month), ny, nm, no+1, m.Date)
+}
+
+func season(month int) string {
+	switch {
+	case month <= 3:
+		return "Spring"
+	case month <= 6:
+		return "Summer"
+	case month <= 9:
+		return "Fall"
+	}
+	return "Winter"
+}
+
+// stepsText returns the steps of a movement line.
+// A unit that stays in place has a single still step, which is rendered as a lone backslash.
+func stepsText(steps []*Step, isScout bool) string {
+	if len(steps) == 1 && steps[0].Still && steps[0].Failure == NoFailure {
+		return `\`
+	}
+	var list []string
+	for _, step := range steps {
+		list = append(list, stepText(step, isScout))
+	}
+	return strings.Join(list, `\`)
+}
+
+func stepText(s *Step, isScout bool) string {
+	switch s.Failure {
+	case BlockedByRiver:
+		return fmt.Sprintf("No Ford on River to %s of HEX", s.Advance)
+	case Exhausted:
+		return fmt.Sprintf("Not enough M.P's to move to %s into %s", s.Advance, longNames[s.Blocker])
+	case ProhibitedWater:
+		water := "Lake"
+		if s.Blocker == terrain.WaterOcean {
+			water = "Ocean"
+		}
+		return fmt.Sprintf("Can't Move on %s to %s of HEX", water, s.Advance)
+	case NoPass:
+		return fmt.Sprintf("No Pass into Mountain to %s of HEX", s.Advance)
+	case NoRiverAdjacent:
+		return fmt.Sprintf("No River Adjacent to Hex to %s of HEX", s.Advance)
+	}
+	list := append([]string{fmt.Sprintf("%s-%s", s.Advance, s.Terrain)}, observations(s)...)
+	if isScout && s.Settlement == "" && s.Resource == resources.None {
+		list = append(list, "Nothing of interest found")
+	}
+	text := strings.Join(list, ", ")
+	if len(s.Deck) != 0 {
+		var deck, horizon []string
+		for _, n := range s.Deck {
+			deck = append(deck, fmt.Sprintf("%s %s", n.Direction, n.Terrain))
+		}
+		for _, fh := range s.Horizon {
+			sight := "Land"
+			if fh.Terrain == terrain.UnknownWater {
+				sight = "Water"
+			}
+			horizon = append(horizon, fmt.Sprintf("Sight %s - %s", sight, compassText(fh.Point)))
+		}
+		text += fmt.Sprintf(", -(%s)(%s)", strings.Join(deck, ", "), strings.Join(horizon, ", "))
+	}
+	return text
+}
+
+func statusText(s *Step) string {
+	list := append([]string{longNames[s.Terrain]}, observations(s)...)
+	if len(s.Units) != 0 {
+		var units []string
+		for _, u := range s.Units {
+			units = append(units, string(u))
+		}
+		list = append(list, strings.Join(units, ", "))
+	}
+	return strings.Join(list, ", ")
+}
+
+// observations returns the components that describe the hex:
+// neighbors, edges, roads, settlement, and resources.
+func observations(s *Step) []string {
+	var list []string
+	var kinds []terrain.Terrain_e
+	byKind := map[terrain.Terrain_e][]string{}
+	for _, n := range s.Neighbors {
+		if _, ok := byKind[n.Terrain]; !ok {
+			kinds = append(kinds, n.Terrain)
+		}
+		byKind[n.Terrain] = append(byKind[n.Terrain], n.Direction.String())
+	}
+	for _, kind := range kinds {
+		list = append(list, fmt.Sprintf("%s %s", kind, strings.Join(byKind[kind], " ")))
+	}
+	for _, kind := range []edges.Edge_e{edges.River, edges.Ford, edges.Pass} {
+		var dirs []string
+		for _, e := range s.Edges {
+			if e.Edge == kind {
+				dirs = append(dirs, e.Direction.String())
+			}
+		}
+		if len(dirs) != 0 {
+			list = append(list, fmt.Sprintf("%s %s", kind, strings.Join(dirs, " ")))
+		}
+	}
+	if len(s.Roads) != 0 {
+		list = append(list, roadsText(s.Roads))
+	}
+	if s.Settlement != "" {
+		list = append(list, s.Settlement)
+	}
+	if s.Resource != resources.None {
+		list = append(list, s.Resource.String())
+	}
+	return list
+}
+
+// roadsText renders the roads the way the legacy report generator does:
+// the road type is printed the first time it is used and only the
+// direction after that, even if another road type was printed in between.
+func roadsText(roads []Road) string {
+	var parts []string
+	printed := map[edges.Edge_e]bool{}
+	for _, d := range direction.Directions {
+		for _, r := range roads {
+			if r.Direction != d {
+				continue
+			} else if !printed[r.Road] {
+				parts = append(parts, r.Road.String())
+				printed[r.Road] = true
+			}
+			parts = append(parts, d.String())
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func compassText(p compass.Point_e) string {
+	for _, cn := range crowsNest {
+		if cn.point == p {
+			return cn.text
+		}
+	}
+	panic(fmt.Sprintf("assert(compass point %v is known)", p))
+}
+
+// Docx renders the model as a Word document. Every line of the text is a
+// paragraph and tabs are <w:tab/> runs, which is what office.Parse reads.
+// The zip timestamps are fixed so that the output is deterministic.
+func (m *Model) Docx() ([]byte, error) {
+	body := &bytes.Buffer{}
+	text := strings.TrimSuffix(string(m.Text()), "\n")
+	for _, line := range strings.Split(text, "\n") {
+		body.WriteString(`<w:p>`)
+		for n, run := range strings.Split(line, "\t") {
+			if n != 0 {
+				body.WriteString(`<w:r><w:tab/></w:r>`)
+			}
+			if run == "" {
+				continue
+			}
+			body.WriteString(`<w:r><w:t xml:space="preserve">`)
+			if err := xml.EscapeText(body, []byte(run)); err != nil {
+				return nil, err
+			}
+			body.WriteString(`</w:t></w:r>`)
+		}
+		body.WriteString("</w:p>\n")
+	}
+
+	files := []struct{ name, body string }{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"><Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/><Default Extension="xml" ContentType="application/xml"/><Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/></Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"><Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/></Relationships>`},
+		{"word/document.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>
+` + body.String() + `</w:body></w:document>`},
+	}
+
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	modified := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: modified})
+		if err != nil {
+			return nil, err
+		} else if _, err = w.Write([]byte(f.body)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,419 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+// Package synthetic generates turn reports from a synthetic world.
+//
+// The reports look like real TribeNet turn reports but don't leak any
+// player's data, so they can be checked in as test data for the parsers
+// and the map pipeline.
+//
+// Generate builds a Model from a seed. The same seed always produces the
+// same model, text, and DOCX. The model covers the things that break
+// parsers: movement failures, scouts, fleets with winds, settlements,
+// special hexes, and the legacy road quirk (see docs/BUGS.md).
+//
+// RoundTrip renders the model, parses the text with bistre, and reports
+// every place where the parsed Turn_t doesn't match the model.
+package synthetic
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+
+	"github.com/playbymail/ottoapp/backend/parsers/bistre"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/direction"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/edges"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/resources"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/results"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/terrain"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/winds"
+)
+
+// Options controls the generator.
+type Options struct {
+	Seed   uint64
+	Clan   int    // 1...999; zero picks a clan from the seed
+	TurnId string // like "0900-03"; empty picks a turn from the seed
+	Units  int    // units in addition to the clan's tribe, 1...40; zero picks the default of 5
+}
+
+// Kind is the kind of unit, which is the name of its section in the report.
+type Kind int
+
+const (
+	Tribe Kind = iota
+	Courier
+	Element
+	Fleet
+	Garrison
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Tribe:
+		return "Tribe"
+	case Courier:
+		return "Courier"
+	case Element:
+		return "Element"
+	case Fleet:
+		return "Fleet"
+	case Garrison:
+		return "Garrison"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Failure is the reason a step failed.
+type Failure int
+
+const (
+	NoFailure       Failure = iota
+	BlockedByRiver          // "No Ford on River to SE of HEX"
+	Exhausted               // "Not enough M.P's to move to SE into PRAIRIE"
+	ProhibitedWater         // "Can't Move on Lake to SE of HEX"
+	NoPass                  // "No Pass into Mountain to SE of HEX"
+	NoRiverAdjacent         // fleets: "No River Adjacent to Hex to SE of HEX"
+)
+
+// Model is the synthetic turn. It holds everything the report says.
+type Model struct {
+	Seed     uint64
+	TurnId   string // "0900-03"
+	Year     int
+	Month    int
+	Clan     bistre.UnitId_t // the clan's tribe, like "0987"
+	Date     string          // report date, like "28/11/2025"
+	Units    []*Unit         // in report order
+	Specials []*Special
+}
+
+// Unit is a unit that reports in the turn.
+type Unit struct {
+	Id          bistre.UnitId_t
+	Kind        Kind
+	PreviousHex string
+	CurrentHex  string
+	Follows     bistre.UnitId_t // set if the unit follows another unit
+	GoesTo      string          // set if the unit goes to a hex
+	Wind        *Wind           // set only for fleets
+	Steps       []*Step         // movement steps, if the unit moved
+	Scouts      []*Scout
+	Status      *Step // observations of the current hex
+}
+
+// Wind is the wind for a fleet movement. The parser doesn't keep it.
+type Wind struct {
+	Strength winds.Strength_e
+	From     direction.Direction_e
+}
+
+// Scout is a scouting party sent out at the end of the turn.
+type Scout struct {
+	No    int
+	Steps []*Step
+}
+
+// Step is one step of a movement line or the observations on a status line.
+type Step struct {
+	Advance    direction.Direction_e
+	Still      bool
+	Result     results.Result_e
+	Failure    Failure
+	Terrain    terrain.Terrain_e // terrain of the hex the unit ends the step in; blank for failures
+	Blocker    terrain.Terrain_e // terrain that stopped an exhausted or prohibited unit
+	Edges      []Edge            // rivers, fords, and passes
+	Neighbors  []Neighbor        // obvious neighboring terrain (water and mountains)
+	Roads      []Road            // the true roads, in direction order
+	Settlement string
+	Resource   resources.Resource_e
+	Units      []bistre.UnitId_t     // units seen in the hex; only on status lines
+	Deck       []Neighbor            // fleets: terrain of the six neighbors
+	Horizon    []bistre.FarHorizon_t // fleets: land and water two hexes out
+}
+
+// Edge is an edge feature on a side of the hex.
+type Edge struct {
+	Direction direction.Direction_e
+	Edge      edges.Edge_e
+}
+
+// Neighbor is the terrain of a neighboring hex.
+type Neighbor struct {
+	Direction direction.Direction_e
+	Terrain   terrain.Terrain_e
+}
+
+// Road is a road leaving the hex.
+type Road struct {
+	Direction direction.Direction_e
+	Road      edges.Edge_e
+}
+
+// Special is a special hex. Id is the full name forced to lower case.
+type Special struct {
+	Id   string
+	Name string
+}
+
+// Unit returns the unit with the given id, or nil.
+func (m *Model) Unit(id bistre.UnitId_t) *Unit {
+	for _, u := range m.Units {
+		if u.Id == id {
+			return u
+		}
+	}
+	return nil
+}
+
+// Generate builds a model from the options.
+func Generate(opts Options) (*Model, error) {
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x5eed))
+	m := &Model{Seed: opts.Seed}
+
+	if opts.Clan == 0 {
+		opts.Clan = 1 + rng.IntN(999)
+	} else if opts.Clan < 1 || opts.Clan > 999 {
+		return nil, fmt.Errorf("clan %d: must be 1...999", opts.Clan)
+	}
+	m.Clan = bistre.UnitId_t(fmt.Sprintf("%04d", opts.Clan))
+
+	if opts.TurnId == "" {
+		m.Year, m.Month = 900+rng.IntN(5), 1+rng.IntN(12)
+	} else if n, err := fmt.Sscanf(opts.TurnId, "%d-%d", &m.Year, &m.Month); err != nil || n != 2 {
+		return nil, fmt.Errorf("turn %q: want yyyy-mm", opts.TurnId)
+	} else if m.Year < 899 || m.Year > 9999 || m.Month < 1 || m.Month > 12 || (m.Year == 899 && m.Month != 12) {
+		return nil, fmt.Errorf("turn %q: out of range", opts.TurnId)
+	}
+	m.TurnId = fmt.Sprintf("%04d-%02d", m.Year, m.Month)
+	m.Date = fmt.Sprintf("%d/%d/2025", 1+rng.IntN(28), 1+rng.IntN(12))
+
+	if opts.Units == 0 {
+		opts.Units = 5
+	} else if opts.Units < 0 || opts.Units > 40 {
+		return nil, fmt.Errorf("units %d: must be 1...40, or 0 for the default", opts.Units)
+	}
+
+	w := newWorld(rng)
+	g := &generator{rng: rng, world: w, model: m}
+	g.run(opts.Units)
+	return m, nil
+}
+
+type generator struct {
+	rng   *rand.Rand
+	world *world
+	model *Model
+	next  map[string]int // next sequence number for each unit kind
+}
+
+// run places the units, moves them, and records what they see.
+//
+// The clan's tribe moves and sends out scouts. The rest of the units
+// cycle through the things a unit can do, so that even a small report
+// covers the element, fleet, goes to, follows, and garrison cases.
+func (g *generator) run(extra int) {
+	m, w := g.model, g.world
+	g.next = map[string]int{}
+
+	// the clan's home hex has a special settlement and the legacy road quirk
+	home := w.randomHex()
+	w.setLand(home)
+	special := w.settlementName()
+	w.hex(home).settlement = special
+	m.Specials = append(m.Specials, &Special{Id: lower(special), Name: special[:3]})
+	w.quirkRoads(home)
+
+	clan := &Unit{Id: m.Clan, Kind: Tribe, PreviousHex: home}
+	clan.Steps, clan.CurrentHex = g.walk(home, 18, false)
+	for n := 1 + g.rng.IntN(2); len(clan.Scouts) < n; {
+		scout := &Scout{No: len(clan.Scouts) + 1}
+		scout.Steps, _ = g.walk(clan.CurrentHex, 12, true)
+		clan.Scouts = append(clan.Scouts, scout)
+	}
+	m.Units = append(m.Units, clan)
+
+	roles := []Kind{Element, Fleet, Tribe, Courier, Garrison}
+	for n := 0; n < extra; n++ {
+		role := roles[n%len(roles)]
+		if n >= len(roles) {
+			role = roles[g.rng.IntN(len(roles))]
+		}
+		for !g.available(role) {
+			role = roles[g.rng.IntN(len(roles))]
+		}
+		u := &Unit{Kind: role, PreviousHex: home}
+		switch role {
+		case Element:
+			u.Id = g.unitId('e')
+			u.Steps, u.CurrentHex = g.walk(home, 18, false)
+		case Fleet:
+			u.Id = g.unitId('f')
+			u.PreviousHex = w.harbor(home, 4+g.rng.IntN(3))
+			u.Wind = &Wind{Strength: winds.Calm + winds.Strength_e(g.rng.IntN(4)), From: g.direction()}
+			u.Steps, u.CurrentHex = g.sail(u.PreviousHex, u.Wind)
+		case Tribe:
+			// tribes other than the clan teleport, which is what "goes to" is for
+			u.Id = g.unitId(0)
+			for tries := 0; u.GoesTo == "" || !w.isLand(u.GoesTo); tries++ {
+				if tries == 10 {
+					u.GoesTo = home
+					break
+				}
+				u.GoesTo = home
+				for i := 3 + g.rng.IntN(3); i > 0; i-- {
+					u.GoesTo = w.neighbor(u.GoesTo, g.direction())
+				}
+			}
+			u.CurrentHex = u.GoesTo
+		case Courier:
+			// couriers follow a unit that has already moved
+			u.Id = g.unitId('c')
+			var leaders []*Unit
+			for _, l := range m.Units {
+				if l.Kind != Fleet && l.Follows == "" {
+					leaders = append(leaders, l)
+				}
+			}
+			leader := leaders[g.rng.IntN(len(leaders))]
+			u.Follows, u.CurrentHex = leader.Id, leader.CurrentHex
+		case Garrison:
+			// garrisons stay home, which puts the roads and the special hex on their status line
+			u.Id = g.unitId('g')
+			u.Steps = []*Step{{Still: true, Result: results.Succeeded}}
+			u.CurrentHex = home
+		}
+		m.Units = append(m.Units, u)
+	}
+
+	// status lines are last since they list every unit in the hex
+	for _, u := range m.Units {
+		u.Status = g.observe(u.CurrentHex, false)
+		u.Status.Still, u.Status.Result = true, results.StatusLine
+		for _, o := range m.Units {
+			if o.CurrentHex == u.CurrentHex {
+				u.Status.Units = append(u.Status.Units, o.Id)
+			}
+		}
+		if g.rng.IntN(4) == 0 {
+			// a stranger
+			u.Status.Units = append(u.Status.Units, bistre.UnitId_t(fmt.Sprintf("%d%03d", g.rng.IntN(10), 1+g.rng.IntN(999))))
+		}
+		sort.Slice(u.Status.Units, func(i, j int) bool {
+			return u.Status.Units[i] < u.Status.Units[j]
+		})
+	}
+}
+
+// suffixes maps the kind of unit to the suffix on its id.
+var suffixes = map[Kind]byte{Tribe: 0, Courier: 'c', Element: 'e', Fleet: 'f', Garrison: 'g'}
+
+// available returns true if the clan can have another unit of the kind.
+// Ids only have room for nine of each.
+func (g *generator) available(kind Kind) bool {
+	if suffixes[kind] == 0 {
+		return g.next["tribe"] < 9
+	}
+	return g.next[string(suffixes[kind])] < 9
+}
+
+// unitId returns the next id for a unit in the clan. A suffix of 0 is a tribe.
+func (g *generator) unitId(suffix byte) bistre.UnitId_t {
+	clan := string(g.model.Clan)
+	if suffix == 0 {
+		g.next["tribe"]++
+		return bistre.UnitId_t(fmt.Sprintf("%d%s", g.next["tribe"], clan[1:]))
+	}
+	g.next[string(suffix)]++
+	return bistre.UnitId_t(fmt.Sprintf("%s%c%d", clan, suffix, g.next[string(suffix)]))
+}
+
+func (g *generator) direction() direction.Direction_e {
+	return direction.Directions[g.rng.IntN(len(direction.Directions))]
+}
+
+// walk moves a land unit (or a scout) until it runs out of movement points,
+// fails a step, or decides to stop. It returns the steps and the final hex.
+func (g *generator) walk(from string, mp int, isScout bool) ([]*Step, string) {
+	w := g.world
+	var steps []*Step
+	for len(steps) < 8 {
+		if len(steps) != 0 && !isScout && g.rng.IntN(5) == 0 {
+			break
+		}
+		d := g.direction()
+		to := w.neighbor(from, d)
+		side, target := w.side(from, d), w.hex(to)
+		step := &Step{Advance: d, Result: results.Failed}
+		cost := target.cost(side == edges.Pass)
+		switch {
+		case side == edges.River:
+			step.Failure = BlockedByRiver
+		case target.terrain == terrain.WaterLake || target.terrain == terrain.WaterOcean:
+			step.Failure, step.Blocker = ProhibitedWater, target.terrain
+		case target.terrain.IsAnyMountain() && side != edges.Pass:
+			step.Failure, step.Blocker = NoPass, terrain.UnknownMountain
+		case cost > mp:
+			step.Failure, step.Blocker = Exhausted, target.terrain
+		}
+		if step.Failure != NoFailure {
+			return append(steps, step), from
+		}
+		mp -= cost
+		seen := g.observe(to, false)
+		seen.Advance, seen.Result = d, results.Succeeded
+		steps = append(steps, seen)
+		from = to
+	}
+	return steps, from
+}
+
+// sail moves a fleet. It stops when the wind gives out or the fleet runs into land.
+func (g *generator) sail(from string, wind *Wind) ([]*Step, string) {
+	w := g.world
+	var steps []*Step
+	for n := int(wind.Strength); n > 0; n-- {
+		d := g.direction()
+		to := w.neighbor(from, d)
+		if t := w.hex(to).terrain; t != terrain.WaterOcean && t != terrain.WaterLake {
+			return append(steps, &Step{Advance: d, Still: true, Result: results.Failed, Failure: NoRiverAdjacent}), from
+		}
+		seen := g.observe(to, true)
+		seen.Advance, seen.Result = d, results.Succeeded
+		steps = append(steps, seen)
+		from = to
+	}
+	return steps, from
+}
+
+// observe returns what a unit sees in the hex.
+func (g *generator) observe(id string, fromDeck bool) *Step {
+	w := g.world
+	h := w.hex(id)
+	s := &Step{Terrain: h.terrain, Settlement: h.settlement, Resource: h.resource}
+	for _, d := range direction.Directions {
+		if e := w.side(id, d); e != edges.None {
+			s.Edges = append(s.Edges, Edge{Direction: d, Edge: e})
+		}
+		if r := w.road(id, d); r != edges.None {
+			s.Roads = append(s.Roads, Road{Direction: d, Road: r})
+		}
+		nt := w.hex(w.neighbor(id, d)).terrain
+		if nt == terrain.WaterLake || nt == terrain.WaterOcean || nt.IsAnyMountain() {
+			s.Neighbors = append(s.Neighbors, Neighbor{Direction: d, Terrain: nt})
+		}
+		if fromDeck {
+			s.Deck = append(s.Deck, Neighbor{Direction: d, Terrain: nt})
+		}
+	}
+	if fromDeck {
+		for _, cn := range crowsNest {
+			t := terrain.UnknownLand
+			if ft := w.hex(w.neighbor(w.neighbor(id, cn.first), cn.second)).terrain; ft == terrain.WaterOcean || ft == terrain.WaterLake {
+				t = terrain.UnknownWater
+			}
+			s.Horizon = append(s.Horizon, bistre.FarHorizon_t{Point: cn.point, Terrain: t})
+		}
+	}
+	return s
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package synthetic_test
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/parsers/synthetic"
+)
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	a, err := synthetic.Generate(synthetic.Options{Seed: 42, Units: 12})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	b, err := synthetic.Generate(synthetic.Options{Seed: 42, Units: 12})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	if !bytes.Equal(a.Text(), b.Text()) {
+		t.Errorf("text: same seed, different reports")
+	}
+	da, err := a.Docx()
+	if err != nil {
+		t.Fatalf("docx: %v", err)
+	}
+	db, err := b.Docx()
+	if err != nil {
+		t.Fatalf("docx: %v", err)
+	}
+	if !bytes.Equal(da, db) {
+		t.Errorf("docx: same seed, different documents")
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	w := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(w)
+	for seed := uint64(1); seed <= 50; seed++ {
+		m, err := synthetic.Generate(synthetic.Options{Seed: seed, Units: 5 + int(seed%20)})
+		if err != nil {
+			t.Fatalf("%d: generate: %v", seed, err)
+		}
+		diffs, err := synthetic.RoundTrip(m)
+		if err != nil {
+			t.Fatalf("%d: round trip: %v", seed, err)
+		}
+		for _, diff := range diffs {
+			t.Errorf("%d: %s", seed, diff)
+		}
+	}
+}
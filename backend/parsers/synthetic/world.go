@@ -0,0 +1,313 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package synthetic
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/compass"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/coords"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/direction"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/edges"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/resources"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/terrain"
+)
+
+// world is the synthetic map. Hexes are created the first time they are
+// looked at, so the order of the lookups is part of the seed.
+type world struct {
+	rng   *rand.Rand
+	hexes map[string]*hex
+	sides map[sideKey]edges.Edge_e // rivers, fords, and passes
+	roads map[sideKey]edges.Edge_e
+	names map[string]bool // settlement names in use
+}
+
+type hex struct {
+	terrain    terrain.Terrain_e
+	settlement string
+	resource   resources.Resource_e
+}
+
+// sideKey identifies the side shared by two hexes.
+type sideKey struct {
+	a, b string
+}
+
+func newWorld(rng *rand.Rand) *world {
+	return &world{
+		rng:   rng,
+		hexes: map[string]*hex{},
+		sides: map[sideKey]edges.Edge_e{},
+		roads: map[sideKey]edges.Edge_e{},
+		names: map[string]bool{},
+	}
+}
+
+// landTerrain is the terrain the generator uses for land, weighted by
+// how often it shows up. Every entry must have a long name in longNames.
+var landTerrain = []terrain.Terrain_e{
+	terrain.FlatPrairie, terrain.FlatPrairie, terrain.FlatPrairie,
+	terrain.HillsGrassy, terrain.HillsGrassy,
+	terrain.FlatDeciduous, terrain.FlatDeciduous,
+	terrain.FlatBrush,
+	terrain.HillsRocky,
+	terrain.HillsConifer,
+	terrain.FlatSwamp,
+	terrain.FlatDesert,
+	terrain.FlatTundra,
+	terrain.FlatJungle,
+	terrain.LowMountainsConifer,
+}
+
+// longNames is the terrain as it is spelled on status lines and in "Not enough M.P's".
+var longNames = map[terrain.Terrain_e]string{
+	terrain.FlatBrush:           "BRUSH",
+	terrain.FlatDeciduous:       "DECIDUOUS FOREST",
+	terrain.FlatDesert:          "DESERT",
+	terrain.FlatJungle:          "JUNGLE",
+	terrain.FlatPrairie:         "PRAIRIE",
+	terrain.FlatSwamp:           "SWAMP",
+	terrain.FlatTundra:          "TUNDRA",
+	terrain.HillsConifer:        "CONIFER HILLS",
+	terrain.HillsGrassy:         "GRASSY HILLS",
+	terrain.HillsRocky:          "ROCKY HILLS",
+	terrain.LowMountainsConifer: "LOW CONIFER MOUNTAINS",
+	terrain.WaterLake:           "LAKE",
+	terrain.WaterOcean:          "OCEAN",
+}
+
+var hillResources = []resources.Resource_e{
+	resources.Coal, resources.CopperOre, resources.IronOre, resources.Limestone,
+	resources.Salt, resources.Silver, resources.TinOre, resources.ZincOre,
+}
+
+// crowsNest lists the twelve hexes two steps out, as seen from a fleet.
+var crowsNest = []struct {
+	text          string
+	point         compass.Point_e
+	first, second direction.Direction_e
+}{
+	{"N/N", compass.North, direction.North, direction.North},
+	{"N/NE", compass.NorthNorthEast, direction.North, direction.NorthEast},
+	{"NE/NE", compass.NorthEast, direction.NorthEast, direction.NorthEast},
+	{"NE/SE", compass.East, direction.NorthEast, direction.SouthEast},
+	{"SE/SE", compass.SouthEast, direction.SouthEast, direction.SouthEast},
+	{"S/SE", compass.SouthSouthEast, direction.South, direction.SouthEast},
+	{"S/S", compass.South, direction.South, direction.South},
+	{"S/SW", compass.SouthSouthWest, direction.South, direction.SouthWest},
+	{"SW/SW", compass.SouthWest, direction.SouthWest, direction.SouthWest},
+	{"SW/NW", compass.West, direction.SouthWest, direction.NorthWest},
+	{"NW/NW", compass.NorthWest, direction.NorthWest, direction.NorthWest},
+	{"N/NW", compass.NorthNorthWest, direction.North, direction.NorthWest},
+}
+
+// randomHex returns a hex well inside the map so that units never walk off it.
+func (w *world) randomHex() string {
+	return fmt.Sprintf("%c%c %02d%02d", 'D'+w.rng.IntN(18), 'D'+w.rng.IntN(18), 1+w.rng.IntN(30), 1+w.rng.IntN(21))
+}
+
+func (w *world) neighbor(id string, d direction.Direction_e) string {
+	c, err := coords.NewWorldMapCoord(id)
+	if err != nil {
+		panic(fmt.Sprintf("assert(%q is valid): %v", id, err))
+	}
+	return c.Move(d).ID()
+}
+
+// hex returns the hex, creating it if needed. New hexes usually copy the
+// terrain of a neighbor so that the map has regions instead of noise.
+func (w *world) hex(id string) *hex {
+	if h, ok := w.hexes[id]; ok {
+		return h
+	}
+	h := &hex{}
+	var near []terrain.Terrain_e
+	for _, d := range direction.Directions {
+		if n, ok := w.hexes[w.neighbor(id, d)]; ok {
+			near = append(near, n.terrain)
+		}
+	}
+	switch r := w.rng.IntN(100); {
+	case len(near) != 0 && r < 55:
+		h.terrain = near[w.rng.IntN(len(near))]
+	case r < 62:
+		h.terrain = terrain.WaterLake
+	case r < 66:
+		h.terrain = terrain.WaterOcean
+	default:
+		h.terrain = landTerrain[w.rng.IntN(len(landTerrain))]
+	}
+	w.hexes[id] = h
+	w.populate(h)
+	return h
+}
+
+// populate adds settlements and resources to a new land hex.
+func (w *world) populate(h *hex) {
+	if h.terrain == terrain.WaterLake || h.terrain == terrain.WaterOcean {
+		h.settlement, h.resource = "", resources.None
+		return
+	}
+	if w.rng.IntN(100) < 8 {
+		h.settlement = w.settlementName()
+	}
+	if (h.terrain == terrain.HillsRocky || h.terrain == terrain.HillsConifer || h.terrain.IsAnyMountain()) && w.rng.IntN(100) < 25 {
+		h.resource = hillResources[w.rng.IntN(len(hillResources))]
+	}
+}
+
+// setLand forces a hex to be land, replacing water if needed.
+func (w *world) setLand(id string) {
+	h := w.hex(id)
+	if h.terrain == terrain.WaterLake || h.terrain == terrain.WaterOcean || h.terrain.IsAnyMountain() {
+		h.terrain = terrain.FlatPrairie
+		w.populate(h)
+	}
+}
+
+// harbor returns an ocean hex some distance from the start, making sure
+// there is open water around it for a fleet to sail in. The harbor is
+// placed where no unit has looked yet so that the map stays consistent.
+func (w *world) harbor(from string, distance int) string {
+	for {
+		d := direction.Directions[w.rng.IntN(len(direction.Directions))]
+		id := from
+		for n := distance; n > 0; n-- {
+			id = w.neighbor(id, d)
+		}
+		sea, fresh := append([]string{id}, w.ring(id)...), true
+		for _, h := range sea {
+			if _, ok := w.hexes[h]; ok {
+				fresh = false
+			}
+		}
+		if !fresh {
+			distance++
+			continue
+		}
+		for _, h := range sea {
+			w.hexes[h] = &hex{terrain: terrain.WaterOcean}
+		}
+		return id
+	}
+}
+
+// ring returns the six neighbors of the hex.
+func (w *world) ring(id string) []string {
+	var list []string
+	for _, d := range direction.Directions {
+		list = append(list, w.neighbor(id, d))
+	}
+	return list
+}
+
+func (w *world) sideKey(id string, d direction.Direction_e) sideKey {
+	a, b := id, w.neighbor(id, d)
+	if b < a {
+		a, b = b, a
+	}
+	return sideKey{a: a, b: b}
+}
+
+// side returns the edge feature between the hex and its neighbor.
+// Water has no edge features.
+func (w *world) side(id string, d direction.Direction_e) edges.Edge_e {
+	k := w.sideKey(id, d)
+	if e, ok := w.sides[k]; ok {
+		return e
+	}
+	e := edges.None
+	if w.isLand(id) && w.isLand(w.neighbor(id, d)) {
+		switch r := w.rng.IntN(100); {
+		case r < 9:
+			e = edges.River
+		case r < 12:
+			e = edges.Ford
+		case r < 15 || (r < 40 && (w.hex(id).terrain.IsAnyMountain() || w.hex(w.neighbor(id, d)).terrain.IsAnyMountain())):
+			e = edges.Pass
+		}
+	}
+	w.sides[k] = e
+	return e
+}
+
+// road returns the road on the side of the hex.
+func (w *world) road(id string, d direction.Direction_e) edges.Edge_e {
+	k := w.sideKey(id, d)
+	if r, ok := w.roads[k]; ok {
+		return r
+	}
+	r := edges.None
+	if w.isLand(id) && w.isLand(w.neighbor(id, d)) && w.rng.IntN(100) < 7 {
+		r = []edges.Edge_e{edges.StoneRoad, edges.RuneRoad, edges.DirtRoad}[w.rng.IntN(3)]
+	}
+	w.roads[k] = r
+	return r
+}
+
+// quirkRoads lays out the roads that trigger the legacy road quirk:
+// two road types, with a later direction using the first type.
+func (w *world) quirkRoads(id string) {
+	first, second := edges.RuneRoad, edges.DirtRoad
+	if w.rng.IntN(2) == 0 {
+		first, second = edges.StoneRoad, edges.RuneRoad
+	}
+	for _, d := range direction.Directions {
+		w.setLand(w.neighbor(id, d))
+		w.roads[w.sideKey(id, d)] = edges.None
+	}
+	n := w.rng.IntN(2) // start at N or NE so there's room for three roads
+	w.roads[w.sideKey(id, direction.Directions[n])] = first
+	w.roads[w.sideKey(id, direction.Directions[n+1+w.rng.IntN(2)])] = second
+	w.roads[w.sideKey(id, direction.Directions[n+4])] = first
+}
+
+func (w *world) isLand(id string) bool {
+	t := w.hex(id).terrain
+	return t != terrain.WaterLake && t != terrain.WaterOcean
+}
+
+// cost returns the movement points needed to enter the hex.
+func (h *hex) cost(throughPass bool) int {
+	cost := h.terrain.MPCost()
+	if throughPass {
+		if _, p, ok := strings.Cut(cost, "P"); ok {
+			cost = p
+		}
+	}
+	n := 0
+	for n < len(cost) && '0' <= cost[n] && cost[n] <= '9' {
+		n++
+	}
+	mp, _ := strconv.Atoi(cost[:n])
+	return mp
+}
+
+var (
+	nameStarts = []string{"Ka", "Bre", "Tor", "Vel", "Mar", "Dun", "Har", "Sel", "Gor", "Thal", "Ves", "Bram", "Cor", "Fen", "Ys"}
+	nameEnds   = []string{"dor", "wick", "holm", "stead", "mere", "gard", "ford", "thorpe", "mont", "vale", "by", "ton"}
+)
+
+// settlementName returns a new name that no one will confuse with a
+// terrain, resource, or direction.
+func (w *world) settlementName() string {
+	for tries := 0; ; tries++ {
+		name := nameStarts[w.rng.IntN(len(nameStarts))] + nameEnds[w.rng.IntN(len(nameEnds))]
+		if tries > 20 {
+			// running out of two part names
+			name = nameStarts[w.rng.IntN(len(nameStarts))] + strings.ToLower(nameStarts[w.rng.IntN(len(nameStarts))]) + nameEnds[w.rng.IntN(len(nameEnds))]
+		}
+		if _, ok := resources.StringToEnum[name]; !ok && !w.names[name] {
+			w.names[name] = true
+			return name
+		}
+	}
+}
+
+func lower(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package lsp
+
+import (
+	"bytes"
+	"errors"
+	"unicode/utf8"
+
+	"github.com/playbymail/ottoapp/backend/services/reports/cst"
+	"github.com/playbymail/ottoapp/backend/services/reports/lexers"
+)
+
+// document is an open report extract.
+//
+// The CST parser only knows the unit, turn, and movement lines, so only
+// those lines are parsed. The other lines (scouts, status, inventory, and
+// so on) are not checked. Every line is lexed for hover and definitions.
+type document struct {
+	uri      string
+	version  int
+	lines    [][]byte        // the text, without the line-feeds
+	tokens   []*lexers.Token // every line
+	tree     *cst.TurnReportNode
+	lineMap  []int // line in the parsed text (0-based) to line in the document (1-based)
+	sections []*section
+}
+
+// section is a unit section in the report.
+type section struct {
+	keyword    string // Tribe, Courier, Element, Fleet, or Garrison
+	unitId     string
+	line       int // 1-based line of the unit line
+	endLine    int // 1-based line of the last line in the section
+	unitIdTok  *lexers.Token
+	currentHex string
+}
+
+func newDocument(uri string, version int, text []byte) *document {
+	d := &document{uri: uri, version: version}
+	d.lines = bytes.Split(text, []byte{'\n'})
+	d.tokens = lexers.Scan(text)
+
+	// build the text for the parser, keeping the columns the same
+	parsed := &bytes.Buffer{}
+	for n, line := range d.lines {
+		if cst.IsParsedLine(line) {
+			parsed.Write(line)
+			parsed.WriteByte('\n')
+			d.lineMap = append(d.lineMap, n+1)
+		}
+	}
+	d.tree = cst.Parse(lexers.Scan(parsed.Bytes()))
+
+	for _, sn := range d.tree.Sections {
+		ul := sn.UnitLine
+		if ul == nil || ul.Keyword == nil || ul.UnitID == nil {
+			continue
+		}
+		line, _ := ul.UnitID.Position()
+		s := &section{
+			keyword:   string(ul.Keyword.Bytes()),
+			unitId:    string(ul.UnitID.Bytes()),
+			line:      d.documentLine(line),
+			unitIdTok: ul.UnitID,
+		}
+		if gc, ok := ul.CurrentHex.(*cst.GridCoordsNode); ok && gc.Grid != nil && gc.Number != nil {
+			s.currentHex = string(gc.Grid.Bytes()) + " " + string(gc.Number.Bytes())
+		}
+		if n := len(d.sections); n != 0 {
+			d.sections[n-1].endLine = s.line - 1
+		}
+		d.sections = append(d.sections, s)
+	}
+	if n := len(d.sections); n != 0 {
+		d.sections[n-1].endLine = len(d.lines)
+	}
+
+	return d
+}
+
+// documentLine returns the line in the document for a line in the parsed text.
+func (d *document) documentLine(line int) int {
+	if 0 < line && line <= len(d.lineMap) {
+		return d.lineMap[line-1]
+	}
+	return len(d.lines)
+}
+
+// diagnostics returns the parse errors.
+func (d *document) diagnostics() []Diagnostic {
+	diagnostics := []Diagnostic{} // not nil, so that we send an empty list
+	for _, err := range d.tree.Errors() {
+		var pe *cst.Error
+		if !errors.As(err, &pe) {
+			continue
+		}
+		line := d.documentLine(pe.LineNo)
+		start, end := pe.ColNo, len(d.line(line))+1
+		if tok := d.tokenAt(line, start); tok != nil {
+			_, col := tok.Position()
+			start, end = col, col+tok.Length()
+		}
+		diagnostics = append(diagnostics, Diagnostic{
+			Range:    d.rangeOf(line, start, end),
+			Severity: severityError,
+			Source:   "ottoapp",
+			Message:  pe.Message,
+		})
+	}
+	return diagnostics
+}
+
+// symbols returns the unit sections.
+func (d *document) symbols() []DocumentSymbol {
+	symbols := []DocumentSymbol{}
+	for _, s := range d.sections {
+		_, col := s.unitIdTok.Position()
+		symbols = append(symbols, DocumentSymbol{
+			Name:           s.keyword + " " + s.unitId,
+			Detail:         s.currentHex,
+			Kind:           symbolModule,
+			Range:          d.rangeOf(s.line, 1, 1).to(d.rangeOf(s.endLine, len(d.line(s.endLine))+1, 1)),
+			SelectionRange: d.rangeOf(s.line, col, col+s.unitIdTok.Length()),
+		})
+	}
+	return symbols
+}
+
+// definition returns the location of the section for the unit id at the position.
+func (d *document) definition(pos Position) *Location {
+	line, col := d.column(pos)
+	tok := d.tokenAt(line, col)
+	if tok == nil || !isUnitId(tok) {
+		return nil
+	}
+	s := d.section(string(tok.Bytes()))
+	if s == nil {
+		return nil
+	}
+	_, start := s.unitIdTok.Position()
+	return &Location{URI: d.uri, Range: d.rangeOf(s.line, start, start+s.unitIdTok.Length())}
+}
+
+// section returns the section for the unit, or nil if there isn't one.
+func (d *document) section(unitId string) *section {
+	for _, s := range d.sections {
+		if s.unitId == unitId {
+			return s
+		}
+	}
+	return nil
+}
+
+// tokenAt returns the token that covers the 1-based line and byte column.
+func (d *document) tokenAt(line, col int) *lexers.Token {
+	for _, tok := range d.tokens {
+		if len(tok.Value) == 0 {
+			continue
+		}
+		tl, tc := tok.Position()
+		if tl == line && tc <= col && col < tc+tok.Length() {
+			return tok
+		} else if tl > line {
+			break
+		}
+	}
+	return nil
+}
+
+// line returns the text of the 1-based line.
+func (d *document) line(line int) []byte {
+	if 0 < line && line <= len(d.lines) {
+		return d.lines[line-1]
+	}
+	return nil
+}
+
+// rangeOf converts a 1-based line and byte columns to an LSP range.
+func (d *document) rangeOf(line, start, end int) Range {
+	return Range{
+		Start: Position{Line: line - 1, Character: d.character(line, start)},
+		End:   Position{Line: line - 1, Character: d.character(line, end)},
+	}
+}
+
+// to returns a range from the start of r to the end of other.
+func (r Range) to(other Range) Range {
+	return Range{Start: r.Start, End: other.End}
+}
+
+// character converts a 1-based byte column to UTF-16 code units.
+func (d *document) character(line, col int) int {
+	text := d.line(line)
+	if col-1 < len(text) {
+		text = text[:max(col-1, 0)]
+	}
+	n := 0
+	for len(text) > 0 {
+		r, size := utf8.DecodeRune(text)
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+		text = text[size:]
+	}
+	return n
+}
+
+// column converts an LSP position to a 1-based line and byte column.
+func (d *document) column(pos Position) (int, int) {
+	line := pos.Line + 1
+	text, col, n := d.line(line), 1, 0
+	for len(text) > 0 && n < pos.Character {
+		r, size := utf8.DecodeRune(text)
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+		text, col = text[size:], col+size
+	}
+	return line, col
+}
+
+// isUnitId returns true if the token is a unit id. The lexer only marks
+// a tribe id as a unit id when it follows "Tribe" or precedes "Status".
+func isUnitId(tok *lexers.Token) bool {
+	if tok.Kind == lexers.UnitId {
+		return true
+	}
+	return tok.Kind == lexers.Number && tok.Length() == 4
+}
@@ -0,0 +1,13 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package lsp
+
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+const (
+	ErrExitWithoutShutdown = Error("exit without shutdown")
+)
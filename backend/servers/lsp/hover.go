@@ -0,0 +1,133 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package lsp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/playbymail/ottoapp/backend/parsers/bistre/terrain"
+	"github.com/playbymail/ottoapp/backend/services/reports/lexers"
+)
+
+// hover returns the hover text for the token at the position.
+func (d *document) hover(pos Position) *Hover {
+	line, col := d.column(pos)
+	tok := d.tokenAt(line, col)
+	if tok == nil {
+		return nil
+	}
+	text := string(tok.Bytes())
+	var value string
+	switch {
+	case tok.Kind == lexers.TerrainCode:
+		value = terrainHover(text)
+	case tok.Kind == lexers.Direction:
+		value = fmt.Sprintf("**%s** direction: %s", text, directionNames[text])
+		if text == "SW" {
+			// the lexer can't tell the direction from the terrain
+			value += "\n\n" + terrainHover(text)
+		}
+	case isUnitId(tok):
+		value = d.unitHover(text)
+	}
+	if value == "" {
+		return nil
+	}
+	_, start := tok.Position()
+	r := d.rangeOf(line, start, start+tok.Length())
+	return &Hover{Contents: MarkupContent{Kind: "markdown", Value: value}, Range: &r}
+}
+
+// terrainHover describes a terrain code.
+func terrainHover(code string) string {
+	name, ok := terrainNames[strings.ToUpper(code)]
+	if !ok {
+		return ""
+	}
+	value := fmt.Sprintf("**%s** terrain: %s", code, name)
+	if t, ok := terrain.StringToTerrain(strings.ToUpper(code)); ok {
+		if cost := t.MPCost(); cost != "" {
+			value += fmt.Sprintf("\n\nMovement cost: %s", cost)
+		}
+	}
+	return value
+}
+
+// unitHover describes a unit and where its section is.
+func (d *document) unitHover(unitId string) string {
+	var kind string
+	switch {
+	case len(unitId) == 4:
+		kind = "tribe"
+		if unitId[0] == '0' {
+			kind = "clan"
+		}
+	case unitId[4] == 'c':
+		kind = "courier"
+	case unitId[4] == 'e':
+		kind = "element"
+	case unitId[4] == 'f':
+		kind = "fleet"
+	case unitId[4] == 'g':
+		kind = "garrison"
+	default:
+		return ""
+	}
+	value := fmt.Sprintf("**%s** %s", unitId, kind)
+	if len(unitId) > 4 {
+		value += fmt.Sprintf(" of tribe %s", unitId[:4])
+	}
+	if s := d.section(unitId); s != nil {
+		value += fmt.Sprintf("\n\nSection at line %d", s.line)
+		if s.currentHex != "" {
+			value += fmt.Sprintf(", current hex %s", s.currentHex)
+		}
+	} else {
+		value += "\n\nNo section for this unit in the report"
+	}
+	return value
+}
+
+var (
+	directionNames = map[string]string{
+		"N":  "north",
+		"NE": "north-east",
+		"SE": "south-east",
+		"S":  "south",
+		"SW": "south-west",
+		"NW": "north-west",
+	}
+
+	// terrainNames is the terrain as it is spelled on status lines.
+	terrainNames = map[string]string{
+		"ALPS": "ALPS",
+		"AH":   "ARID HILLS",
+		"AR":   "ARID",
+		"BF":   "BRUSH",
+		"BH":   "BRUSH HILLS",
+		"CH":   "CONIFER HILLS",
+		"D":    "DECIDUOUS FOREST",
+		"DE":   "DESERT",
+		"DH":   "DECIDUOUS HILLS",
+		"GH":   "GRASSY HILLS",
+		"GHP":  "GRASSY HILLS PLATEAU",
+		"HSM":  "HIGH SNOWY MOUNTAINS",
+		"JG":   "JUNGLE",
+		"JH":   "JUNGLE HILLS",
+		"L":    "LAKE",
+		"LAM":  "LOW ARID MOUNTAINS",
+		"LCM":  "LOW CONIFER MOUNTAINS",
+		"LJM":  "LOW JUNGLE MOUNTAINS",
+		"LSM":  "LOW SNOWY MOUNTAINS",
+		"LVM":  "LOW VOLCANIC MOUNTAINS",
+		"O":    "OCEAN",
+		"PI":   "POLAR ICE",
+		"PPR":  "PRAIRIE PLATEAU",
+		"PR":   "PRAIRIE",
+		"RH":   "ROCKY HILLS",
+		"SH":   "SNOWY HILLS",
+		"SW":   "SWAMP",
+		"TU":   "TUNDRA",
+	}
+)
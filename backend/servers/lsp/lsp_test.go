@@ -0,0 +1,153 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package lsp_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/servers/lsp"
+)
+
+const report = `Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)
+Current Turn 899-12 (#0), Winter, FINE
+Tribe Movement: Move N-CH, \
+Humans 1000
+0987 Status: CONIFER HILLS, 0987
+Element 0987e1, , Current Hex = QQ 1202, (Previous Hex = QQ 1203)
+Current Turn 899-12 (#0), Winter, FINE
+Tribe Follows 0987e1
+Courier 0987c1, , Current Hex = QQ 1202 (Previous Hex = QQ 1203)
+Current Turn 899-12 (#0), Winter, FINE
+`
+
+// message is a response or notification from the server.
+type message struct {
+	ID     *int            `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result"`
+	Error  *struct {
+		Code int `json:"code"`
+	} `json:"error"`
+}
+
+func TestServer(t *testing.T) {
+	input := &bytes.Buffer{}
+	id := 0
+	send := func(method string, params any) {
+		msg := map[string]any{"jsonrpc": "2.0", "method": method, "params": params}
+		if method != "initialized" && method != "exit" && !strings.HasPrefix(method, "textDocument/did") {
+			id++
+			msg["id"] = id
+		}
+		body, _ := json.Marshal(msg)
+		fmt.Fprintf(input, "Content-Length: %d\r\n\r\n%s", len(body), body)
+	}
+	const uri = "file:///0301.0899-12.0987.report.txt"
+	doc := map[string]any{"uri": uri}
+	at := func(line, character int) map[string]any {
+		return map[string]any{"textDocument": doc, "position": map[string]int{"line": line, "character": character}}
+	}
+	send("initialize", map[string]any{})                                                                     // 1
+	send("initialized", map[string]any{})                                                                    //
+	send("textDocument/didOpen", map[string]any{"textDocument": map[string]any{"uri": uri, "text": report}}) //
+	send("textDocument/hover", at(2, 23))                                                                    // 2: CH
+	send("textDocument/hover", at(2, 21))                                                                    // 3: N
+	send("textDocument/hover", at(7, 16))                                                                    // 4: 0987e1
+	send("textDocument/definition", at(7, 16))                                                               // 5
+	send("textDocument/documentSymbol", map[string]any{"textDocument": doc})                                 // 6
+	send("textDocument/formatting", map[string]any{"textDocument": doc})                                     // 7
+	send("shutdown", nil)                                                                                    // 8
+	send("exit", nil)
+
+	output := &bytes.Buffer{}
+	if err := lsp.New(input, output, "0.0.0", false).Serve(); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+
+	results := map[int]message{}
+	var diagnostics []message
+	r := bufio.NewReader(output)
+	for {
+		header, err := textproto.NewReader(r).ReadMIMEHeader()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		length, _ := strconv.Atoi(header.Get("Content-Length"))
+		body := make([]byte, length)
+		if _, err := io.ReadFull(r, body); err != nil {
+			t.Fatal(err)
+		}
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg.ID != nil {
+			results[*msg.ID] = msg
+		} else if msg.Method == "textDocument/publishDiagnostics" {
+			diagnostics = append(diagnostics, msg)
+		}
+	}
+
+	// the courier is missing a comma; nothing else is checked
+	if len(diagnostics) != 1 {
+		t.Fatalf("diagnostics: want 1 notification, got %d", len(diagnostics))
+	}
+	var published struct {
+		Diagnostics []lsp.Diagnostic `json:"diagnostics"`
+	}
+	_ = json.Unmarshal(diagnostics[0].Params, &published)
+	if len(published.Diagnostics) != 1 {
+		t.Fatalf("diagnostics: want 1, got %+v", published.Diagnostics)
+	} else if d := published.Diagnostics[0]; d.Range.Start.Line != 8 || d.Range.Start.Character != 40 || d.Message != "expected Comma, got LeftParen" {
+		t.Errorf("diagnostics: got %+v", d)
+	}
+
+	hover := func(id int) string {
+		var h lsp.Hover
+		_ = json.Unmarshal(results[id].Result, &h)
+		return h.Contents.Value
+	}
+	if got := hover(2); !strings.Contains(got, "CONIFER HILLS") {
+		t.Errorf("hover terrain: got %q", got)
+	}
+	if got := hover(3); !strings.Contains(got, "north") {
+		t.Errorf("hover direction: got %q", got)
+	}
+	if got := hover(4); !strings.Contains(got, "element of tribe 0987") || !strings.Contains(got, "line 6") {
+		t.Errorf("hover unit: got %q", got)
+	}
+
+	var location lsp.Location
+	_ = json.Unmarshal(results[5].Result, &location)
+	if location.URI != uri || location.Range.Start.Line != 5 || location.Range.Start.Character != 8 || location.Range.End.Character != 14 {
+		t.Errorf("definition: got %+v", location)
+	}
+
+	var symbols []lsp.DocumentSymbol
+	_ = json.Unmarshal(results[6].Result, &symbols)
+	var names []string
+	for _, s := range symbols {
+		names = append(names, fmt.Sprintf("%s %d-%d", s.Name, s.Range.Start.Line, s.Range.End.Line))
+	}
+	if got, want := strings.Join(names, ", "), "Tribe 0987 0-4, Element 0987e1 5-7, Courier 0987c1 8-10"; got != want {
+		t.Errorf("symbols: want %q, got %q", want, got)
+	}
+
+	if results[7].Error == nil || results[7].Error.Code != -32601 {
+		t.Errorf("formatting: want method not found, got %+v", results[7])
+	}
+	if _, ok := results[8]; !ok {
+		t.Errorf("shutdown: no response")
+	}
+}
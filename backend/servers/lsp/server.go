@@ -0,0 +1,211 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+// Package lsp implements a language server for turn report extracts.
+// It speaks JSON-RPC over a reader and writer (usually stdin and stdout)
+// and supports diagnostics, hover, go-to-definition, and document symbols.
+//
+// Requests are handled one at a time, in the order they are received.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+)
+
+type Server struct {
+	r        *bufio.Reader
+	w        io.Writer
+	version  string
+	docs     map[string]*document
+	shutdown bool
+	debug    bool
+}
+
+// New returns a server that reads requests from r and writes responses to w.
+// The version is reported to the client.
+func New(r io.Reader, w io.Writer, version string, debug bool) *Server {
+	return &Server{
+		r:       bufio.NewReader(r),
+		w:       w,
+		version: version,
+		docs:    map[string]*document{},
+		debug:   debug,
+	}
+}
+
+// Serve handles requests until the client sends "exit" or closes the input.
+// It returns an error if the client exits without asking for a shutdown.
+func (s *Server) Serve() error {
+	for {
+		body, err := readMessage(s.r)
+		if err == io.EOF {
+			if !s.shutdown {
+				return ErrExitWithoutShutdown
+			}
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		var req request
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := s.replyError(nil, codeParseError, err.Error()); err != nil {
+				return err
+			}
+			continue
+		}
+		if s.debug {
+			log.Printf("[lsp] %s %s\n", req.Method, string(req.ID))
+		}
+
+		if req.Method == "exit" {
+			if !s.shutdown {
+				return ErrExitWithoutShutdown
+			}
+			return nil
+		}
+		if err := s.handle(&req); err != nil {
+			return err
+		}
+	}
+}
+
+// handle dispatches a request. It only returns an error if the
+// response can't be written.
+func (s *Server) handle(req *request) error {
+	if s.shutdown && !req.isNotification() {
+		return s.replyError(req.ID, codeInvalidRequest, "server is shutting down")
+	}
+
+	switch req.Method {
+	case "initialize":
+		return s.reply(req.ID, InitializeResult{
+			Capabilities: ServerCapabilities{
+				TextDocumentSync:       TextDocumentSyncOptions{OpenClose: true, Change: syncFull},
+				HoverProvider:          true,
+				DefinitionProvider:     true,
+				DocumentSymbolProvider: true,
+			},
+			ServerInfo: &ServerInfo{Name: "ottoapp", Version: s.version},
+		})
+
+	case "initialized":
+		return nil
+
+	case "shutdown":
+		s.shutdown = true
+		return s.reply(req.ID, nil)
+
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			log.Printf("[lsp] %s: %v\n", req.Method, err)
+			return nil
+		}
+		doc := newDocument(params.TextDocument.URI, params.TextDocument.Version, []byte(params.TextDocument.Text))
+		s.docs[doc.uri] = doc
+		return s.publishDiagnostics(doc)
+
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			log.Printf("[lsp] %s: %v\n", req.Method, err)
+			return nil
+		} else if len(params.ContentChanges) == 0 {
+			return nil
+		}
+		// full sync, so the last change has the whole text
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		doc := newDocument(params.TextDocument.URI, params.TextDocument.Version, []byte(text))
+		s.docs[doc.uri] = doc
+		return s.publishDiagnostics(doc)
+
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			log.Printf("[lsp] %s: %v\n", req.Method, err)
+			return nil
+		}
+		delete(s.docs, params.TextDocument.URI)
+		// clear the diagnostics for the closed document
+		return s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []Diagnostic{}})
+
+	case "textDocument/hover":
+		doc, params, err := s.position(req)
+		if err != nil {
+			return s.replyError(req.ID, codeInvalidParams, err.Error())
+		} else if doc == nil {
+			return s.reply(req.ID, nil)
+		}
+		if hover := doc.hover(params.Position); hover != nil {
+			return s.reply(req.ID, hover)
+		}
+		return s.reply(req.ID, nil)
+
+	case "textDocument/definition":
+		doc, params, err := s.position(req)
+		if err != nil {
+			return s.replyError(req.ID, codeInvalidParams, err.Error())
+		} else if doc == nil {
+			return s.reply(req.ID, nil)
+		}
+		if location := doc.definition(params.Position); location != nil {
+			return s.reply(req.ID, location)
+		}
+		return s.reply(req.ID, nil)
+
+	case "textDocument/documentSymbol":
+		var params DocumentSymbolParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.replyError(req.ID, codeInvalidParams, err.Error())
+		}
+		doc, ok := s.docs[params.TextDocument.URI]
+		if !ok {
+			return s.reply(req.ID, []DocumentSymbol{})
+		}
+		return s.reply(req.ID, doc.symbols())
+	}
+
+	if req.isNotification() {
+		// unknown notifications are ignored
+		return nil
+	}
+	return s.replyError(req.ID, codeMethodNotFound, fmt.Sprintf("%s: method not found", req.Method))
+}
+
+// position returns the document and position for hover and definition requests.
+// The document is nil if it isn't open.
+func (s *Server) position(req *request) (*document, *TextDocumentPositionParams, error) {
+	var params TextDocumentPositionParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return nil, nil, err
+	}
+	return s.docs[params.TextDocument.URI], &params, nil
+}
+
+func (s *Server) publishDiagnostics(doc *document) error {
+	version := doc.version
+	return s.notify("textDocument/publishDiagnostics", PublishDiagnosticsParams{
+		URI:         doc.uri,
+		Version:     &version,
+		Diagnostics: doc.diagnostics(),
+	})
+}
+
+func (s *Server) reply(id json.RawMessage, result any) error {
+	return writeMessage(s.w, response{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (s *Server) replyError(id json.RawMessage, code int, message string) error {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	return writeMessage(s.w, errorResponse{JSONRPC: "2.0", ID: id, Error: &responseError{Code: code, Message: message}})
+}
+
+func (s *Server) notify(method string, params any) error {
+	return writeMessage(s.w, notification{JSONRPC: "2.0", Method: method, Params: params})
+}
@@ -3,12 +3,14 @@
 package rest
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/playbymail/ottoapp/backend/domains"
 	"github.com/playbymail/ottoapp/backend/restapi"
@@ -17,6 +19,7 @@ import (
 )
 
 // GetDocument returns a document for the current actor.
+// The actor must own the document or it must be shared with them.
 //
 // Route: GET /api/documents/{id}
 //
@@ -52,7 +55,11 @@ func GetDocument(authzSvc *authz.Service, documentsSvc *documents.Service, quiet
 			}
 			restapi.WriteJsonApiDatabaseError(w)
 			return
-		} else if clan.UserID != actor.ID {
+		}
+		if ok, err := documentsSvc.CanReadDocument(actor, clan, docId); err != nil {
+			restapi.WriteJsonApiDatabaseError(w)
+			return
+		} else if !ok {
 			restapi.WriteJsonApiError(w, http.StatusForbidden, "forbidden", "Forbidden", "You are not allowed access to this document.")
 			return
 		}
@@ -78,6 +85,7 @@ func GetDocument(authzSvc *authz.Service, documentsSvc *documents.Service, quiet
 }
 
 // GetDocumentContents returns the document contents for the current actor.
+// The actor must own the document or it must be shared with them.
 //
 // Route: GET /api/documents/{id}/contents
 //
@@ -113,7 +121,11 @@ func GetDocumentContents(authzSvc *authz.Service, documentsSvc *documents.Servic
 			}
 			restapi.WriteJsonApiDatabaseError(w)
 			return
-		} else if clan.UserID != actor.ID {
+		}
+		if ok, err := documentsSvc.CanReadDocument(actor, clan, docId); err != nil {
+			restapi.WriteJsonApiDatabaseError(w)
+			return
+		} else if !ok {
 			restapi.WriteJsonApiError(w, http.StatusForbidden, "forbidden", "Forbidden", "You are not allowed access to this document.")
 			return
 		}
@@ -130,13 +142,161 @@ func GetDocumentContents(authzSvc *authz.Service, documentsSvc *documents.Servic
 				fmt.Sprintf("Document with ID %d could not be found.", docId))
 			return
 		}
-		w.Header().Set("Content-Type", doc.ContentType)
-		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", doc.Path))
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write(doc.Contents)
+		// reverting sets modified_at back to the old file time, so use
+		// the later of the two times or If-Modified-Since would say that
+		// the reverted contents haven't changed.
+		modTime := doc.ModifiedAt
+		if doc.UpdatedAt.After(modTime) {
+			modTime = doc.UpdatedAt
+		}
+		serveContents(w, r, doc, modTime)
+	}
+}
+
+// GetDocumentRevisions returns the revisions of a document, newest first.
+// The revision routes use the revert policy so that the admins who can
+// revert a document can also see the revisions to pick from.
+//
+// Route: GET /api/documents/{id}/revisions
+//
+// Response type: []documents.RevisionView
+func GetDocumentRevisions(authzSvc *authz.Service, documentsSvc *documents.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, clan, docId, ok := authorizeDocument(w, r, authzSvc, documentsSvc, authzSvc.CanRevertDocuments, quiet, verbose, debug)
+		if !ok {
+			return
+		}
+		view, err := documentsSvc.ReadDocumentRevisions(actor, clan, docId, quiet, verbose, debug)
+		if err != nil {
+			log.Printf("%s %s: restapi: GetDocumentRevisions: %v\n", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiDatabaseError(w)
+			return
+		}
+		restapi.WriteJsonApiData(w, http.StatusOK, view)
 	}
 }
 
+// GetDocumentRevisionContents returns the contents of a revision of a document.
+//
+// Route: GET /api/documents/{id}/revisions/{revision}/contents
+//
+// Response type: depends on the content type
+func GetDocumentRevisionContents(authzSvc *authz.Service, documentsSvc *documents.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, clan, docId, ok := authorizeDocument(w, r, authzSvc, documentsSvc, authzSvc.CanRevertDocuments, quiet, verbose, debug)
+		if !ok {
+			return
+		}
+		revision, err := strconv.Atoi(r.PathValue("revision"))
+		if err != nil || revision < 1 {
+			restapi.WriteJsonApiMalformedPathParameter(w, "revision", "Revision", r.PathValue("revision"))
+			return
+		}
+
+		doc, err := documentsSvc.ReadDocumentRevisionContents(actor, clan, docId, revision, quiet, verbose, debug)
+		if err != nil {
+			if errors.Is(err, domains.ErrNotExists) {
+				restapi.WriteJsonApiError(w, http.StatusNotFound, "revision_not_found",
+					"Resource Not Found",
+					fmt.Sprintf("Document with ID %d has no revision %d.", docId, revision))
+				return
+			}
+			log.Printf("%s %s: restapi: GetDocumentRevisionContents: %v\n", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiDatabaseError(w)
+			return
+		}
+		serveContents(w, r, doc, doc.CreatedAt)
+	}
+}
+
+// PostDocumentRevisionRevert makes an old revision the current contents of a document.
+// The contents are copied to a new revision.
+//
+// Route: POST /api/documents/{id}/revisions/{revision}/revert
+//
+// Response type: documents.RevisionView
+func PostDocumentRevisionRevert(authzSvc *authz.Service, documentsSvc *documents.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, clan, docId, ok := authorizeDocument(w, r, authzSvc, documentsSvc, authzSvc.CanRevertDocuments, quiet, verbose, debug)
+		if !ok {
+			return
+		}
+		revision, err := strconv.Atoi(r.PathValue("revision"))
+		if err != nil || revision < 1 {
+			restapi.WriteJsonApiMalformedPathParameter(w, "revision", "Revision", r.PathValue("revision"))
+			return
+		}
+
+		view, err := documentsSvc.RevertDocument(actor, clan, docId, revision, quiet, verbose, debug)
+		if err != nil {
+			var reportErr *documents.ReportError
+			switch {
+			case errors.Is(err, domains.ErrNotExists):
+				restapi.WriteJsonApiError(w, http.StatusNotFound, "revision_not_found",
+					"Resource Not Found",
+					fmt.Sprintf("Document with ID %d has no revision %d.", docId, revision))
+			case errors.Is(err, domains.ErrNotAuthorized):
+				restapi.WriteJsonApiError(w, http.StatusForbidden, "forbidden", "Forbidden", "You are not allowed to change this document.")
+			case errors.As(err, &reportErr), errors.Is(err, documents.ErrWrongFormat):
+				restapi.WriteJsonApiError(w, http.StatusUnprocessableEntity, "invalid_revision", "Invalid Revision", err.Error())
+			default:
+				log.Printf("%s %s: restapi: PostDocumentRevisionRevert: %v\n", r.Method, r.URL.Path, err)
+				restapi.WriteJsonApiDatabaseError(w)
+			}
+			return
+		}
+		restapi.WriteJsonApiData(w, http.StatusCreated, view)
+	}
+}
+
+// authorizeDocumentOwner returns the actor, the clan that owns the document,
+// and the document id from the path. It writes the error response and returns
+// false if the actor isn't signed in or doesn't own the document.
+func authorizeDocumentOwner(w http.ResponseWriter, r *http.Request, authzSvc *authz.Service, documentsSvc *documents.Service, quiet, verbose, debug bool) (*domains.Actor, *domains.Clan, domains.ID, bool) {
+	return authorizeDocument(w, r, authzSvc, documentsSvc, func(actor, owner *domains.Actor) bool {
+		return actor.ID == owner.ID
+	}, quiet, verbose, debug)
+}
+
+// authorizeDocument is authorizeDocumentOwner with the policy passed in.
+// The policy is called with the actor and the user that owns the document.
+func authorizeDocument(w http.ResponseWriter, r *http.Request, authzSvc *authz.Service, documentsSvc *documents.Service, allowed func(actor, owner *domains.Actor) bool, quiet, verbose, debug bool) (*domains.Actor, *domains.Clan, domains.ID, bool) {
+	actor, err := authzSvc.GetActor(r)
+	if err != nil {
+		log.Printf("%s %s: restapi: GetActor: %v\n", r.Method, r.URL.Path, err)
+		restapi.WriteJsonApiError(w, http.StatusUnauthorized, "not_authenticated", "Unauthenticated", "Sign in to access this resource.")
+		return nil, nil, domains.InvalidID, false
+	} else if !actor.IsValid() {
+		restapi.WriteJsonApiError(w, http.StatusUnauthorized, "not_authenticated", "Unauthenticated", "Sign in to access this resource.")
+		return nil, nil, domains.InvalidID, false
+	}
+
+	var docId domains.ID = domains.InvalidID
+	if value, err := strconv.Atoi(r.PathValue("id")); err != nil {
+		restapi.WriteJsonApiMalformedPathParameter(w, "document_id", "Document ID", r.PathValue("id"))
+		return nil, nil, domains.InvalidID, false
+	} else {
+		docId = domains.ID(value)
+	}
+
+	clan, err := documentsSvc.ReadDocumentOwner(docId, quiet, verbose, debug)
+	if err != nil {
+		if errors.Is(err, domains.ErrNotExists) {
+			// not found, return a 404 response structured as a JSON:API error object
+			restapi.WriteJsonApiError(w, http.StatusNotFound, "document_not_found",
+				"Resource Not Found",
+				fmt.Sprintf("Document with ID %d could not be found.", docId))
+			return nil, nil, domains.InvalidID, false
+		}
+		restapi.WriteJsonApiDatabaseError(w)
+		return nil, nil, domains.InvalidID, false
+	} else if !allowed(actor, &domains.Actor{ID: clan.UserID}) {
+		restapi.WriteJsonApiError(w, http.StatusForbidden, "forbidden", "Forbidden", "You are not allowed access to this document.")
+		return nil, nil, domains.InvalidID, false
+	}
+	return actor, clan, docId, true
+}
+
 // GetDocumentList returns a list of documents for the current actor.
 //
 // Route: GET /api/documents
@@ -187,3 +347,80 @@ func GetDocumentList(authzSvc *authz.Service, documentsSvc *documents.Service, q
 		restapi.WriteJsonApiData(w, http.StatusOK, view)
 	}
 }
+
+// GetDocumentArchive returns the current actor's documents as a ZIP file.
+// The archive is built as it is sent, so errors after the headers are
+// written can only be logged.
+//
+// Route: GET /api/documents/archive
+// Query params:
+//   - filter[kind]=worldographer-map – only maps
+//   - filter[from]=0900-01 – only turns on or after this turn
+//   - filter[to]=0900-06 – only turns on or before this turn
+//
+// Response type: application/zip
+func GetDocumentArchive(authzSvc *authz.Service, documentsSvc *documents.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter := documents.ArchiveFilter{
+			Type:     domains.DocumentType(r.URL.Query().Get("filter[kind]")),
+			FromTurn: r.URL.Query().Get("filter[from]"),
+			ToTurn:   r.URL.Query().Get("filter[to]"),
+		}
+		if filter.Type != "" && !filter.Type.IsValid() {
+			restapi.WriteJsonApiInvalidQueryParameter(w, "filter_kind", "filter[kind]")
+			return
+		} else if filter.FromTurn != "" && !documents.IsTurnId(filter.FromTurn) {
+			restapi.WriteJsonApiInvalidQueryParameter(w, "filter_from", "filter[from]")
+			return
+		} else if filter.ToTurn != "" && !documents.IsTurnId(filter.ToTurn) {
+			restapi.WriteJsonApiInvalidQueryParameter(w, "filter_to", "filter[to]")
+			return
+		}
+
+		actor, err := authzSvc.GetActor(r)
+		if err != nil {
+			log.Printf("%s %s: restapi: GetActor: %v\n", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiError(w, http.StatusUnauthorized, "not_authenticated", "Unauthenticated", "Sign in to access this resource.")
+			return
+		} else if !actor.IsValid() {
+			restapi.WriteJsonApiError(w, http.StatusUnauthorized, "not_authenticated", "Unauthenticated", "Sign in to access this resource.")
+			return
+		}
+
+		entries, err := documentsSvc.ReadArchive(actor, actor.ID, filter, quiet, verbose, debug)
+		if err != nil {
+			if errors.Is(err, domains.ErrNotAuthorized) {
+				restapi.WriteJsonApiError(w, http.StatusForbidden, "forbidden", "Forbidden", "You are not allowed to export these documents.")
+				return
+			}
+			log.Printf("%s %s: restapi: ReadArchive: %v\n", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiInternalServerError(w)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "ottoapp-documents.zip"))
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.WriteHeader(http.StatusOK)
+		if err := documentsSvc.WriteArchive(w, entries, quiet, verbose, debug); err != nil {
+			log.Printf("%s %s: restapi: WriteArchive: %v\n", r.Method, r.URL.Path, err)
+		}
+	}
+}
+
+// serveContents writes the document contents with validators so that
+// clients can skip downloading contents they already have.
+//
+// The ETag is the hash of the contents, so it is a strong validator.
+// http.ServeContent handles If-None-Match, If-Modified-Since, and Range.
+// The contents are private to the owner, and clients must revalidate
+// before using a cached copy.
+func serveContents(w http.ResponseWriter, r *http.Request, doc *domains.Document, modTime time.Time) {
+	w.Header().Set("Content-Type", doc.ContentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", doc.Path))
+	w.Header().Set("Cache-Control", "private, no-cache")
+	if doc.ContentsHash != "" {
+		w.Header().Set("ETag", fmt.Sprintf("%q", doc.ContentsHash))
+	}
+	http.ServeContent(w, r, doc.Path, modTime, bytes.NewReader(doc.Contents))
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/servers/rest"
+)
+
+func TestServeContents(t *testing.T) {
+	modTime := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	doc := &domains.Document{
+		Path:         "0899-12.0987.report.txt",
+		ContentType:  "text/plain; charset=UTF-8",
+		ContentsHash: "abc123",
+		Contents:     []byte("0123456789"),
+	}
+	for _, tc := range []struct {
+		name   string
+		method string
+		header map[string]string
+		status int
+		body   string
+		want   map[string]string // response headers
+	}{
+		{name: "full", method: http.MethodGet, status: http.StatusOK, body: "0123456789",
+			want: map[string]string{"ETag": `"abc123"`, "Content-Length": "10", "Accept-Ranges": "bytes", "Last-Modified": "Sun, 18 Oct 2026 12:00:00 GMT"}},
+		{name: "head", method: http.MethodHead, status: http.StatusOK, body: "",
+			want: map[string]string{"ETag": `"abc123"`, "Content-Length": "10"}},
+		{name: "range", method: http.MethodGet, header: map[string]string{"Range": "bytes=2-5"}, status: http.StatusPartialContent, body: "2345",
+			want: map[string]string{"Content-Range": "bytes 2-5/10", "Content-Length": "4"}},
+		{name: "suffix range", method: http.MethodGet, header: map[string]string{"Range": "bytes=-3"}, status: http.StatusPartialContent, body: "789"},
+		{name: "unsatisfiable range", method: http.MethodGet, header: map[string]string{"Range": "bytes=20-30"}, status: http.StatusRequestedRangeNotSatisfiable},
+		{name: "if-none-match", method: http.MethodGet, header: map[string]string{"If-None-Match": `"abc123"`}, status: http.StatusNotModified, body: ""},
+		{name: "weak if-none-match", method: http.MethodGet, header: map[string]string{"If-None-Match": `W/"abc123"`}, status: http.StatusNotModified, body: ""},
+		{name: "other etag", method: http.MethodGet, header: map[string]string{"If-None-Match": `"def456"`}, status: http.StatusOK, body: "0123456789"},
+		{name: "if-modified-since", method: http.MethodGet, header: map[string]string{"If-Modified-Since": "Sun, 18 Oct 2026 12:00:00 GMT"}, status: http.StatusNotModified, body: ""},
+		{name: "modified since", method: http.MethodGet, header: map[string]string{"If-Modified-Since": "Sun, 18 Oct 2026 11:59:59 GMT"}, status: http.StatusOK, body: "0123456789"},
+		{name: "if-range matches", method: http.MethodGet, header: map[string]string{"Range": "bytes=0-1", "If-Range": `"abc123"`}, status: http.StatusPartialContent, body: "01"},
+		{name: "if-range stale", method: http.MethodGet, header: map[string]string{"Range": "bytes=0-1", "If-Range": `"def456"`}, status: http.StatusOK, body: "0123456789"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, "/api/documents/1/contents", nil)
+			for k, v := range tc.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			rest.ServeContents(w, r, doc, modTime)
+			if w.Code != tc.status {
+				t.Fatalf("status: got %d, want %d", w.Code, tc.status)
+			}
+			if tc.status != http.StatusRequestedRangeNotSatisfiable {
+				if got := w.Body.String(); got != tc.body {
+					t.Errorf("body: got %q, want %q", got, tc.body)
+				}
+			}
+			for k, v := range tc.want {
+				if got := w.Header().Get(k); got != v {
+					t.Errorf("%s: got %q, want %q", k, got, v)
+				}
+			}
+		})
+	}
+}
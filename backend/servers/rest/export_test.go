@@ -0,0 +1,7 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package rest
+
+// export the helpers for the tests in rest_test.
+
+var ServeContents = serveContents
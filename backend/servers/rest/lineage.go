@@ -0,0 +1,45 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/restapi"
+	"github.com/playbymail/ottoapp/backend/services/authz"
+	"github.com/playbymail/ottoapp/backend/services/dag"
+	"github.com/playbymail/ottoapp/backend/services/documents"
+)
+
+// GetDocumentLineage returns the documents that a document was derived
+// from and the documents derived from it, with the ones that are out of
+// date marked stale.
+//
+// Route: GET /api/documents/{id}/lineage
+//
+// Response type: []dag.NodeView
+func GetDocumentLineage(authzSvc *authz.Service, dagSvc *dag.Service, documentsSvc *documents.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, clan, docId, ok := authorizeDocumentOwner(w, r, authzSvc, documentsSvc, quiet, verbose, debug)
+		if !ok {
+			return
+		}
+		view, err := dagSvc.ReadDocumentLineage(actor, clan, docId, quiet, verbose, debug)
+		if err != nil {
+			if errors.Is(err, domains.ErrNotExists) {
+				restapi.WriteJsonApiError(w, http.StatusNotFound, "document_not_found",
+					"Resource Not Found",
+					fmt.Sprintf("Document with ID %d could not be found.", docId))
+				return
+			}
+			log.Printf("%s %s: restapi: GetDocumentLineage: %v\n", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiDatabaseError(w)
+			return
+		}
+		restapi.WriteJsonApiData(w, http.StatusOK, view)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/jsonapi"
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/restapi"
+	"github.com/playbymail/ottoapp/backend/services/authz"
+	"github.com/playbymail/ottoapp/backend/services/documents"
+)
+
+// GetDocumentLinks returns the public links for a document, including
+// expired and revoked links, with the number of times each was used.
+//
+// Route: GET /api/documents/{id}/links
+//
+// Response type: []documents.LinkView
+func GetDocumentLinks(authzSvc *authz.Service, documentsSvc *documents.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, clan, docId, ok := authorizeDocumentOwner(w, r, authzSvc, documentsSvc, quiet, verbose, debug)
+		if !ok {
+			return
+		}
+		view, err := documentsSvc.ReadDocumentLinks(actor, clan, docId, quiet, verbose, debug)
+		if err != nil {
+			if errors.Is(err, domains.ErrNotAuthorized) {
+				restapi.WriteJsonApiError(w, http.StatusForbidden, "forbidden", "Forbidden", "You are not allowed to share this document.")
+				return
+			}
+			log.Printf("%s %s: restapi: GetDocumentLinks: %v\n", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiDatabaseError(w)
+			return
+		}
+		restapi.WriteJsonApiData(w, http.StatusOK, view)
+	}
+}
+
+// PostDocumentLink creates a public link to the current revision of a
+// document. Later revisions are not visible through the link.
+//
+// Route: POST /api/documents/{id}/links
+//
+// Request type: document-link with attribute expires-at (optional, defaults to 7 days)
+//
+// Response type: documents.LinkView
+func PostDocumentLink(authzSvc *authz.Service, documentsSvc *documents.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	type linkRequest struct {
+		ID        string     `jsonapi:"primary,document-link"`
+		ExpiresAt *time.Time `jsonapi:"attr,expires-at,iso8601,omitempty"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, clan, docId, ok := authorizeDocumentOwner(w, r, authzSvc, documentsSvc, quiet, verbose, debug)
+		if !ok {
+			return
+		}
+
+		var p linkRequest
+		if err := jsonapi.UnmarshalPayload(r.Body, &p); err != nil {
+			log.Printf("%s %s: link: %v", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiError(w, http.StatusBadRequest, "bad_request", "Invalid Request Body", err.Error())
+			return
+		}
+		var expiresAt time.Time
+		if p.ExpiresAt != nil {
+			expiresAt = *p.ExpiresAt
+		}
+
+		view, err := documentsSvc.CreateDocumentLink(actor, clan, docId, expiresAt, quiet, verbose, debug)
+		if err != nil {
+			switch {
+			case errors.Is(err, domains.ErrNotAuthorized):
+				restapi.WriteJsonApiError(w, http.StatusForbidden, "forbidden", "Forbidden", "You are not allowed to share this document.")
+			case errors.Is(err, domains.ErrNotExists):
+				restapi.WriteJsonApiError(w, http.StatusNotFound, "document_not_found",
+					"Resource Not Found",
+					fmt.Sprintf("Document with ID %d could not be found.", docId))
+			case errors.Is(err, documents.ErrInvalidLink):
+				restapi.WriteJsonApiValidationErrors(w, restapi.ValidationDetail{
+					FieldName:   "ExpiresAt",
+					JsonPointer: "/data/attributes/expires-at",
+					Detail:      err.Error(),
+				})
+			default:
+				log.Printf("%s %s: restapi: PostDocumentLink: %v\n", r.Method, r.URL.Path, err)
+				restapi.WriteJsonApiDatabaseError(w)
+			}
+			return
+		}
+		restapi.WriteJsonApiData(w, http.StatusCreated, view)
+	}
+}
+
+// DeleteDocumentLink revokes a public link. The link is kept so that the
+// owner can see how often it was used.
+//
+// Route: DELETE /api/documents/{id}/links/{linkId}
+func DeleteDocumentLink(authzSvc *authz.Service, documentsSvc *documents.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, clan, docId, ok := authorizeDocumentOwner(w, r, authzSvc, documentsSvc, quiet, verbose, debug)
+		if !ok {
+			return
+		}
+		linkId, err := strconv.Atoi(r.PathValue("linkId"))
+		if err != nil || linkId < 1 {
+			restapi.WriteJsonApiMalformedPathParameter(w, "link_id", "Link ID", r.PathValue("linkId"))
+			return
+		}
+
+		err = documentsSvc.RevokeDocumentLink(actor, clan, docId, domains.ID(linkId), quiet, verbose, debug)
+		if err != nil {
+			switch {
+			case errors.Is(err, domains.ErrNotAuthorized):
+				restapi.WriteJsonApiError(w, http.StatusForbidden, "forbidden", "Forbidden", "You are not allowed to share this document.")
+			case errors.Is(err, domains.ErrNotExists):
+				restapi.WriteJsonApiError(w, http.StatusNotFound, "link_not_found",
+					"Resource Not Found",
+					fmt.Sprintf("Document with ID %d has no active link %d.", docId, linkId))
+			default:
+				log.Printf("%s %s: restapi: DeleteDocumentLink: %v\n", r.Method, r.URL.Path, err)
+				restapi.WriteJsonApiDatabaseError(w)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetSharedContents returns the contents of the document revision that a
+// public link points to. It does not require a session; the signed token
+// is the authorization. Bad, expired, and revoked tokens all get the same
+// 404 so that the response doesn't say which links once existed.
+//
+// Only full downloads are counted. HEAD requests, range requests, and
+// conditional requests that get a 304 don't add to the access count.
+//
+// Route: GET /api/shared/{token}
+//
+// Response type: raw document contents
+func GetSharedContents(documentsSvc *documents.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := r.PathValue("token")
+		doc, err := documentsSvc.ReadLinkedContents(token, quiet, verbose, debug)
+		if err == nil && r.Method == http.MethodGet && r.Header.Get("Range") == "" && !notModified(r, doc, doc.CreatedAt) {
+			err = documentsSvc.CountLinkAccess(token, quiet, verbose, debug)
+		}
+		if err != nil {
+			if errors.Is(err, domains.ErrNotExists) {
+				restapi.WriteJsonApiError(w, http.StatusNotFound, "link_not_found",
+					"Resource Not Found",
+					"The link is not valid or has expired.")
+				return
+			}
+			log.Printf("%s /api/shared: restapi: GetSharedContents: %v\n", r.Method, err)
+			restapi.WriteJsonApiDatabaseError(w)
+			return
+		}
+		// keep the token out of Referer headers and search engines
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set("X-Robots-Tag", "noindex")
+		serveContents(w, r, doc, doc.CreatedAt)
+	}
+}
+
+// notModified returns true if http.ServeContent will answer the conditional
+// request with a 304. If-None-Match is checked against the ETag that
+// serveContents sets; If-Modified-Since is only used without it.
+func notModified(r *http.Request, doc *domains.Document, modTime time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if doc.ContentsHash == "" {
+			return false
+		}
+		etag := fmt.Sprintf("%q", doc.ContentsHash)
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+			if tag == "*" || tag == etag {
+				return true
+			}
+		}
+		return false
+	}
+	ims, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || modTime.IsZero() {
+		return false
+	}
+	return !modTime.Truncate(time.Second).After(ims)
+}
@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package rest_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/servers/rest"
+	"github.com/playbymail/ottoapp/backend/services/documents"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite"
+)
+
+func TestGetSharedContents(t *testing.T) {
+	svc, actor, owner := newDocumentsService(t)
+	id, err := svc.ReplaceDocument(actor, owner, &domains.Document{
+		Path:       "0899-12.0987.wxx",
+		Type:       domains.WorldographerMap,
+		Contents:   []byte("map contents"),
+		ModifiedAt: time.Now().UTC(),
+	}, true, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	link, err := svc.CreateDocumentLink(actor, owner, id, time.Time{}, true, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/shared/{token}", rest.GetSharedContents(svc, true, false, false))
+
+	// only the full downloads are counted
+	for _, tc := range []struct {
+		name   string
+		method string
+		header map[string]string
+		status int
+		count  int
+	}{
+		{"get", http.MethodGet, nil, http.StatusOK, 1},
+		{"head", http.MethodHead, nil, http.StatusOK, 1},
+		{"other etag", http.MethodGet, map[string]string{"If-None-Match": `"not-the-hash"`}, http.StatusOK, 2},
+		{"not modified", http.MethodGet, map[string]string{"If-None-Match": "*"}, http.StatusNotModified, 2},
+		{"range", http.MethodGet, map[string]string{"Range": "bytes=0-2"}, http.StatusPartialContent, 2},
+		{"get again", http.MethodGet, nil, http.StatusOK, 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, link.Url, nil)
+			for k, v := range tc.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+			if w.Code != tc.status {
+				t.Fatalf("status: got %d, want %d", w.Code, tc.status)
+			}
+			links, err := svc.ReadDocumentLinks(actor, owner, id, true, false, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := links[0].AccessCount; got != tc.count {
+				t.Errorf("count: got %d, want %d", got, tc.count)
+			}
+		})
+	}
+
+	// bad tokens get a 404 and aren't counted
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/shared/not-a-token", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("bad token: got %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+// newDocumentsService returns a documents service on a new database
+// with one player, the player's clan, and a game for the clan.
+func newDocumentsService(t *testing.T) (*documents.Service, *domains.Actor, *domains.Clan) {
+	t.Helper()
+	ctx := context.Background()
+	db, err := sqlite.OpenTempDB(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	for _, stmt := range []string{
+		`INSERT INTO users (user_id, handle, username, email, timezone, is_active, is_user, created_at, updated_at)
+		 VALUES (2, 'alice', 'alice', 'alice@example.com', 'UTC', 1, 1, 1, 1)`,
+		`INSERT INTO games (game_id, code, description, active_turn, setup_turn, orders_due, created_at, updated_at)
+		 VALUES (1, '0301', 'test game', '0899-12', '0899-12', 1, 1, 1)`,
+		`INSERT INTO game_turns (game_id, turn, turn_year, turn_month, turn_no, created_at, updated_at)
+		 VALUES (1, '0899-12', 899, 12, 0, 1, 1)`,
+		`INSERT INTO clans (clan_id, game_id, user_id, clan, setup_turn, created_at, updated_at)
+		 VALUES (1, 1, 2, 987, '0899-12', 1, 1)`,
+	} {
+		if _, err := db.Stdlib().ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("fixture: %v", err)
+		}
+	}
+	svc, err := documents.New(db, nil, nil, nil, nil, true, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actor := &domains.Actor{ID: 2, Roles: domains.Roles{Active: true, User: true}}
+	owner := &domains.Clan{GameID: 1, UserID: 2, ClanID: 1, ClanNo: 987, IsActive: true}
+	return svc, actor, owner
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package rest
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/restapi"
+	"github.com/playbymail/ottoapp/backend/services/authz"
+	"github.com/playbymail/ottoapp/backend/services/reports"
+)
+
+// PostAdminReportsReparse re-parses the stored turn reports whose parse is stale.
+//
+// Route: POST /api/admin/reports/reparse
+//
+// Query parameters:
+//   - all=true re-parses every report, not just the stale ones
+//   - dry-run=true parses and compares without storing the results
+//   - workers=N sets the number of parsers to run at once
+//
+// Response type: reports.ReparseView
+func PostAdminReportsReparse(authzSvc *authz.Service, reportsSvc *reports.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, err := authzSvc.GetActor(r)
+		if err != nil {
+			log.Printf("%s %s: restapi: GetActor: %v\n", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiError(w, http.StatusUnauthorized, "not_authenticated", "Unauthenticated", "Sign in to access this resource.")
+			return
+		} else if !actor.IsValid() {
+			restapi.WriteJsonApiError(w, http.StatusUnauthorized, "not_authenticated", "Unauthenticated", "Sign in to access this resource.")
+			return
+		} else if !authzSvc.CanReparseReports(actor) {
+			restapi.WriteJsonApiError(w, http.StatusForbidden, "forbidden", "Forbidden", "You must have the admin role to re-parse reports.")
+			return
+		}
+
+		var opts reports.ReparseOptions
+		q := r.URL.Query()
+		if value := q.Get("all"); value != "" {
+			if opts.All, err = strconv.ParseBool(value); err != nil {
+				restapi.WriteJsonApiInvalidQueryParameter(w, "all", "all")
+				return
+			}
+		}
+		if value := q.Get("dry-run"); value != "" {
+			if opts.DryRun, err = strconv.ParseBool(value); err != nil {
+				restapi.WriteJsonApiInvalidQueryParameter(w, "dry_run", "dry-run")
+				return
+			}
+		}
+		if value := q.Get("workers"); value != "" {
+			if opts.Workers, err = strconv.Atoi(value); err != nil || opts.Workers < 1 {
+				restapi.WriteJsonApiInvalidQueryParameter(w, "workers", "workers")
+				return
+			}
+		}
+
+		startedAt := time.Now().UTC()
+		summary, err := reportsSvc.Reparse(opts, quiet, verbose, debug)
+		if err != nil {
+			log.Printf("%s %s: restapi: Reparse: %v\n", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiDatabaseError(w)
+			return
+		}
+		log.Printf("%s %s: reparse: %d checked: %d reparsed: %d changed: %d failed\n", r.Method, r.URL.Path, summary.Checked, summary.Reparsed, summary.Changed, summary.Failed)
+		restapi.WriteJsonApiData(w, http.StatusOK, reports.NewReparseView(summary, opts.DryRun, startedAt))
+	}
+}
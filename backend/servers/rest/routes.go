@@ -15,6 +15,7 @@ func Routes(s *Server, quiet, verbose, debug bool) http.Handler {
 	})
 	mux.HandleFunc("POST /api/login", handlePostLogin(s.services.authnSvc, s.services.authzSvc, s.services.sessionsSvc, s.services.usersSvc))
 	mux.HandleFunc("GET /api/session", handleGetSession(s.services.authzSvc, s.services.sessionsSvc))
+	mux.Handle("GET /api/shared/{token}", GetSharedContents(s.services.documentsSvc, quiet, verbose, debug))
 	mux.HandleFunc("POST /api/shutdown", s.handlePostShutdown(s.debug.shutdownKey))
 	mux.HandleFunc("GET /api/timezones", s.services.ianaSvc.HandleGetTimezones(true, false, false))
 	mux.HandleFunc("GET /api/versions", s.getAllVersions())
@@ -22,15 +23,31 @@ func Routes(s *Server, quiet, verbose, debug bool) http.Handler {
 
 	// Protected routes (authentication required)
 	protected := http.NewServeMux()
+	protected.Handle("POST /api/admin/reports/reparse", PostAdminReportsReparse(s.services.authzSvc, s.services.reportsSvc, quiet, verbose, debug))
 	protected.HandleFunc("GET /api/cookies/delete", s.services.sessionsSvc.DeleteCookie)
 	protected.Handle("GET /api/documents", GetDocumentList(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
+	protected.Handle("GET /api/documents/archive", GetDocumentArchive(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
 	protected.Handle("GET /api/documents/{id}", GetDocument(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
+	protected.Handle("DELETE /api/documents/{id}", DeleteDocument(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
 	protected.Handle("GET /api/documents/{id}/contents", GetDocumentContents(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
+	protected.Handle("GET /api/documents/{id}/lineage", GetDocumentLineage(s.services.authzSvc, s.services.dagSvc, s.services.documentsSvc, quiet, verbose, debug))
+	protected.Handle("GET /api/documents/{id}/links", GetDocumentLinks(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
+	protected.Handle("POST /api/documents/{id}/links", PostDocumentLink(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
+	protected.Handle("DELETE /api/documents/{id}/links/{linkId}", DeleteDocumentLink(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
+	protected.Handle("GET /api/documents/{id}/revisions", GetDocumentRevisions(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
+	protected.Handle("GET /api/documents/{id}/revisions/{revision}/contents", GetDocumentRevisionContents(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
+	protected.Handle("POST /api/documents/{id}/revisions/{revision}/revert", PostDocumentRevisionRevert(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
+	protected.Handle("GET /api/documents/{id}/shares", GetDocumentShares(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
+	protected.Handle("POST /api/documents/{id}/shares", PostDocumentShare(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
+	protected.Handle("DELETE /api/documents/{id}/shares/{shareId}", DeleteDocumentShare(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
 	protected.Handle("POST /api/games/{id}/turn-report-files", PostGamesTurnReportFiles(s.services.authzSvc, s.services.documentsSvc, s.services.gamesSvc, quiet, verbose, debug))
 	protected.HandleFunc("POST /api/logout", s.services.sessionsSvc.HandlePostLogout)
 	protected.HandleFunc("GET /api/my/profile", handleGetMyProfile(s.services.authzSvc, s.services.usersSvc))
 	protected.HandleFunc("GET /api/profile", handleGetProfile(s.services.authzSvc, s.services.usersSvc))
 	protected.HandleFunc("POST /api/profile", handlePostProfile(s.services.authzSvc, s.services.ianaSvc, s.services.usersSvc))
+	protected.Handle("GET /api/search", GetSearch(s.services.authzSvc, s.services.searchSvc, quiet, verbose, debug))
+	protected.Handle("GET /api/trash", GetTrash(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
+	protected.Handle("POST /api/trash/{id}/restore", PostTrashRestore(s.services.authzSvc, s.services.documentsSvc, quiet, verbose, debug))
 	protected.HandleFunc("GET /api/users", handleGetUsers(s.services.authzSvc, s.services.usersSvc))
 	protected.HandleFunc("POST /api/users", handlePostUser(s.services.authnSvc, s.services.authzSvc, s.services.usersSvc))
 	protected.HandleFunc("GET /api/users/me", handleGetMe(s.services.authzSvc, s.services.usersSvc))
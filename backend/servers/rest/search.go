@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package rest
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/playbymail/ottoapp/backend/restapi"
+	"github.com/playbymail/ottoapp/backend/services/authz"
+	"github.com/playbymail/ottoapp/backend/services/search"
+)
+
+// GetSearch searches the turn report extracts that the current actor can read.
+//
+// Route: GET /api/search
+// Query params:
+//   - q=copper mine – lines that contain all the terms; quote phrases
+//   - page[number], page[size] – standard pagination if needed
+//
+// Response type: []search.ResultView
+func GetSearch(authzSvc *authz.Service, searchSvc *search.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("q")
+		pageNumber, pageSize, err := restapi.GetPaginationParameters(r)
+		if err != nil {
+			if errors.Is(err, restapi.ErrInvalidPageNumber) {
+				restapi.WriteJsonApiInvalidQueryParameter(w, "page_number", "page[number]")
+			} else if errors.Is(err, restapi.ErrInvalidPageSize) {
+				restapi.WriteJsonApiInvalidQueryParameter(w, "page_size", "page[size]")
+			} else {
+				log.Printf("%s %s: restapi: GetPaginationParameters: %v\n", r.Method, r.URL.Path, err)
+				restapi.WriteJsonApiInternalServerError(w)
+			}
+			return
+		}
+
+		actor, err := authzSvc.GetActor(r)
+		if err != nil {
+			log.Printf("%s %s: restapi: GetActor: %v\n", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiError(w, http.StatusUnauthorized, "not_authenticated", "Unauthenticated", "Sign in to access this resource.")
+			return
+		} else if !actor.IsValid() {
+			restapi.WriteJsonApiError(w, http.StatusUnauthorized, "not_authenticated", "Unauthenticated", "Sign in to access this resource.")
+			return
+		}
+
+		view, err := searchSvc.Search(actor, query, pageNumber, pageSize, quiet, verbose, debug)
+		if err != nil {
+			if errors.Is(err, search.ErrEmptyQuery) {
+				restapi.WriteJsonApiInvalidQueryParameter(w, "q", "q")
+				return
+			}
+			log.Printf("%s %s: restapi: Search: %v\n", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiInternalServerError(w)
+			return
+		}
+		restapi.WriteJsonApiData(w, http.StatusOK, view)
+	}
+}
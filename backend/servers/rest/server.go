@@ -18,8 +18,11 @@ import (
 	"github.com/playbymail/ottoapp/backend/iana"
 	"github.com/playbymail/ottoapp/backend/services/authn"
 	"github.com/playbymail/ottoapp/backend/services/authz"
+	"github.com/playbymail/ottoapp/backend/services/dag"
 	"github.com/playbymail/ottoapp/backend/services/documents"
 	"github.com/playbymail/ottoapp/backend/services/games"
+	"github.com/playbymail/ottoapp/backend/services/reports"
+	"github.com/playbymail/ottoapp/backend/services/search"
 	"github.com/playbymail/ottoapp/backend/services/users"
 	"github.com/playbymail/ottoapp/backend/sessions"
 	"github.com/playbymail/ottoapp/backend/versions"
@@ -30,9 +33,12 @@ type Server struct {
 	services struct {
 		authnSvc     *authn.Service
 		authzSvc     *authz.Service
+		dagSvc       *dag.Service
 		documentsSvc *documents.Service
 		gamesSvc     *games.Service
 		ianaSvc      *iana.Service
+		reportsSvc   *reports.Service
+		searchSvc    *search.Service
 		sessionsSvc  *sessions.Service
 		usersSvc     *users.Service
 		versionsSvc  *versions.Service
@@ -58,8 +64,11 @@ type Server struct {
 func New(
 	authnSvc *authn.Service,
 	authzSvc *authz.Service,
+	dagSvc *dag.Service,
 	documentsSvc *documents.Service,
 	gamesSvc *games.Service,
+	reportsSvc *reports.Service,
+	searchSvc *search.Service,
 	sessionsSvc *sessions.Service,
 	tzSvc *iana.Service,
 	usersSvc *users.Service,
@@ -77,8 +86,11 @@ func New(
 	s.debug.debug = true
 	s.services.authnSvc = authnSvc
 	s.services.authzSvc = authzSvc
+	s.services.dagSvc = dagSvc
 	s.services.documentsSvc = documentsSvc
 	s.services.gamesSvc = gamesSvc
+	s.services.reportsSvc = reportsSvc
+	s.services.searchSvc = searchSvc
 	s.services.sessionsSvc = sessionsSvc
 	s.services.ianaSvc = tzSvc
 	s.services.usersSvc = usersSvc
@@ -100,12 +112,21 @@ func New(
 	} else if s.services.authzSvc == nil {
 		log.Printf("[rest] authzSvc not initialized")
 		return nil, domains.ErrInvalidArgument
+	} else if s.services.dagSvc == nil {
+		log.Printf("[rest] dagSvc not initialized")
+		return nil, domains.ErrInvalidArgument
 	} else if s.services.documentsSvc == nil {
 		log.Printf("[rest] documentsSvc not initialized")
 		return nil, domains.ErrInvalidArgument
 	} else if s.services.gamesSvc == nil {
 		log.Printf("[rest] gamesSvc not initialized")
 		return nil, domains.ErrInvalidArgument
+	} else if s.services.reportsSvc == nil {
+		log.Printf("[rest] reportsSvc not initialized")
+		return nil, domains.ErrInvalidArgument
+	} else if s.services.searchSvc == nil {
+		log.Printf("[rest] searchSvc not initialized")
+		return nil, domains.ErrInvalidArgument
 	} else if s.services.sessionsSvc == nil {
 		log.Printf("[rest] sessionsSvc not initialized")
 		return nil, domains.ErrInvalidArgument
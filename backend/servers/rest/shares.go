@@ -0,0 +1,143 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/jsonapi"
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/restapi"
+	"github.com/playbymail/ottoapp/backend/services/authz"
+	"github.com/playbymail/ottoapp/backend/services/documents"
+)
+
+// GetDocumentShares returns the clans and users a document is shared with,
+// including expired and revoked shares.
+//
+// Route: GET /api/documents/{id}/shares
+//
+// Response type: []documents.ShareView
+func GetDocumentShares(authzSvc *authz.Service, documentsSvc *documents.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, clan, docId, ok := authorizeDocumentOwner(w, r, authzSvc, documentsSvc, quiet, verbose, debug)
+		if !ok {
+			return
+		}
+		view, err := documentsSvc.ReadDocumentShares(actor, clan, docId, quiet, verbose, debug)
+		if err != nil {
+			if errors.Is(err, domains.ErrNotAuthorized) {
+				restapi.WriteJsonApiError(w, http.StatusForbidden, "forbidden", "Forbidden", "You are not allowed to share this document.")
+				return
+			}
+			log.Printf("%s %s: restapi: GetDocumentShares: %v\n", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiDatabaseError(w)
+			return
+		}
+		restapi.WriteJsonApiData(w, http.StatusOK, view)
+	}
+}
+
+// PostDocumentShare shares a document with another clan in the same game
+// or with another user. The request names either a clan or a user.
+//
+// Route: POST /api/documents/{id}/shares
+//
+// Request type: document-share with attributes clan or user, and expires-at (optional)
+//
+// Response type: documents.ShareView
+func PostDocumentShare(authzSvc *authz.Service, documentsSvc *documents.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	type shareRequest struct {
+		ID        string     `jsonapi:"primary,document-share"`
+		Clan      string     `jsonapi:"attr,clan,omitempty"`
+		User      string     `jsonapi:"attr,user,omitempty"`
+		ExpiresAt *time.Time `jsonapi:"attr,expires-at,iso8601,omitempty"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, clan, docId, ok := authorizeDocumentOwner(w, r, authzSvc, documentsSvc, quiet, verbose, debug)
+		if !ok {
+			return
+		}
+
+		var p shareRequest
+		if err := jsonapi.UnmarshalPayload(r.Body, &p); err != nil {
+			log.Printf("%s %s: share: %v", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiError(w, http.StatusBadRequest, "bad_request", "Invalid Request Body", err.Error())
+			return
+		}
+		grant := documents.ShareGrant{Handle: p.User}
+		if p.Clan != "" {
+			clanNo, err := strconv.Atoi(p.Clan)
+			if err != nil || clanNo < 1 || clanNo > 999 {
+				restapi.WriteJsonApiValidationErrors(w, restapi.ValidationDetail{
+					FieldName:   "Clan",
+					JsonPointer: "/data/attributes/clan",
+					Detail:      "must be a clan number",
+				})
+				return
+			}
+			grant.ClanNo = clanNo
+		}
+		if p.ExpiresAt != nil {
+			grant.ExpiresAt = *p.ExpiresAt
+		}
+
+		view, err := documentsSvc.ShareDocument(actor, clan, docId, grant, quiet, verbose, debug)
+		if err != nil {
+			switch {
+			case errors.Is(err, domains.ErrNotAuthorized):
+				restapi.WriteJsonApiError(w, http.StatusForbidden, "forbidden", "Forbidden", "You are not allowed to share this document.")
+			case errors.Is(err, domains.ErrNotExists):
+				restapi.WriteJsonApiError(w, http.StatusUnprocessableEntity, "invalid_share", "Invalid Share", "The clan or user could not be found.")
+			case errors.Is(err, documents.ErrInvalidShare):
+				restapi.WriteJsonApiError(w, http.StatusUnprocessableEntity, "invalid_share", "Invalid Share", err.Error())
+			default:
+				log.Printf("%s %s: restapi: PostDocumentShare: %v\n", r.Method, r.URL.Path, err)
+				restapi.WriteJsonApiDatabaseError(w)
+			}
+			return
+		}
+		restapi.WriteJsonApiData(w, http.StatusCreated, view)
+	}
+}
+
+// DeleteDocumentShare revokes a share. The share is kept so that the owner
+// can see who had access.
+//
+// Route: DELETE /api/documents/{id}/shares/{shareId}
+func DeleteDocumentShare(authzSvc *authz.Service, documentsSvc *documents.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, clan, docId, ok := authorizeDocumentOwner(w, r, authzSvc, documentsSvc, quiet, verbose, debug)
+		if !ok {
+			return
+		}
+		shareId, err := strconv.Atoi(r.PathValue("shareId"))
+		if err != nil || shareId < 1 {
+			restapi.WriteJsonApiMalformedPathParameter(w, "share_id", "Share ID", r.PathValue("shareId"))
+			return
+		}
+
+		err = documentsSvc.RevokeDocumentShare(actor, clan, docId, domains.ID(shareId), quiet, verbose, debug)
+		if err != nil {
+			switch {
+			case errors.Is(err, domains.ErrNotAuthorized):
+				restapi.WriteJsonApiError(w, http.StatusForbidden, "forbidden", "Forbidden", "You are not allowed to share this document.")
+			case errors.Is(err, domains.ErrNotExists):
+				restapi.WriteJsonApiError(w, http.StatusNotFound, "share_not_found",
+					"Resource Not Found",
+					fmt.Sprintf("Document with ID %d has no active share %d.", docId, shareId))
+			default:
+				log.Printf("%s %s: restapi: DeleteDocumentShare: %v\n", r.Method, r.URL.Path, err)
+				restapi.WriteJsonApiDatabaseError(w)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
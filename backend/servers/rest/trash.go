@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/restapi"
+	"github.com/playbymail/ottoapp/backend/services/authz"
+	"github.com/playbymail/ottoapp/backend/services/documents"
+)
+
+// DeleteDocument moves a document to the trash. It can be restored until
+// the purge job deletes it.
+//
+// Route: DELETE /api/documents/{id}
+func DeleteDocument(authzSvc *authz.Service, documentsSvc *documents.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, clan, docId, ok := authorizeDocumentOwner(w, r, authzSvc, documentsSvc, quiet, verbose, debug)
+		if !ok {
+			return
+		}
+		err := documentsSvc.DeleteDocument(actor, clan, docId)
+		if err != nil {
+			if errors.Is(err, domains.ErrNotAuthorized) {
+				restapi.WriteJsonApiError(w, http.StatusForbidden, "forbidden", "Forbidden", "You are not allowed to delete this document.")
+				return
+			}
+			log.Printf("%s %s: restapi: DeleteDocument: %v\n", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiDatabaseError(w)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// GetTrash returns the documents in the trash. GMs see every deleted
+// document; other users see their own.
+//
+// Route: GET /api/trash
+//
+// Response type: []documents.TrashView
+func GetTrash(authzSvc *authz.Service, documentsSvc *documents.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, err := authzSvc.GetActor(r)
+		if err != nil {
+			log.Printf("%s %s: restapi: GetActor: %v\n", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiError(w, http.StatusUnauthorized, "not_authenticated", "Unauthenticated", "Sign in to access this resource.")
+			return
+		} else if !actor.IsValid() {
+			restapi.WriteJsonApiError(w, http.StatusUnauthorized, "not_authenticated", "Unauthenticated", "Sign in to access this resource.")
+			return
+		}
+		view, err := documentsSvc.ReadDeletedDocuments(actor, quiet, verbose, debug)
+		if err != nil {
+			log.Printf("%s %s: restapi: GetTrash: %v\n", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiDatabaseError(w)
+			return
+		}
+		restapi.WriteJsonApiData(w, http.StatusOK, view)
+	}
+}
+
+// PostTrashRestore takes a document out of the trash.
+//
+// Route: POST /api/trash/{id}/restore
+func PostTrashRestore(authzSvc *authz.Service, documentsSvc *documents.Service, quiet, verbose, debug bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actor, err := authzSvc.GetActor(r)
+		if err != nil {
+			log.Printf("%s %s: restapi: GetActor: %v\n", r.Method, r.URL.Path, err)
+			restapi.WriteJsonApiError(w, http.StatusUnauthorized, "not_authenticated", "Unauthenticated", "Sign in to access this resource.")
+			return
+		} else if !actor.IsValid() {
+			restapi.WriteJsonApiError(w, http.StatusUnauthorized, "not_authenticated", "Unauthenticated", "Sign in to access this resource.")
+			return
+		}
+		docId, err := strconv.Atoi(r.PathValue("id"))
+		if err != nil || docId < 1 {
+			restapi.WriteJsonApiMalformedPathParameter(w, "document_id", "Document ID", r.PathValue("id"))
+			return
+		}
+
+		err = documentsSvc.RestoreDocument(actor, domains.ID(docId), quiet, verbose, debug)
+		if err != nil {
+			switch {
+			case errors.Is(err, domains.ErrNotAuthorized):
+				restapi.WriteJsonApiError(w, http.StatusForbidden, "forbidden", "Forbidden", "You are not allowed to restore this document.")
+			case errors.Is(err, domains.ErrNotExists):
+				restapi.WriteJsonApiError(w, http.StatusNotFound, "document_not_found",
+					"Resource Not Found",
+					fmt.Sprintf("Document with ID %d is not in the trash.", docId))
+			default:
+				log.Printf("%s %s: restapi: PostTrashRestore: %v\n", r.Method, r.URL.Path, err)
+				restapi.WriteJsonApiDatabaseError(w)
+			}
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -36,6 +36,77 @@ func (s *Service) CanCreateDocuments(actor *domains.Actor) bool {
 	return actor.IsValid()
 }
 
+// CanReadDocument returns true if the actor can read a document.
+// Rules: users can read their own documents and documents shared with them.
+func (s *Service) CanReadDocument(actor, owner *domains.Actor, isShared bool) bool {
+	if !actor.IsValid() {
+		return false
+	}
+	return actor.ID == owner.ID || isShared
+}
+
+// CanShareDocuments returns true if the actor can share the owner's
+// documents with other clans and users or through public links, or
+// revoke the shares and links.
+// Rules: users can share their own documents, sysop can manage anyone's.
+func (s *Service) CanShareDocuments(actor, owner *domains.Actor) bool {
+	if actor.IsSysop() {
+		return true
+	}
+	return actor.IsValid() && actor.ID == owner.ID
+}
+
+// CanExportDocuments returns true if the actor can download all of the
+// target's documents in one archive.
+// Rules: users can export their own documents, sysop can export anyone's.
+func (s *Service) CanExportDocuments(actor, target *domains.Actor) bool {
+	if actor.IsSysop() {
+		return true
+	}
+	return actor.IsValid() && actor.ID == target.ID
+}
+
+// CanRevertDocuments returns true if the actor can make an old revision
+// the current contents of the owner's documents.
+// Rules: users can revert their own documents, admins and sysop can revert anyone's.
+func (s *Service) CanRevertDocuments(actor, owner *domains.Actor) bool {
+	if actor.IsSysop() || actor.IsAdmin() {
+		return true
+	}
+	return actor.IsValid() && actor.ID == owner.ID
+}
+
+// CanDeleteDocuments returns true if the actor can move the owner's
+// documents to the trash.
+// Rules: users can delete their own documents, sysop can delete anyone's.
+func (s *Service) CanDeleteDocuments(actor, owner *domains.Actor) bool {
+	if actor.IsSysop() {
+		return true
+	}
+	return actor.IsValid() && actor.ID == owner.ID
+}
+
+// CanRestoreDocuments returns true if the actor can see the owner's
+// documents in the trash and restore them.
+// Rules: users can restore their own documents, GMs and sysop can restore anyone's.
+func (s *Service) CanRestoreDocuments(actor, owner *domains.Actor) bool {
+	if actor.IsSysop() || actor.IsGM() {
+		return true
+	}
+	return actor.IsValid() && actor.ID == owner.ID
+}
+
+// CanListAllDeletedDocuments returns true if the actor can see every
+// document in the trash, not just their own.
+func (s *Service) CanListAllDeletedDocuments(actor *domains.Actor) bool {
+	return actor.IsSysop() || actor.IsGM()
+}
+
+// CanPurgeDocuments returns true if the actor can empty the trash.
+func (s *Service) CanPurgeDocuments(actor *domains.Actor) bool {
+	return actor.IsSysop()
+}
+
 // CanCreateTarget checks if actor can create new users.
 func (s *Service) CanCreateTarget(actor *domains.Actor) bool {
 	if actor.IsSysop() {
@@ -181,6 +252,18 @@ func (s *Service) CanResetTargetCredentials(actor, target *domains.Actor) bool {
 	return true
 }
 
+// CanReparseReports returns true if the actor can re-parse the stored turn reports.
+func (s *Service) CanReparseReports(actor *domains.Actor) bool {
+	if actor.IsSysop() {
+		// sysop can always re-parse reports
+		return true
+	}
+	// from here down, sysop is impossible
+
+	// re-parsing requires the admin role
+	return actor.IsAdmin()
+}
+
 func (s *Service) CanShutdownServer(actor *domains.Actor) bool {
 	if actor.IsSysop() {
 		// sysop can server
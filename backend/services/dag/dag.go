@@ -3,14 +3,14 @@
 package dag
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"log"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"time"
-
-	"github.com/playbymail/ottoapp/backend/services/documents"
 )
 
 // Node is a file in the dependency graph
@@ -112,8 +112,8 @@ func (g *Graph) ReadReportExtractFiles(gameId, path string, quiet, verbose, debu
 
 		if data, err := os.ReadFile(node.Path); err != nil {
 			return err
-		} else if _, node.ID, err = documents.Hash(data); err != nil {
-			return err
+		} else {
+			node.ID = hash(data)
 		}
 
 		g.Nodes[node.ID] = node
@@ -182,8 +182,8 @@ func (g *Graph) ReadTurnReportFiles(gameId, path string, quiet, verbose, debug b
 
 		if data, err := os.ReadFile(node.Path); err != nil {
 			return err
-		} else if _, node.ID, err = documents.Hash(data); err != nil {
-			return err
+		} else {
+			node.ID = hash(data)
 		}
 
 		g.Nodes[node.ID] = node
@@ -191,3 +191,10 @@ func (g *Graph) ReadTurnReportFiles(gameId, path string, quiet, verbose, debug b
 
 	return nil
 }
+
+// hash returns the hex encoded SHA-256 of the contents, the same hash the
+// documents service stores.
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package dag
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/jsonapi"
+)
+
+// NodeView is the JSON:API view for a document in a lineage.
+type NodeView struct {
+	ID           string     `jsonapi:"primary,lineage-node"` // document id
+	DocumentName string     `jsonapi:"attr,document-name"`
+	DocumentType string     `jsonapi:"attr,document-type"`
+	ContentsHash string     `jsonapi:"attr,contents-hash"`
+	DependsOn    []string   `jsonapi:"attr,depends-on"` // ids of the documents this one was derived from
+	IsStale      bool       `jsonapi:"attr,is-stale"`   // true if a document above this one changed since it was stored
+	StaleSince   *time.Time `jsonapi:"attr,stale-since,iso8601,omitempty"`
+	Parser       string     `jsonapi:"attr,parser,omitempty"` // name and version of the parser, for parsed extracts
+	ParseError   string     `jsonapi:"attr,parse-error,omitempty"`
+	IsParseStale bool       `jsonapi:"attr,is-parse-stale,omitempty"` // true if the extract changed since it was parsed
+	UpdatedAt    time.Time  `jsonapi:"attr,updated-at,iso8601"`
+}
+
+// JSONAPILinks implements the jsonapi.Linkable interface for lineage-node-links
+func (n *NodeView) JSONAPILinks() *jsonapi.Links {
+	return &jsonapi.Links{
+		"document": fmt.Sprintf("/api/documents/%s", n.ID),
+		"lineage":  fmt.Sprintf("/api/documents/%s/lineage", n.ID),
+	}
+}
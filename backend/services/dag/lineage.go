@@ -0,0 +1,297 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package dag
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+// Relations between the documents in a lineage.
+const (
+	// Extracted is the edge from a turn report file to the report
+	// extract that was taken from it.
+	Extracted = "extracted"
+
+	// Mapped is the edge from a report extract to a map. Maps are
+	// cumulative, so a map depends on the extracts for its turn and
+	// for every turn before it.
+	Mapped = "mapped"
+)
+
+var (
+	// document name is {game}.{turnNo}.{unitId}.{suffix}
+	reDocumentName = regexp.MustCompile(`^(\d{4})\.(\d{4}-\d{2})\.(\d{4}([cefg][1-9])?)\.(.+)$`)
+)
+
+// Document is a clan's document that may be part of a lineage.
+type Document struct {
+	ID   int64
+	Name string
+	Type domains.DocumentType
+}
+
+// Edge is a derivation from the upstream document to the downstream document.
+type Edge struct {
+	Upstream   int64
+	Downstream int64
+	Relation   string
+}
+
+// Edges returns the edges between the document and the clan's other
+// documents. The edges are found from the document names, so documents
+// that don't follow the {game}.{turnNo}.{unitId} naming convention
+// aren't part of any lineage.
+func Edges(doc Document, docs []Document) []Edge {
+	game, turn, unit, ok := splitName(doc.Name)
+	if !ok {
+		return nil
+	}
+	var edges []Edge
+	for _, other := range docs {
+		if other.ID == doc.ID {
+			continue
+		}
+		oGame, oTurn, oUnit, ok := splitName(other.Name)
+		if !ok || oGame != game {
+			continue
+		}
+		switch {
+		case doc.Type == domains.TurnReportFile && other.Type == domains.TurnReportExtract && oTurn == turn && oUnit == unit:
+			edges = append(edges, Edge{Upstream: doc.ID, Downstream: other.ID, Relation: Extracted})
+		case doc.Type == domains.TurnReportExtract && other.Type == domains.TurnReportFile && oTurn == turn && oUnit == unit:
+			edges = append(edges, Edge{Upstream: other.ID, Downstream: doc.ID, Relation: Extracted})
+		case doc.Type == domains.TurnReportExtract && other.Type == domains.WorldographerMap && turn <= oTurn:
+			edges = append(edges, Edge{Upstream: doc.ID, Downstream: other.ID, Relation: Mapped})
+		case doc.Type == domains.WorldographerMap && other.Type == domains.TurnReportExtract && oTurn <= turn:
+			edges = append(edges, Edge{Upstream: other.ID, Downstream: doc.ID, Relation: Mapped})
+		}
+	}
+	return edges
+}
+
+// Record updates the lineage after a document gets new contents.
+// It adds the edges to and from the document and marks the edges into the
+// document as up to date. If the document changed, everything below it is
+// marked stale; a new document's first contents don't make anything stale.
+// Callers should run this in the same transaction that updates the
+// document's contents.
+func Record(ctx context.Context, q *sqlc.Queries, documentId int64, changed bool, now int64) error {
+	if err := link(ctx, q, documentId, now); err != nil {
+		return err
+	}
+	err := q.RefreshDocumentLineage(ctx, sqlc.RefreshDocumentLineageParams{
+		UpdatedAt:  now,
+		DocumentID: documentId,
+	})
+	if err != nil {
+		return err
+	} else if !changed {
+		return nil
+	}
+	return MarkStale(ctx, q, documentId, now)
+}
+
+// MarkStale marks everything below the document as stale. Record does this
+// when the contents change; the reports service does it when a reparse
+// changes an extract's output, since the maps are drawn from the output.
+func MarkStale(ctx context.Context, q *sqlc.Queries, documentId int64, now int64) error {
+	return q.MarkDocumentLineageStale(ctx, sqlc.MarkDocumentLineageStaleParams{
+		DocumentID: documentId,
+		StaleAt:    sql.NullInt64{Int64: now, Valid: true},
+	})
+}
+
+// link adds the missing edges to and from the document.
+// Existing edges are not changed.
+func link(ctx context.Context, q *sqlc.Queries, documentId int64, now int64) error {
+	d, err := q.ReadDocumentById(ctx, documentId)
+	if err != nil {
+		return err
+	}
+	docs, err := readDocuments(ctx, q, d.ClanID)
+	if err != nil {
+		return err
+	}
+	doc := Document{ID: d.DocumentID, Name: d.DocumentName, Type: domains.DocumentType(d.DocumentType)}
+	for _, edge := range Edges(doc, docs) {
+		err = q.CreateDocumentLineage(ctx, sqlc.CreateDocumentLineageParams{
+			DownstreamID: edge.Downstream,
+			Relation:     edge.Relation,
+			CreatedAt:    now,
+			UpstreamID:   edge.Upstream,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readDocuments returns the clan's documents that aren't in the trash.
+func readDocuments(ctx context.Context, q *sqlc.Queries, clanId int64) ([]Document, error) {
+	rows, err := q.ReadLineageDocuments(ctx, clanId)
+	if err != nil {
+		return nil, err
+	}
+	var docs []Document
+	for _, row := range rows {
+		docs = append(docs, Document{ID: row.DocumentID, Name: row.DocumentName, Type: domains.DocumentType(row.DocumentType)})
+	}
+	return docs, nil
+}
+
+// splitName returns the game, turn, and unit from a document name.
+func splitName(name string) (game, turn, unit string, ok bool) {
+	m := reDocumentName.FindStringSubmatch(name)
+	if m == nil {
+		return "", "", "", false
+	}
+	return m[1], m[2], m[3], true
+}
+
+// ReadDocumentLineage returns the documents above and below a document,
+// from the turn report files down to the maps. The document is included
+// even if it isn't part of any lineage.
+// The caller must check that the actor can read the owner's documents.
+func (s *Service) ReadDocumentLineage(actor *domains.Actor, owner *domains.Clan, documentId domains.ID, quiet, verbose, debug bool) ([]*NodeView, error) {
+	if debug {
+		log.Printf("[dag] ReadDocumentLineage(%d, (%d, %d), %d)\n", actor.ID, owner.GameID, owner.ClanID, documentId)
+	}
+	ctx := s.db.Context()
+	rows, err := s.db.Queries().ReadDocumentLineage(ctx, int64(documentId))
+	if err != nil {
+		log.Printf("[dag] ReadDocumentLineage(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	nodes := map[int64]*NodeView{}
+	node := func(id int64) (*NodeView, error) {
+		if view, ok := nodes[id]; ok {
+			return view, nil
+		}
+		row, err := s.db.Queries().ReadLineageNode(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		view := &NodeView{
+			ID:           fmt.Sprintf("%d", row.DocumentID),
+			DocumentName: row.DocumentName,
+			DocumentType: row.DocumentType,
+			ContentsHash: row.ContentsHash,
+			DependsOn:    []string{},
+			UpdatedAt:    time.Unix(row.UpdatedAt, 0).UTC(),
+		}
+		if row.ParserName.Valid {
+			view.Parser = fmt.Sprintf("%s %d.%d.%d", row.ParserName.String, row.ParserMajor.Int64, row.ParserMinor.Int64, row.ParserPatch.Int64)
+			view.ParseError = row.ParseError.String
+			view.IsParseStale = row.ParsedHash.String != row.ContentsHash
+		}
+		nodes[id] = view
+		return view, nil
+	}
+	if _, err := node(int64(documentId)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domains.ErrNotExists
+		}
+		log.Printf("[dag] ReadDocumentLineage(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	for _, row := range rows {
+		if _, err = node(row.UpstreamID); err != nil {
+			log.Printf("[dag] ReadDocumentLineage(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+			return nil, errors.Join(domains.ErrDatabaseError, err)
+		}
+		view, err := node(row.DownstreamID)
+		if err != nil {
+			log.Printf("[dag] ReadDocumentLineage(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+			return nil, errors.Join(domains.ErrDatabaseError, err)
+		}
+		view.DependsOn = append(view.DependsOn, fmt.Sprintf("%d", row.UpstreamID))
+		if row.StaleAt.Valid {
+			staleAt := time.Unix(row.StaleAt.Int64, 0).UTC()
+			if !view.IsStale || staleAt.Before(*view.StaleSince) {
+				view.StaleSince = &staleAt
+			}
+			view.IsStale = true
+		}
+	}
+	list := []*NodeView{}
+	for _, view := range nodes {
+		list = append(list, view)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if a, b := stage(list[i].DocumentType), stage(list[j].DocumentType); a != b {
+			return a < b
+		}
+		return list[i].DocumentName < list[j].DocumentName
+	})
+	return list, nil
+}
+
+// Relink adds the missing edges for every document. Edges that already
+// exist are not changed, and nothing is marked stale.
+// Returns the number of documents linked.
+func (s *Service) Relink(actor *domains.Actor, quiet, verbose, debug bool) (int, error) {
+	if !actor.IsSysop() {
+		return 0, domains.ErrNotAuthorized
+	}
+	ctx := s.db.Context()
+	clans, err := s.db.Queries().ReadLineageClans(ctx)
+	if err != nil {
+		log.Printf("[dag] Relink: %v\n", err)
+		return 0, errors.Join(domains.ErrDatabaseError, err)
+	}
+	linked := 0
+	now := time.Now().UTC().Unix()
+	for _, clanId := range clans {
+		n, err := func() (int, error) {
+			tx, err := s.db.Stdlib().BeginTx(ctx, nil)
+			if err != nil {
+				return 0, err
+			}
+			defer tx.Rollback() // rollback if we return early; harmless after commit
+			qtx := s.db.Queries().WithTx(tx)
+			docs, err := readDocuments(ctx, qtx, clanId)
+			if err != nil {
+				return 0, err
+			}
+			for _, doc := range docs {
+				if err := link(ctx, qtx, doc.ID, now); err != nil {
+					return 0, err
+				}
+			}
+			return len(docs), tx.Commit()
+		}()
+		if err != nil {
+			log.Printf("[dag] Relink: clan %d: %v\n", clanId, err)
+			return 0, errors.Join(domains.ErrDatabaseError, err)
+		}
+		if verbose {
+			log.Printf("[dag] Relink: clan %d: linked %d documents\n", clanId, n)
+		}
+		linked += n
+	}
+	return linked, nil
+}
+
+// stage returns the order of the document type in a lineage.
+func stage(documentType string) int {
+	switch domains.DocumentType(documentType) {
+	case domains.TurnReportFile:
+		return 1
+	case domains.TurnReportExtract:
+		return 2
+	case domains.WorldographerMap:
+		return 3
+	}
+	return 4
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package dag_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/services/dag"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite"
+)
+
+func TestEdges(t *testing.T) {
+	docs := []dag.Document{
+		{ID: 1, Name: "0301.0900-01.0500.docx", Type: domains.TurnReportFile},
+		{ID: 2, Name: "0301.0900-01.0500.report.txt", Type: domains.TurnReportExtract},
+		{ID: 3, Name: "0301.0900-02.0500.report.txt", Type: domains.TurnReportExtract},
+		{ID: 4, Name: "0301.0900-01.0500.wxx", Type: domains.WorldographerMap},
+		{ID: 5, Name: "0301.0900-02.0500.wxx", Type: domains.WorldographerMap},
+		{ID: 6, Name: "notes.txt", Type: domains.TurnReportExtract},
+		{ID: 7, Name: "0300.0900-01.0500.wxx", Type: domains.WorldographerMap},
+	}
+	for _, tc := range []struct {
+		id   int64
+		want []dag.Edge
+	}{
+		{1, []dag.Edge{{1, 2, dag.Extracted}}},
+		{2, []dag.Edge{{1, 2, dag.Extracted}, {2, 4, dag.Mapped}, {2, 5, dag.Mapped}}},
+		// maps are cumulative, so the second turn's extract isn't in the first turn's map
+		{3, []dag.Edge{{3, 5, dag.Mapped}}},
+		{5, []dag.Edge{{2, 5, dag.Mapped}, {3, 5, dag.Mapped}}},
+		// names that don't follow the convention aren't linked
+		{6, nil},
+		// documents from other games aren't linked
+		{7, nil},
+	} {
+		got := dag.Edges(docs[tc.id-1], docs)
+		if len(got) != len(tc.want) {
+			t.Errorf("%d: got %v, want %v", tc.id, got, tc.want)
+			continue
+		}
+		for i := range tc.want {
+			if got[i] != tc.want[i] {
+				t.Errorf("%d: edge %d: got %v, want %v", tc.id, i, got[i], tc.want[i])
+			}
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlite.OpenTempDB(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, stmt := range []string{
+		`INSERT INTO users (user_id, handle, username, email, timezone, is_active, is_user, created_at, updated_at)
+		 VALUES (2, 'alice', 'alice', 'alice@example.com', 'UTC', 1, 1, 1, 1)`,
+		`INSERT INTO games (game_id, code, description, active_turn, setup_turn, orders_due, created_at, updated_at)
+		 VALUES (1, '0301', 'test game', '0899-12', '0899-12', 1, 1, 1)`,
+		`INSERT INTO game_turns (game_id, turn, turn_year, turn_month, turn_no, created_at, updated_at)
+		 VALUES (1, '0899-12', 899, 12, 0, 1, 1)`,
+		`INSERT INTO clans (clan_id, game_id, user_id, clan, setup_turn, created_at, updated_at)
+		 VALUES (1, 1, 2, 987, '0899-12', 1, 1)`,
+	} {
+		if _, err := db.Stdlib().ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("fixture: %v", err)
+		}
+	}
+
+	// store sets the document's contents and records the lineage the way
+	// the documents service does: only new contents of an existing
+	// document make anything stale.
+	now := int64(1000)
+	store := func(id int64, name string, documentType domains.DocumentType, hash string) {
+		t.Helper()
+		now++
+		var exists bool
+		err := db.Stdlib().QueryRowContext(ctx, `SELECT COUNT(*) FROM document_contents WHERE document_id = ?`, id).Scan(&exists)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var stmts []string
+		if exists {
+			stmts = []string{`UPDATE document_contents SET contents_hash = ?3, updated_at = ?4 WHERE document_id = ?1`}
+		} else {
+			stmts = []string{
+				`INSERT INTO documents (document_id, clan_id, document_name, document_type, modified_at, created_at, updated_at)
+				 VALUES (?1, 1, ?2, ?5, ?4, ?4, ?4)`,
+				`INSERT INTO document_contents (document_id, content_length, contents_hash, created_at, updated_at)
+				 VALUES (?1, 1, ?3, ?4, ?4)`,
+			}
+		}
+		for _, stmt := range stmts {
+			if _, err := db.Stdlib().ExecContext(ctx, stmt, id, name, hash, now, string(documentType)); err != nil {
+				t.Fatalf("store %s: %v", name, err)
+			}
+		}
+		if err := dag.Record(ctx, db.Queries(), id, exists, now); err != nil {
+			t.Fatalf("record %s: %v", name, err)
+		}
+	}
+	// stale returns the stale edges as "upstream-downstream"
+	stale := func() string {
+		t.Helper()
+		rows, err := db.Stdlib().QueryContext(ctx, `SELECT upstream_id, downstream_id FROM document_lineage WHERE stale_at IS NOT NULL ORDER BY upstream_id, downstream_id`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+		var list []string
+		for rows.Next() {
+			var up, down int64
+			if err := rows.Scan(&up, &down); err != nil {
+				t.Fatal(err)
+			}
+			list = append(list, fmt.Sprintf("%d-%d", up, down))
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+		return strings.Join(list, " ")
+	}
+
+	// the first upload of each document marks nothing stale
+	store(1, "0301.0899-12.0987.docx", domains.TurnReportFile, "docx-1")
+	store(2, "0301.0899-12.0987.report.txt", domains.TurnReportExtract, "txt-1")
+	store(3, "0301.0899-12.0987.wxx", domains.WorldographerMap, "wxx-1")
+	store(4, "0301.0900-01.0987.wxx", domains.WorldographerMap, "wxx-2")
+	if got := stale(); got != "" {
+		t.Fatalf("after first uploads: stale %q, want none", got)
+	}
+
+	// replacing the turn report marks the extract and both maps stale
+	store(1, "0301.0899-12.0987.docx", domains.TurnReportFile, "docx-2")
+	if got, want := stale(), "1-2 2-3 2-4"; got != want {
+		t.Fatalf("after replacing docx: stale %q, want %q", got, want)
+	}
+
+	// re-storing the extract clears its edge, but the maps are still stale
+	store(2, "0301.0899-12.0987.report.txt", domains.TurnReportExtract, "txt-2")
+	if got, want := stale(), "2-3 2-4"; got != want {
+		t.Fatalf("after replacing extract: stale %q, want %q", got, want)
+	}
+
+	// re-storing a map clears the edges into it
+	store(3, "0301.0899-12.0987.wxx", domains.WorldographerMap, "wxx-3")
+	if got, want := stale(), "2-4"; got != want {
+		t.Fatalf("after replacing map: stale %q, want %q", got, want)
+	}
+
+	// a changed parse of the extract marks the maps stale without new contents
+	if err := dag.MarkStale(ctx, db.Queries(), 2, now+1); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := stale(), "2-3 2-4"; got != want {
+		t.Fatalf("after reparse: stale %q, want %q", got, want)
+	}
+}
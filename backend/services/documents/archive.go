@@ -0,0 +1,139 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package documents
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"regexp"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+)
+
+// ArchiveFilter selects the documents that go into an archive.
+// Turns are YYYY-MM and the range is inclusive. Empty fields don't filter.
+type ArchiveFilter struct {
+	Type     domains.DocumentType
+	FromTurn string
+	ToTurn   string
+}
+
+// ArchiveEntry is a document in an archive. The contents are read when
+// the archive is written, so that large archives are streamed.
+type ArchiveEntry struct {
+	DocumentID     domains.ID
+	Name           string // {game}.{turn}.{unitId}.{type}
+	Type           domains.DocumentType
+	Turn           string // YYYY-MM, empty if the name doesn't follow the convention
+	ContentsLength int
+	ContentsHash   string
+	ModifiedAt     time.Time
+}
+
+var (
+	// our naming convention is {game}.{turn}.{unitId}.{type}
+	reConventionalName = regexp.MustCompile(`^(\d{4})\.(\d{4}-\d{2})\.(\d{4}([cefg][1-9])?)\.(.+)$`)
+
+	// turns are YYYY-MM
+	reTurnId = regexp.MustCompile(`^\d{4}-\d{2}$`)
+)
+
+// IsTurnId returns true if the value looks like a turn (YYYY-MM).
+func IsTurnId(value string) bool {
+	return reTurnId.MatchString(value)
+}
+
+// ReadArchive returns the user's documents that match the filter, ordered
+// by game and name. Returns domains.ErrNotAuthorized if the actor can't
+// export the user's documents.
+func (s *Service) ReadArchive(actor *domains.Actor, userId domains.ID, filter ArchiveFilter, quiet, verbose, debug bool) ([]*ArchiveEntry, error) {
+	if !s.authzSvc.CanExportDocuments(actor, &domains.Actor{ID: userId}) {
+		return nil, domains.ErrNotAuthorized
+	}
+	if filter.Type != "" && !filter.Type.IsValid() {
+		return nil, fmt.Errorf("%q: unknown type", filter.Type)
+	}
+	if debug {
+		log.Printf("[documents] ReadArchive(%d, %d, %+v)\n", actor.ID, userId, filter)
+	}
+	rows, err := s.db.Queries().ReadDocumentArchiveByUser(s.db.Context(), int64(userId))
+	if err != nil {
+		log.Printf("[documents] ReadArchive(%d, %d, %+v) %v\n", actor.ID, userId, filter, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	list := []*ArchiveEntry{}
+	for _, row := range rows {
+		entry := &ArchiveEntry{
+			DocumentID:     domains.ID(row.DocumentID),
+			Type:           domains.DocumentType(row.DocumentType),
+			ContentsLength: int(row.ContentLength),
+			ContentsHash:   row.ContentsHash,
+			ModifiedAt:     time.Unix(row.ModifiedAt, 0).UTC(),
+		}
+		if filter.Type != "" && entry.Type != filter.Type {
+			continue
+		}
+		entry.Name, entry.Turn = archiveName(row.Code, int(row.Clan), row.DocumentName)
+		if filter.FromTurn != "" || filter.ToTurn != "" {
+			if entry.Turn == "" {
+				// can't tell which turn it is for
+				continue
+			} else if filter.FromTurn != "" && entry.Turn < filter.FromTurn {
+				continue
+			} else if filter.ToTurn != "" && entry.Turn > filter.ToTurn {
+				continue
+			}
+		}
+		list = append(list, entry)
+	}
+	return list, nil
+}
+
+// WriteArchive writes the entries to w as a ZIP file, reading the contents
+// of one document at a time. Word documents are already compressed, so
+// they are stored; everything else is deflated.
+func (s *Service) WriteArchive(w io.Writer, entries []*ArchiveEntry, quiet, verbose, debug bool) error {
+	zw := zip.NewWriter(w)
+	for _, entry := range entries {
+		contents, err := s.blobs.Get(s.db.Context(), s.db.Queries(), entry.ContentsHash)
+		if err != nil {
+			log.Printf("[documents] WriteArchive: %d: %v\n", entry.DocumentID, err)
+			return errors.Join(domains.ErrDatabaseError, err)
+		}
+		method := zip.Deflate
+		if entry.Type == domains.TurnReportFile {
+			method = zip.Store
+		}
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     entry.Name,
+			Method:   method,
+			Modified: entry.ModifiedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(contents); err != nil {
+			return err
+		}
+		if debug {
+			log.Printf("[documents] WriteArchive: %d: %s: %d bytes\n", entry.DocumentID, entry.Name, len(contents))
+		}
+	}
+	return zw.Close()
+}
+
+// archiveName returns the name for a document in an archive and the turn.
+// Names that follow our convention are kept. Other names are prefixed with
+// the game and clan so that they don't collide; the turn is unknown.
+func archiveName(game string, clanNo int, name string) (string, string) {
+	name = path.Base(name) // never let an entry escape the archive root
+	if m := reConventionalName.FindStringSubmatch(name); m != nil && m[1] == game {
+		return name, m[2]
+	}
+	return fmt.Sprintf("%s.%04d.%s", game, clanNo, name), ""
+}
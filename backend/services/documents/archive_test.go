@@ -0,0 +1,105 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package documents_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/services/documents"
+)
+
+func TestArchive(t *testing.T) {
+	f := newFixture(t)
+	f.replaceMap(t, f.alice, f.c0987, "0301.0899-12.0987.wxx", "december")
+	f.replaceMap(t, f.alice, f.c0987, "0301.0900-01.0987.wxx", "january")
+	f.replaceMap(t, f.alice, f.c0987, "0300.0899-12.0987.wxx", "other game")
+	f.replaceMap(t, f.alice, f.c0987, "maps/../../etc/passwd", "escape")
+	f.replaceMap(t, f.bob, f.c0988, "0301.0899-12.0988.wxx", "bob's map")
+
+	for _, tc := range []struct {
+		name   string
+		filter documents.ArchiveFilter
+		want   []string
+	}{
+		// ordered by the stored names, not the entry names
+		{"all", documents.ArchiveFilter{}, []string{
+			"0301.0987.0300.0899-12.0987.wxx", // other games are prefixed and have no turn
+			"0301.0899-12.0987.wxx",
+			"0301.0900-01.0987.wxx",
+			"0301.0987.passwd", // entries can't escape the archive root
+		}},
+		{"from", documents.ArchiveFilter{FromTurn: "0900-01"}, []string{"0301.0900-01.0987.wxx"}},
+		{"to", documents.ArchiveFilter{ToTurn: "0899-12"}, []string{"0301.0899-12.0987.wxx"}},
+		{"kind", documents.ArchiveFilter{Type: domains.TurnReportExtract}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := f.svc.ReadArchive(f.alice, f.alice.ID, tc.filter, true, false, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, entry := range entries {
+				got = append(got, entry.Name)
+			}
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+			for i := range tc.want {
+				if got[i] != tc.want[i] {
+					t.Errorf("%d: got %q, want %q", i, got[i], tc.want[i])
+				}
+			}
+		})
+	}
+
+	// only the owner and sysop can export a user's documents
+	for _, tc := range []struct {
+		name  string
+		actor *domains.Actor
+		err   error
+	}{
+		{"owner", f.alice, nil},
+		{"other player", f.bob, domains.ErrNotAuthorized},
+		{"admin", f.carol, domains.ErrNotAuthorized},
+		{"sysop", &domains.Actor{ID: 1, Roles: domains.Roles{Active: true, Sysop: true}}, nil},
+	} {
+		t.Run("export "+tc.name, func(t *testing.T) {
+			_, err := f.svc.ReadArchive(tc.actor, f.alice.ID, documents.ArchiveFilter{}, true, false, false)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("got %v, want %v", err, tc.err)
+			}
+		})
+	}
+
+	// the archive has the entries and their contents
+	entries, err := f.svc.ReadArchive(f.alice, f.alice.ID, documents.ArchiveFilter{ToTurn: "0899-12"}, true, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := f.svc.WriteArchive(&buf, entries, true, false, false); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip: %v", err)
+	}
+	if len(zr.File) != 1 || zr.File[0].Name != "0301.0899-12.0987.wxx" {
+		t.Fatalf("zip: got %d files, want 0301.0899-12.0987.wxx", len(zr.File))
+	}
+	rc, err := zr.File[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	if got, err := io.ReadAll(rc); err != nil {
+		t.Fatal(err)
+	} else if string(got) != "december" {
+		t.Errorf("zip: got %q, want %q", got, "december")
+	}
+}
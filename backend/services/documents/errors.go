@@ -2,6 +2,12 @@
 
 package documents
 
+import (
+	"fmt"
+
+	"github.com/playbymail/ottoapp/backend/parsers/bistre"
+)
+
 type Error string
 
 func (e Error) Error() string {
@@ -9,6 +15,28 @@ func (e Error) Error() string {
 }
 
 const (
-	ErrExists      = Error("document already exists")
-	ErrInvalidPath = Error("invalid path")
+	ErrExists        = Error("document already exists")
+	ErrInvalidPath   = Error("invalid path")
+	ErrWrongFormat   = Error("contents do not match document type")
+	ErrInvalidReport = Error("report failed validation")
+	ErrInvalidShare  = Error("invalid share")
+	ErrInvalidLink   = Error("invalid link")
 )
+
+// ReportError is returned when a turn report extract breaks the rules
+// for turn reports. It wraps ErrInvalidReport.
+type ReportError struct {
+	Clan       string // clan from the heading of the report
+	Violations []*bistre.Violation_t
+}
+
+func (e *ReportError) Error() string {
+	if len(e.Violations) == 1 {
+		return fmt.Sprintf("%s: %s", ErrInvalidReport, e.Violations[0])
+	}
+	return fmt.Sprintf("%s: %s (and %d more)", ErrInvalidReport, e.Violations[0], len(e.Violations)-1)
+}
+
+func (e *ReportError) Unwrap() error {
+	return ErrInvalidReport
+}
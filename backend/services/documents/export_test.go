@@ -0,0 +1,12 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package documents
+
+// export the link token helpers for the tests in documents_test.
+
+type LinkClaims = linkClaims
+
+var (
+	SignLinkToken   = signLinkToken
+	VerifyLinkToken = verifyLinkToken
+)
@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package documents
+
+import (
+	"fmt"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre"
+	"github.com/playbymail/ottoapp/backend/services/reports/office"
+)
+
+// checkFormat returns an error if the contents don't match the document type.
+// We trust the magic bytes, not the name of the file, so a turn report file
+// may be a Word (.docx) or LibreOffice (.odt) document, or plain text.
+func checkFormat(docType domains.DocumentType, contents []byte) error {
+	dt := office.DetectDocType(contents)
+	switch docType {
+	case domains.TurnReportFile:
+		if dt == office.Docx || dt == office.Odt || dt == office.Text {
+			return nil
+		}
+	case domains.TurnReportExtract:
+		if dt == office.Text {
+			return nil
+		}
+	case domains.WorldographerMap:
+		// we don't inspect maps yet
+		return nil
+	}
+	return fmt.Errorf("%w: %s: found %s", ErrWrongFormat, docType, dt)
+}
+
+// contentType returns the content type for downloading the document.
+// The document_types table only has one content type per document type,
+// so OpenDocument and plain text reports are detected from the contents.
+func contentType(docType domains.DocumentType, defaultType string, contents []byte) string {
+	if docType == domains.TurnReportFile {
+		switch office.DetectDocType(contents) {
+		case office.Odt:
+			return string(domains.ODTMimeType)
+		case office.Text:
+			return string(domains.ReportMimeType)
+		}
+	}
+	return defaultType
+}
+
+// checkReport validates a turn report extract before it is accepted.
+// Every section must be for the same turn, every unit must be in the
+// clan from the heading, and followed units must be in the report.
+// The heading must also be for the clan that will own the document.
+//
+// Returns a *ReportError with all the violations.
+func checkReport(owner *domains.Clan, doc *domains.Document) error {
+	if doc.Type != domains.TurnReportExtract {
+		return nil
+	}
+	clan, violations := bistre.ValidateReport(doc.Path, doc.Contents)
+	if clan != "" && owner != nil && owner.ClanNo != 0 {
+		if want := bistre.UnitId_t(fmt.Sprintf("%04d", owner.ClanNo)); clan != want {
+			violations = append([]*bistre.Violation_t{{
+				Rule:    bistre.NoHeading,
+				LineNo:  1,
+				UnitId:  clan,
+				Message: fmt.Sprintf("report is for clan %s, document is owned by clan %s", clan, want),
+			}}, violations...)
+		}
+	}
+	if len(violations) != 0 {
+		return &ReportError{Clan: string(clan), Violations: violations}
+	}
+	return nil
+}
@@ -12,16 +12,18 @@ import (
 
 // DocumentView is the JSON:API view for a document.
 type DocumentView struct {
-	ID           string    `jsonapi:"primary,document"`   // singular when sending a payload
-	OwnerHandle  string    `jsonapi:"attr,owner"`         // handle of user that owns this document
-	UserHandle   string    `jsonapi:"attr,created-by"`    // handle of user for this document
-	GameId       string    `jsonapi:"attr,game-id"`       // game for this document
-	ClanNo       string    `jsonapi:"attr,clan"`          // clan for this document
-	DocumentName string    `jsonapi:"attr,document-name"` // untainted name of document
-	DocumentType string    `jsonapi:"attr,document-type"` // for categorizing on the dashboards
-	ModifiedAt   time.Time `jsonapi:"attr,modified-at,iso8601"`
-	CreatedAt    time.Time `jsonapi:"attr,created-at,iso8601"`
-	UpdatedAt    time.Time `jsonapi:"attr,updated-at,iso8601"`
+	ID           string     `jsonapi:"primary,document"`   // singular when sending a payload
+	OwnerHandle  string     `jsonapi:"attr,owner"`         // handle of user that owns this document
+	UserHandle   string     `jsonapi:"attr,created-by"`    // handle of user for this document
+	GameId       string     `jsonapi:"attr,game-id"`       // game for this document
+	ClanNo       string     `jsonapi:"attr,clan"`          // clan for this document
+	DocumentName string     `jsonapi:"attr,document-name"` // untainted name of document
+	DocumentType string     `jsonapi:"attr,document-type"` // for categorizing on the dashboards
+	ModifiedAt   time.Time  `jsonapi:"attr,modified-at,iso8601"`
+	CreatedAt    time.Time  `jsonapi:"attr,created-at,iso8601"`
+	UpdatedAt    time.Time  `jsonapi:"attr,updated-at,iso8601"`
+	Shared       bool       `jsonapi:"attr,shared"`                         // true if another user shared this document with the actor
+	SharedUntil  *time.Time `jsonapi:"attr,shared-until,iso8601,omitempty"` // nil if the share doesn't expire
 }
 
 // JSONAPILinks implements the jsonapi.Linkable interface for document-links
@@ -74,3 +76,92 @@ func (d *UserDocumentView) JSONAPILinks() *jsonapi.Links {
 	}
 	panic(fmt.Sprintf("assert(docType != %q)", d.DocumentType))
 }
+
+// RevisionView is the JSON:API view for a revision of a document's contents.
+type RevisionView struct {
+	ID            string    `jsonapi:"primary,document-revision"` // document id and revision, 12-3
+	DocumentId    string    `jsonapi:"attr,document-id"`
+	Revision      int       `jsonapi:"attr,revision"`
+	ContentLength int       `jsonapi:"attr,content-length"`
+	ContentsHash  string    `jsonapi:"attr,contents-hash"`
+	UploadedBy    string    `jsonapi:"attr,uploaded-by"` // handle of user that uploaded the revision
+	Reason        string    `jsonapi:"attr,reason"`
+	IsLatest      bool      `jsonapi:"attr,is-latest"`
+	CreatedAt     time.Time `jsonapi:"attr,created-at,iso8601"`
+}
+
+// JSONAPILinks implements the jsonapi.Linkable interface for document-revision-links
+func (d *RevisionView) JSONAPILinks() *jsonapi.Links {
+	return &jsonapi.Links{
+		"document": fmt.Sprintf("/api/documents/%s", d.DocumentId),
+		"contents": jsonapi.Link{
+			Href: fmt.Sprintf("/api/documents/%s/revisions/%d/contents", d.DocumentId, d.Revision),
+		},
+	}
+}
+
+// ShareView is the JSON:API view for a document shared with a clan or user.
+type ShareView struct {
+	ID         string     `jsonapi:"primary,document-share"` // singular when sending a payload
+	DocumentId string     `jsonapi:"attr,document-id"`
+	Clan       string     `jsonapi:"attr,clan,omitempty"` // four-digit clan number, if shared with a clan
+	User       string     `jsonapi:"attr,user,omitempty"` // handle, if shared with a user
+	GrantedBy  string     `jsonapi:"attr,granted-by"`     // handle of user that shared the document
+	IsActive   bool       `jsonapi:"attr,is-active"`      // false once expired or revoked
+	ExpiresAt  *time.Time `jsonapi:"attr,expires-at,iso8601,omitempty"`
+	RevokedAt  *time.Time `jsonapi:"attr,revoked-at,iso8601,omitempty"`
+	CreatedAt  time.Time  `jsonapi:"attr,created-at,iso8601"`
+}
+
+// JSONAPILinks implements the jsonapi.Linkable interface for document-share-links
+func (d *ShareView) JSONAPILinks() *jsonapi.Links {
+	return &jsonapi.Links{
+		"self":     fmt.Sprintf("/api/documents/%s/shares/%s", d.DocumentId, d.ID),
+		"document": fmt.Sprintf("/api/documents/%s", d.DocumentId),
+	}
+}
+
+// LinkView is the JSON:API view for a public link to a document.
+type LinkView struct {
+	ID             string     `jsonapi:"primary,document-link"` // singular when sending a payload
+	DocumentId     string     `jsonapi:"attr,document-id"`
+	Revision       int        `jsonapi:"attr,revision"` // revision served by the link
+	Token          string     `jsonapi:"attr,token"`
+	Url            string     `jsonapi:"attr,url"`        // public route that serves the contents
+	CreatedBy      string     `jsonapi:"attr,created-by"` // handle of user that created the link
+	AccessCount    int        `jsonapi:"attr,access-count"`
+	LastAccessedAt *time.Time `jsonapi:"attr,last-accessed-at,iso8601,omitempty"`
+	IsActive       bool       `jsonapi:"attr,is-active"` // false once expired or revoked
+	ExpiresAt      time.Time  `jsonapi:"attr,expires-at,iso8601"`
+	RevokedAt      *time.Time `jsonapi:"attr,revoked-at,iso8601,omitempty"`
+	CreatedAt      time.Time  `jsonapi:"attr,created-at,iso8601"`
+}
+
+// JSONAPILinks implements the jsonapi.Linkable interface for document-link-links
+func (d *LinkView) JSONAPILinks() *jsonapi.Links {
+	return &jsonapi.Links{
+		"self":     fmt.Sprintf("/api/documents/%s/links/%s", d.DocumentId, d.ID),
+		"document": fmt.Sprintf("/api/documents/%s", d.DocumentId),
+		"shared":   d.Url,
+	}
+}
+
+// TrashView is the JSON:API view for a document in the trash.
+type TrashView struct {
+	ID           string    `jsonapi:"primary,deleted-document"` // singular when sending a payload
+	OwnerHandle  string    `jsonapi:"attr,owner"`               // handle of user that owns this document
+	GameId       string    `jsonapi:"attr,game-id"`
+	ClanNo       string    `jsonapi:"attr,clan"`
+	DocumentName string    `jsonapi:"attr,document-name"`
+	DocumentType string    `jsonapi:"attr,document-type"`
+	DeletedBy    string    `jsonapi:"attr,deleted-by"` // handle of user that deleted the document
+	DeletedAt    time.Time `jsonapi:"attr,deleted-at,iso8601"`
+	PurgeAfter   time.Time `jsonapi:"attr,purge-after,iso8601"` // when the purge job may delete it for good
+}
+
+// JSONAPILinks implements the jsonapi.Linkable interface for deleted-document-links
+func (d *TrashView) JSONAPILinks() *jsonapi.Links {
+	return &jsonapi.Links{
+		"restore": fmt.Sprintf("/api/trash/%s/restore", d.ID),
+	}
+}
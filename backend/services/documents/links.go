@@ -0,0 +1,310 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package documents
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+const (
+	// DefaultLinkLifetime is used when a link is created without an expiry.
+	DefaultLinkLifetime = 7 * 24 * time.Hour
+	// MaxLinkLifetime is the longest a link can last.
+	MaxLinkLifetime = 90 * 24 * time.Hour
+
+	// linkSecretKey is the config key for the secret that signs link tokens.
+	linkSecretKey = "links.secret"
+)
+
+// linkClaims are the values signed into a link token.
+type linkClaims struct {
+	LinkID     int64
+	DocumentID int64
+	Revision   int64
+	ExpiresAt  int64
+}
+
+// CreateDocumentLink creates a public link to the current revision of the
+// document. Anyone with the link can read that revision until the link
+// expires or is revoked. A zero expiresAt means DefaultLinkLifetime.
+// Returns ErrInvalidLink if the expiry is in the past or too far away.
+func (s *Service) CreateDocumentLink(actor *domains.Actor, owner *domains.Clan, documentId domains.ID, expiresAt time.Time, quiet, verbose, debug bool) (*LinkView, error) {
+	if !s.authzSvc.CanShareDocuments(actor, &domains.Actor{ID: owner.UserID}) {
+		return nil, domains.ErrNotAuthorized
+	}
+	now := time.Now().UTC()
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(DefaultLinkLifetime)
+	} else if !expiresAt.After(now) {
+		return nil, errors.Join(ErrInvalidLink, fmt.Errorf("expiry is in the past"))
+	} else if expiresAt.After(now.Add(MaxLinkLifetime)) {
+		return nil, errors.Join(ErrInvalidLink, fmt.Errorf("links can't last more than %d days", MaxLinkLifetime/(24*time.Hour)))
+	}
+
+	secret, err := s.linkSecret()
+	if err != nil {
+		log.Printf("[documents] CreateDocumentLink(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	revision, err := s.db.Queries().ReadDocumentLatestRevision(s.db.Context(), int64(documentId))
+	if err != nil {
+		log.Printf("[documents] CreateDocumentLink(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	} else if revision == 0 {
+		return nil, domains.ErrNotExists
+	}
+	createdBy, err := s.usersSvc.GetUserHandle(actor.ID)
+	if err != nil {
+		log.Printf("[documents] CreateDocumentLink(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+
+	linkId, err := s.db.Queries().CreateDocumentLink(s.db.Context(), sqlc.CreateDocumentLinkParams{
+		DocumentID: int64(documentId),
+		Revision:   revision,
+		CreatedBy:  int64(actor.ID),
+		ExpiresAt:  expiresAt.Unix(),
+		CreatedAt:  now.Unix(),
+		UpdatedAt:  now.Unix(),
+	})
+	if err != nil {
+		log.Printf("[documents] CreateDocumentLink(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	if debug {
+		log.Printf("[documents] CreateDocumentLink(%d, (%d, %d), %d) link %d revision %d\n", actor.ID, owner.GameID, owner.ClanID, documentId, linkId, revision)
+	}
+	return newLinkView(secret, sqlc.ReadDocumentLinksRow{
+		LinkID:     linkId,
+		DocumentID: int64(documentId),
+		Revision:   revision,
+		CreatedBy:  createdBy,
+		ExpiresAt:  expiresAt.Unix(),
+		CreatedAt:  now.Unix(),
+	}, now), nil
+}
+
+// ReadDocumentLinks returns all the links for the document, including the
+// expired and revoked ones, oldest first.
+func (s *Service) ReadDocumentLinks(actor *domains.Actor, owner *domains.Clan, documentId domains.ID, quiet, verbose, debug bool) ([]*LinkView, error) {
+	if !s.authzSvc.CanShareDocuments(actor, &domains.Actor{ID: owner.UserID}) {
+		return nil, domains.ErrNotAuthorized
+	}
+	secret, err := s.linkSecret()
+	if err != nil {
+		log.Printf("[documents] ReadDocumentLinks(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	rows, err := s.db.Queries().ReadDocumentLinks(s.db.Context(), int64(documentId))
+	if err != nil {
+		log.Printf("[documents] ReadDocumentLinks(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	now := time.Now().UTC()
+	list := []*LinkView{}
+	for _, row := range rows {
+		list = append(list, newLinkView(secret, row, now))
+	}
+	return list, nil
+}
+
+// RevokeDocumentLink ends a link. Returns domains.ErrNotExists if the
+// document doesn't have the link or it was already revoked.
+func (s *Service) RevokeDocumentLink(actor *domains.Actor, owner *domains.Clan, documentId, linkId domains.ID, quiet, verbose, debug bool) error {
+	if !s.authzSvc.CanShareDocuments(actor, &domains.Actor{ID: owner.UserID}) {
+		return domains.ErrNotAuthorized
+	}
+	n, err := s.db.Queries().RevokeDocumentLink(s.db.Context(), sqlc.RevokeDocumentLinkParams{
+		RevokedAt:  sql.NullInt64{Int64: time.Now().UTC().Unix(), Valid: true},
+		LinkID:     int64(linkId),
+		DocumentID: int64(documentId),
+	})
+	if err != nil {
+		log.Printf("[documents] RevokeDocumentLink(%d, (%d, %d), %d, %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, linkId, err)
+		return errors.Join(domains.ErrDatabaseError, err)
+	} else if n == 0 {
+		return domains.ErrNotExists
+	}
+	if debug {
+		log.Printf("[documents] RevokeDocumentLink(%d, (%d, %d), %d, %d) revoked\n", actor.ID, owner.GameID, owner.ClanID, documentId, linkId)
+	}
+	return nil
+}
+
+// ReadLinkedContents returns the revision of the document that the link
+// token points to. It doesn't count the access; the caller decides if the
+// response is a download (see CountLinkAccess). Returns domains.ErrNotExists
+// if the token is not valid or the link has expired or been revoked; callers
+// shouldn't tell the difference to anonymous users.
+func (s *Service) ReadLinkedContents(token string, quiet, verbose, debug bool) (*domains.Document, error) {
+	claims, err := s.verifyLink(token, debug)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.db.Queries().ReadActiveDocumentLink(s.db.Context(), sqlc.ReadActiveDocumentLinkParams{
+		LinkID:     claims.LinkID,
+		DocumentID: claims.DocumentID,
+		Revision:   claims.Revision,
+		Now:        time.Now().UTC().Unix(),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// revoked, or the document was deleted
+			return nil, domains.ErrNotExists
+		}
+		log.Printf("[documents] ReadLinkedContents(%d) %v\n", claims.LinkID, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	owner, err := s.ReadDocumentOwner(domains.ID(claims.DocumentID), quiet, verbose, debug)
+	if err != nil {
+		return nil, err
+	}
+	// the link is the authorization; there is no actor
+	return s.ReadDocumentRevisionContents(&domains.Actor{}, owner, domains.ID(claims.DocumentID), int(claims.Revision), quiet, verbose, debug)
+}
+
+// CountLinkAccess counts a download through the link. Returns
+// domains.ErrNotExists if the token is not valid or the link has
+// expired or been revoked.
+func (s *Service) CountLinkAccess(token string, quiet, verbose, debug bool) error {
+	claims, err := s.verifyLink(token, debug)
+	if err != nil {
+		return err
+	}
+	n, err := s.db.Queries().UpdateDocumentLinkAccess(s.db.Context(), sqlc.UpdateDocumentLinkAccessParams{
+		Now:        sql.NullInt64{Int64: time.Now().UTC().Unix(), Valid: true},
+		LinkID:     claims.LinkID,
+		DocumentID: claims.DocumentID,
+		Revision:   claims.Revision,
+	})
+	if err != nil {
+		log.Printf("[documents] CountLinkAccess(%d) %v\n", claims.LinkID, err)
+		return errors.Join(domains.ErrDatabaseError, err)
+	} else if n == 0 {
+		// revoked, or the document was deleted
+		return domains.ErrNotExists
+	}
+	return nil
+}
+
+// verifyLink returns the claims from a link token that is signed
+// and hasn't expired.
+func (s *Service) verifyLink(token string, debug bool) (linkClaims, error) {
+	secret, err := s.linkSecret()
+	if err != nil {
+		log.Printf("[documents] verifyLink %v\n", err)
+		return linkClaims{}, errors.Join(domains.ErrDatabaseError, err)
+	}
+	claims, err := verifyLinkToken(secret, token)
+	if err != nil {
+		if debug {
+			log.Printf("[documents] verifyLink %v\n", err)
+		}
+		return linkClaims{}, domains.ErrNotExists
+	}
+	if claims.ExpiresAt <= time.Now().UTC().Unix() {
+		return linkClaims{}, domains.ErrNotExists
+	}
+	return claims, nil
+}
+
+// linkSecret returns the key that signs link tokens.
+func (s *Service) linkSecret() ([]byte, error) {
+	value, err := s.db.Queries().ReadConfigKeyValue(s.db.Context(), linkSecretKey)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("%s", linkSecretKey), err)
+	}
+	secret, err := hex.DecodeString(value)
+	if err != nil || len(secret) < 32 {
+		return nil, fmt.Errorf("%s: invalid secret", linkSecretKey)
+	}
+	return secret, nil
+}
+
+// newLinkView returns the view for a link, including its token.
+func newLinkView(secret []byte, row sqlc.ReadDocumentLinksRow, now time.Time) *LinkView {
+	token := signLinkToken(secret, linkClaims{
+		LinkID:     row.LinkID,
+		DocumentID: row.DocumentID,
+		Revision:   row.Revision,
+		ExpiresAt:  row.ExpiresAt,
+	})
+	view := &LinkView{
+		ID:          fmt.Sprintf("%d", row.LinkID),
+		DocumentId:  fmt.Sprintf("%d", row.DocumentID),
+		Revision:    int(row.Revision),
+		Token:       token,
+		Url:         "/api/shared/" + token,
+		CreatedBy:   row.CreatedBy,
+		AccessCount: int(row.AccessCount),
+		ExpiresAt:   time.Unix(row.ExpiresAt, 0).UTC(),
+		IsActive:    !row.RevokedAt.Valid && row.ExpiresAt > now.Unix(),
+		CreatedAt:   time.Unix(row.CreatedAt, 0).UTC(),
+	}
+	if row.RevokedAt.Valid {
+		revokedAt := time.Unix(row.RevokedAt.Int64, 0).UTC()
+		view.RevokedAt = &revokedAt
+	}
+	if row.LastAccessedAt.Valid {
+		lastAccessedAt := time.Unix(row.LastAccessedAt.Int64, 0).UTC()
+		view.LastAccessedAt = &lastAccessedAt
+	}
+	return view
+}
+
+// signLinkToken returns the token for the claims. The token is the claims
+// and their HMAC-SHA256, both base64url encoded and joined with a dot.
+func signLinkToken(secret []byte, claims linkClaims) string {
+	payload := fmt.Sprintf("%d.%d.%d.%d", claims.LinkID, claims.DocumentID, claims.Revision, claims.ExpiresAt)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyLinkToken returns the claims from the token if the signature is
+// valid. It doesn't check the expiry.
+func verifyLinkToken(secret []byte, token string) (linkClaims, error) {
+	var claims linkClaims
+	encodedPayload, encodedSum, ok := strings.Cut(token, ".")
+	if !ok {
+		return claims, ErrInvalidLink
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return claims, ErrInvalidLink
+	}
+	sum, err := base64.RawURLEncoding.DecodeString(encodedSum)
+	if err != nil {
+		return claims, ErrInvalidLink
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	if !hmac.Equal(sum, mac.Sum(nil)) {
+		return claims, ErrInvalidLink
+	}
+	fields := strings.Split(string(payload), ".")
+	if len(fields) != 4 {
+		return claims, ErrInvalidLink
+	}
+	values := make([]int64, len(fields))
+	for n, field := range fields {
+		if values[n], err = strconv.ParseInt(field, 10, 64); err != nil || values[n] < 1 {
+			return claims, ErrInvalidLink
+		}
+	}
+	claims.LinkID, claims.DocumentID, claims.Revision, claims.ExpiresAt = values[0], values[1], values[2], values[3]
+	return claims, nil
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package documents_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/services/documents"
+)
+
+func TestLinkToken(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	claims := documents.LinkClaims{LinkID: 1, DocumentID: 2, Revision: 3, ExpiresAt: 1893456000}
+	token := documents.SignLinkToken(secret, claims)
+	payload, sum, _ := strings.Cut(token, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte("1.2.4.1893456000"))
+
+	for _, tc := range []struct {
+		name   string
+		secret []byte
+		token  string
+		ok     bool
+	}{
+		{"round trip", secret, token, true},
+		{"other secret", []byte("fedcba9876543210fedcba9876543210"), token, false},
+		{"tampered payload", secret, forged + "." + sum, false},
+		{"tampered signature", secret, payload + "." + sum[:len(sum)-2] + "AA", false},
+		{"truncated", secret, token[:len(token)-4], false},
+		{"no signature", secret, payload, false},
+		{"empty", secret, "", false},
+		{"not base64", secret, "!!!." + sum, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := documents.VerifyLinkToken(tc.secret, tc.token)
+			if !tc.ok {
+				if !errors.Is(err, documents.ErrInvalidLink) {
+					t.Fatalf("got %v, want %v", err, documents.ErrInvalidLink)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got != claims {
+				t.Errorf("got %+v, want %+v", got, claims)
+			}
+		})
+	}
+
+	// a payload that is signed but isn't four positive numbers is rejected
+	for _, bad := range []string{"1.2.3", "1.2.3.4.5", "1.2.0.4", "1.two.3.4"} {
+		t.Run("malformed "+bad, func(t *testing.T) {
+			mac := hmac.New(sha256.New, secret)
+			mac.Write([]byte(bad))
+			token := base64.RawURLEncoding.EncodeToString([]byte(bad)) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+			if _, err := documents.VerifyLinkToken(secret, token); !errors.Is(err, documents.ErrInvalidLink) {
+				t.Errorf("got %v, want %v", err, documents.ErrInvalidLink)
+			}
+		})
+	}
+}
+
+func TestLinkedContents(t *testing.T) {
+	f := newFixture(t)
+	id := f.replaceMap(t, f.alice, f.c0987, "0899-12.0987.wxx", "first")
+
+	link, err := f.svc.CreateDocumentLink(f.alice, f.c0987, id, time.Time{}, true, false, false)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	// the link stays on the revision it was created for
+	f.replaceMap(t, f.alice, f.c0987, "0899-12.0987.wxx", "second")
+
+	// an expired token has a valid signature, so sign it with the real secret
+	var value string
+	if err := f.db.Stdlib().QueryRowContext(context.Background(), `SELECT value FROM config WHERE key = 'links.secret'`).Scan(&value); err != nil {
+		t.Fatal(err)
+	}
+	secret, err := hex.DecodeString(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	linkId, err := strconv.ParseInt(link.ID, 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired := documents.SignLinkToken(secret, documents.LinkClaims{LinkID: linkId, DocumentID: int64(id), Revision: 1, ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+
+	doc, err := f.svc.ReadLinkedContents(link.Token, true, false, false)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	} else if got := string(doc.Contents); got != "first" {
+		t.Errorf("read: got %q, want %q", got, "first")
+	}
+	for _, token := range []string{expired, link.Token[:len(link.Token)-4], "not-a-token"} {
+		if _, err := f.svc.ReadLinkedContents(token, true, false, false); !errors.Is(err, domains.ErrNotExists) {
+			t.Errorf("read %q: got %v, want %v", token, err, domains.ErrNotExists)
+		}
+		if err := f.svc.CountLinkAccess(token, true, false, false); !errors.Is(err, domains.ErrNotExists) {
+			t.Errorf("count %q: got %v, want %v", token, err, domains.ErrNotExists)
+		}
+	}
+
+	// each count is one download
+	for range 2 {
+		if err := f.svc.CountLinkAccess(link.Token, true, false, false); err != nil {
+			t.Fatalf("count: %v", err)
+		}
+	}
+	links, err := f.svc.ReadDocumentLinks(f.alice, f.c0987, id, true, false, false)
+	if err != nil {
+		t.Fatalf("links: %v", err)
+	} else if len(links) != 1 || links[0].AccessCount != 2 {
+		t.Fatalf("links: got %d links, want 1 with 2 accesses", len(links))
+	}
+
+	// a trashed document can't be read through its links until it is restored
+	if err := f.svc.DeleteDocument(f.alice, f.c0987, id); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := f.svc.ReadLinkedContents(link.Token, true, false, false); !errors.Is(err, domains.ErrNotExists) {
+		t.Errorf("read trashed: got %v, want %v", err, domains.ErrNotExists)
+	}
+	if err := f.svc.CountLinkAccess(link.Token, true, false, false); !errors.Is(err, domains.ErrNotExists) {
+		t.Errorf("count trashed: got %v, want %v", err, domains.ErrNotExists)
+	}
+	if err := f.svc.RestoreDocument(f.alice, id, true, false, false); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if _, err := f.svc.ReadLinkedContents(link.Token, true, false, false); err != nil {
+		t.Errorf("read restored: %v", err)
+	}
+
+	// a revoked link is gone for good
+	if err := f.svc.RevokeDocumentLink(f.alice, f.c0987, id, domains.ID(linkId), true, false, false); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if _, err := f.svc.ReadLinkedContents(link.Token, true, false, false); !errors.Is(err, domains.ErrNotExists) {
+		t.Errorf("read revoked: got %v, want %v", err, domains.ErrNotExists)
+	}
+	if err := f.svc.CountLinkAccess(link.Token, true, false, false); !errors.Is(err, domains.ErrNotExists) {
+		t.Errorf("count revoked: got %v, want %v", err, domains.ErrNotExists)
+	}
+}
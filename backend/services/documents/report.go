@@ -3,9 +3,11 @@
 package documents
 
 import (
+	"log"
 	"net/http"
 
 	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/parsers"
 )
 
 // LoadReportFromFS loads the file, creates a Document, and returns the document ID.
@@ -17,3 +19,28 @@ func (s *Service) LoadReportFromFS(actor *domains.Actor, clan *domains.Clan, pat
 func (s *Service) LoadReportFromRequest(r *http.Request) (domains.ID, error) {
 	return s.loadFromRequest(r)
 }
+
+// parseReport records the parse of an extract after its contents are
+// created or replaced, so that report_parses doesn't wait for a reparse.
+// Parse errors are recorded with the parse. Failing to store the parse
+// doesn't fail the caller; the extract stays stale until the next reparse.
+func (s *Service) parseReport(documentId domains.ID, docType domains.DocumentType, contentsHash string, quiet, verbose, debug bool) {
+	if docType != domains.TurnReportExtract {
+		return
+	}
+	format := parsers.DefaultFormat
+	if p, err := s.reportsSvc.ReadParse(documentId); err == nil {
+		if p.ContentsHash == contentsHash {
+			// contents didn't change
+			return
+		} else if p.Format.IsValid() {
+			format = p.Format
+		}
+	}
+	p, err := s.reportsSvc.ParseDocument(documentId, format, quiet, verbose, debug)
+	if err != nil {
+		log.Printf("[documents] parseReport(%d) %v\n", documentId, err)
+	} else if verbose && p.Error != "" {
+		log.Printf("[documents] parseReport(%d) %s\n", documentId, p.Error)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package documents
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/services/dag"
+	"github.com/playbymail/ottoapp/backend/services/search"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+// ReadDocumentRevisions returns the revisions of a document, newest first.
+// The caller must check that the actor can read the owner's documents.
+func (s *Service) ReadDocumentRevisions(actor *domains.Actor, owner *domains.Clan, documentId domains.ID, quiet, verbose, debug bool) ([]*RevisionView, error) {
+	if debug {
+		log.Printf("[documents] ReadDocumentRevisions(%d, (%d, %d), %d)\n", actor.ID, owner.GameID, owner.ClanID, documentId)
+	}
+	rows, err := s.db.Queries().ReadDocumentRevisions(s.db.Context(), sqlc.ReadDocumentRevisionsParams{
+		DocumentID: int64(documentId),
+		ClanID:     int64(owner.ClanID),
+	})
+	if err != nil {
+		log.Printf("[documents] ReadDocumentRevisions(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	list := []*RevisionView{}
+	for n, row := range rows {
+		list = append(list, &RevisionView{
+			ID:            fmt.Sprintf("%d-%d", row.DocumentID, row.Revision),
+			DocumentId:    fmt.Sprintf("%d", row.DocumentID),
+			Revision:      int(row.Revision),
+			ContentLength: int(row.ContentLength),
+			ContentsHash:  row.ContentsHash,
+			UploadedBy:    row.UploadedBy,
+			Reason:        row.Reason,
+			IsLatest:      n == 0,
+			CreatedAt:     time.Unix(row.CreatedAt, 0).UTC(),
+		})
+	}
+	return list, nil
+}
+
+// ReadDocumentRevisionContents returns the contents of a single revision.
+// Returns domains.ErrNotExists if the document doesn't have the revision.
+// The caller must check that the actor can read the owner's documents.
+func (s *Service) ReadDocumentRevisionContents(actor *domains.Actor, owner *domains.Clan, documentId domains.ID, revision int, quiet, verbose, debug bool) (*domains.Document, error) {
+	if debug {
+		log.Printf("[documents] ReadDocumentRevisionContents(%d, (%d, %d), %d, %d)\n", actor.ID, owner.GameID, owner.ClanID, documentId, revision)
+	}
+	d, err := s.db.Queries().ReadDocumentRevision(s.db.Context(), sqlc.ReadDocumentRevisionParams{
+		DocumentID: int64(documentId),
+		Revision:   int64(revision),
+		ClanID:     int64(owner.ClanID),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domains.ErrNotExists
+		}
+		log.Printf("[documents] ReadDocumentRevisionContents(%d, (%d, %d), %d, %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, revision, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	documentType := domains.DocumentType(d.DocumentType)
+	if !documentType.IsValid() {
+		return nil, fmt.Errorf("%q: unknown type", d.DocumentType)
+	}
+	contents, err := s.blobs.Get(s.db.Context(), s.db.Queries(), d.ContentsHash)
+	if err != nil {
+		log.Printf("[documents] ReadDocumentRevisionContents(%d, (%d, %d), %d, %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, revision, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	return &domains.Document{
+		ID:             domains.ID(d.DocumentID),
+		GameID:         owner.GameID,
+		ClanId:         owner.ClanID,
+		ClanNo:         owner.ClanNo,
+		Path:           d.DocumentName,
+		Type:           documentType,
+		ContentsLength: int(d.ContentLength),
+		ContentsHash:   d.ContentsHash,
+		ContentType:    contentType(documentType, d.ContentType, contents),
+		Contents:       contents,
+		Revision:       int(d.Revision),
+		CreatedAt:      time.Unix(d.CreatedAt, 0).UTC(),
+		UpdatedAt:      time.Unix(d.CreatedAt, 0).UTC(),
+	}, nil
+}
+
+// RevertDocument makes an old revision the current contents of the document.
+// The old contents are copied to a new revision so that the history is kept.
+// Returns the new revision.
+//
+// The contents are checked again because the rules for reports may have
+// changed since the revision was uploaded.
+//
+// Returns domains.ErrNotExists if the document doesn't belong to the owner.
+func (s *Service) RevertDocument(actor *domains.Actor, owner *domains.Clan, documentId domains.ID, revision int, quiet, verbose, debug bool) (*RevisionView, error) {
+	// don't trust the caller's owner; check it against the document
+	clan, err := s.ReadDocumentOwner(documentId, quiet, verbose, debug)
+	if err != nil {
+		return nil, err
+	} else if clan.ClanID != owner.ClanID {
+		return nil, domains.ErrNotExists
+	}
+	if !s.authzSvc.CanRevertDocuments(actor, &domains.Actor{ID: clan.UserID}) {
+		return nil, domains.ErrNotAuthorized
+	}
+	old, err := s.ReadDocumentRevisionContents(actor, owner, documentId, revision, quiet, verbose, debug)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFormat(old.Type, old.Contents); err != nil {
+		return nil, err
+	}
+	if err := checkReport(owner, old); err != nil {
+		return nil, err
+	}
+
+	// start transaction
+	ctx := s.db.Context()
+	tx, err := s.db.Stdlib().BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[documents] RevertDocument(%d, (%d, %d), %d, %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, revision, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	defer tx.Rollback() // rollback if we return early; harmless after commit
+	qtx := s.db.Queries().WithTx(tx)
+	now := time.Now().UTC()
+	updatedAt := now.Unix()
+
+	err = qtx.UpdateDocumentContentsById(ctx, sqlc.UpdateDocumentContentsByIdParams{
+		DocumentID:    int64(documentId),
+		ContentLength: int64(old.ContentsLength),
+		ContentsHash:  old.ContentsHash,
+		UpdatedAt:     updatedAt,
+	})
+	if err != nil {
+		log.Printf("[documents] RevertDocument(%d, (%d, %d), %d, %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, revision, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	reason := fmt.Sprintf("reverted to revision %d", revision)
+	newRevision, err := s.createRevision(ctx, qtx, actor, int64(documentId), string(old.Type), old.Contents, old.ContentsLength, old.ContentsHash, reason, updatedAt)
+	if err != nil {
+		log.Printf("[documents] RevertDocument(%d, (%d, %d), %d, %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, revision, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	err = qtx.UpdateDocumentById(ctx, sqlc.UpdateDocumentByIdParams{
+		ClanID:       int64(owner.ClanID),
+		DocumentID:   int64(documentId),
+		DocumentName: old.Path,
+		DocumentType: string(old.Type),
+		ModifiedAt:   updatedAt, // a revert is a new modification
+		UpdatedAt:    updatedAt,
+	})
+	if err != nil {
+		log.Printf("[documents] RevertDocument(%d, (%d, %d), %d, %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, revision, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+
+	user, err := qtx.ReadUserByUserId(ctx, int64(actor.ID))
+	if err != nil {
+		log.Printf("[documents] RevertDocument(%d, (%d, %d), %d, %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, revision, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		log.Printf("[documents] RevertDocument(%d, (%d, %d), %d, %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, revision, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	s.parseReport(documentId, old.Type, old.ContentsHash, quiet, verbose, debug)
+
+	if debug {
+		log.Printf("[documents] RevertDocument(%d, (%d, %d), %d, %d) revision %d\n", actor.ID, owner.GameID, owner.ClanID, documentId, revision, newRevision)
+	}
+
+	return &RevisionView{
+		ID:            fmt.Sprintf("%d-%d", documentId, newRevision),
+		DocumentId:    fmt.Sprintf("%d", documentId),
+		Revision:      int(newRevision),
+		ContentLength: old.ContentsLength,
+		ContentsHash:  old.ContentsHash,
+		UploadedBy:    user.Handle,
+		Reason:        reason,
+		IsLatest:      true,
+		CreatedAt:     now,
+	}, nil
+}
+
+// createRevision records new contents for a document and returns the
+// revision number. The contents are stored in the blob table, which is
+// a no-op if another document or revision already has the same contents.
+// Extracts are added to the search index, and the documents derived
+// from this one are marked stale if it had earlier contents.
+// The caller is responsible for document_contents.
+func (s *Service) createRevision(ctx context.Context, qtx *sqlc.Queries, actor *domains.Actor, documentId int64, documentType string, contents []byte, contentLength int, contentsHash, reason string, createdAt int64) (int64, error) {
+	err := s.blobs.Put(ctx, qtx, contentsHash, contents, compressible(domains.DocumentType(documentType)), createdAt)
+	if err != nil {
+		return 0, err
+	}
+	// keep the search index on the latest contents of the extracts
+	if domains.DocumentType(documentType) == domains.TurnReportExtract {
+		err = search.Index(ctx, qtx, documentId, contents)
+	} else {
+		err = search.Unindex(ctx, qtx, documentId)
+	}
+	if err != nil {
+		return 0, err
+	}
+	// documents derived from this one are out of date if the document had earlier contents
+	latest, err := qtx.ReadDocumentLatestRevision(ctx, documentId)
+	if err != nil {
+		return 0, err
+	}
+	if err = dag.Record(ctx, qtx, documentId, latest != 0, createdAt); err != nil {
+		return 0, err
+	}
+	return qtx.CreateDocumentRevision(ctx, sqlc.CreateDocumentRevisionParams{
+		DocumentID:    documentId,
+		ContentLength: int64(contentLength),
+		ContentsHash:  contentsHash,
+		UploadedBy:    int64(actor.ID),
+		Reason:        reason,
+		CreatedAt:     createdAt,
+	})
+}
+
+// compressible returns true for the text documents that are worth
+// compressing. Word documents are already zip files.
+func compressible(documentType domains.DocumentType) bool {
+	switch documentType {
+	case domains.TurnReportExtract, domains.WorldographerMap:
+		return true
+	}
+	return false
+}
+
+// revisionReason returns the reason from the document, or the default
+// for the operation if the caller didn't give one.
+func revisionReason(doc *domains.Document, defaultReason string) string {
+	if doc.Reason != "" {
+		return doc.Reason
+	}
+	return defaultReason
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package documents_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+)
+
+func TestRevisions(t *testing.T) {
+	f := newFixture(t)
+	id := f.replaceMap(t, f.alice, f.c0987, "0899-12.0987.wxx", "first")
+	f.replaceMap(t, f.alice, f.c0987, "0899-12.0987.wxx", "second")
+
+	// list, newest first
+	list, err := f.svc.ReadDocumentRevisions(f.alice, f.c0987, id, true, false, false)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("list: got %d revisions, want 2", len(list))
+	}
+	if list[0].Revision != 2 || !list[0].IsLatest || list[0].Reason != "replaced" {
+		t.Errorf("list[0]: got %d %v %q, want 2 true \"replaced\"", list[0].Revision, list[0].IsLatest, list[0].Reason)
+	}
+	if list[1].Revision != 1 || list[1].IsLatest || list[1].Reason != "created" {
+		t.Errorf("list[1]: got %d %v %q, want 1 false \"created\"", list[1].Revision, list[1].IsLatest, list[1].Reason)
+	}
+
+	// the list and the contents are checked against the owner's clan
+	if list, err := f.svc.ReadDocumentRevisions(f.alice, f.c0988, id, true, false, false); err != nil {
+		t.Errorf("list wrong clan: %v", err)
+	} else if len(list) != 0 {
+		t.Errorf("list wrong clan: got %d revisions, want 0", len(list))
+	}
+
+	// read
+	for _, tc := range []struct {
+		name     string
+		owner    *domains.Clan
+		revision int
+		want     string
+		err      error
+	}{
+		{"first", f.c0987, 1, "first", nil},
+		{"second", f.c0987, 2, "second", nil},
+		{"missing revision", f.c0987, 3, "", domains.ErrNotExists},
+		{"wrong clan", f.c0988, 1, "", domains.ErrNotExists},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			doc, err := f.svc.ReadDocumentRevisionContents(f.alice, tc.owner, id, tc.revision, true, false, false)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("got %v, want %v", err, tc.err)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(doc.Contents); got != tc.want {
+				t.Errorf("contents: got %q, want %q", got, tc.want)
+			}
+			if doc.Revision != tc.revision {
+				t.Errorf("revision: got %d, want %d", doc.Revision, tc.revision)
+			}
+		})
+	}
+
+	// revert
+	for _, tc := range []struct {
+		name  string
+		actor *domains.Actor
+		owner *domains.Clan
+		err   error
+	}{
+		{"wrong clan", f.alice, f.c0988, domains.ErrNotExists},
+		{"not the owner", f.bob, f.c0987, domains.ErrNotAuthorized},
+		{"owner", f.alice, f.c0987, nil},
+		{"admin", f.carol, f.c0987, nil},
+	} {
+		t.Run("revert "+tc.name, func(t *testing.T) {
+			view, err := f.svc.RevertDocument(tc.actor, tc.owner, id, 1, true, false, false)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("got %v, want %v", err, tc.err)
+				}
+				return
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if view.Reason != "reverted to revision 1" || !view.IsLatest {
+				t.Errorf("got %q %v, want \"reverted to revision 1\" true", view.Reason, view.IsLatest)
+			}
+			doc, err := f.svc.ReadDocumentContents(f.alice, f.c0987, id, true, false, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(doc.Contents); got != "first" {
+				t.Errorf("contents: got %q, want %q", got, "first")
+			}
+		})
+	}
+
+	// each revert adds a revision instead of rewriting history
+	list, err = f.svc.ReadDocumentRevisions(f.alice, f.c0987, id, true, false, false)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(list) != 4 {
+		t.Errorf("list after revert: got %d revisions, want 4", len(list))
+	}
+}
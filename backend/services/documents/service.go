@@ -20,19 +20,23 @@ import (
 	"github.com/playbymail/ottoapp/backend/iana"
 	"github.com/playbymail/ottoapp/backend/services/authn"
 	"github.com/playbymail/ottoapp/backend/services/authz"
+	"github.com/playbymail/ottoapp/backend/services/reports"
 	"github.com/playbymail/ottoapp/backend/services/users"
+	"github.com/playbymail/ottoapp/backend/stores/blobs"
 	"github.com/playbymail/ottoapp/backend/stores/sqlite"
 	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
 )
 
 // Service provides document management operations.
 type Service struct {
-	db       *sqlite.DB
-	authzSvc *authz.Service
-	usersSvc *users.Service
+	db         *sqlite.DB
+	authzSvc   *authz.Service
+	usersSvc   *users.Service
+	reportsSvc *reports.Service
+	blobs      *blobs.Blobs
 }
 
-func New(db *sqlite.DB, authzSvc *authz.Service, usersSvc *users.Service, quiet, verbose, debug bool) (*Service, error) {
+func New(db *sqlite.DB, authzSvc *authz.Service, usersSvc *users.Service, reportsSvc *reports.Service, blobStore *blobs.Blobs, quiet, verbose, debug bool) (*Service, error) {
 	if authzSvc == nil {
 		authzSvc = authz.New(db)
 	}
@@ -44,11 +48,18 @@ func New(db *sqlite.DB, authzSvc *authz.Service, usersSvc *users.Service, quiet,
 		}
 		usersSvc = users.New(db, authnSvc, authzSvc, ianaSvc)
 	}
-	return &Service{db: db, authzSvc: authzSvc, usersSvc: usersSvc}, nil
+	if blobStore == nil {
+		blobStore = blobs.New(db)
+	}
+	if reportsSvc == nil {
+		reportsSvc = reports.New(db, blobStore, nil, nil)
+	}
+	return &Service{db: db, authzSvc: authzSvc, usersSvc: usersSvc, reportsSvc: reportsSvc, blobs: blobStore}, nil
 }
 
 // CreateDocument creates a document.
-// Returns ErrExists if the document name already exists for the clan.
+// Returns ErrExists if the document name already exists for the clan,
+// even if that document is in the trash.
 //
 // Actor is the user/service creating the document.
 // Owner is the clan will own the new document.
@@ -79,6 +90,12 @@ func (s *Service) CreateDocument(actor *domains.Actor, owner *domains.Clan, doc
 	default:
 		return domains.InvalidID, fmt.Errorf("%q: unknown type", doc.Type)
 	}
+	if err := checkFormat(doc.Type, doc.Contents); err != nil {
+		return domains.InvalidID, err
+	}
+	if err := checkReport(owner, doc); err != nil {
+		return domains.InvalidID, err
+	}
 
 	// start transaction
 	ctx := s.db.Context()
@@ -126,7 +143,6 @@ func (s *Service) CreateDocument(actor *domains.Actor, owner *domains.Clan, doc
 		DocumentID:    documentId,
 		ContentLength: int64(contentLength),
 		ContentsHash:  contentsHash,
-		Contents:      doc.Contents,
 		CreatedAt:     createdAt,
 		UpdatedAt:     updatedAt,
 	})
@@ -134,12 +150,18 @@ func (s *Service) CreateDocument(actor *domains.Actor, owner *domains.Clan, doc
 		log.Printf("[documents] CreateDocument(%d, (%d, %d), (%q, %d), %q) %v", actor.ID, owner.GameID, owner.UserID, owner.GameID, owner.UserID, doc.Path, err)
 		return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
 	}
+	_, err = s.createRevision(ctx, qtx, actor, documentId, documentType, doc.Contents, contentLength, contentsHash, revisionReason(doc, "created"), createdAt)
+	if err != nil {
+		log.Printf("[documents] CreateDocument(%d, (%d, %d), %q) %v", actor.ID, owner.GameID, owner.UserID, doc.Path, err)
+		return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
+	}
 
 	err = tx.Commit()
 	if err != nil {
 		log.Printf("[documents] CreateDocument(%d, (%d, %d), %q) %v", actor.ID, owner.GameID, owner.UserID, doc.Path, err)
 		return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
 	}
+	s.parseReport(domains.ID(documentId), doc.Type, contentsHash, quiet, verbose, debug)
 
 	if debug {
 		log.Printf("[documents] CreateDocument(%d, (%d, %d), %q, %q) %d\n", actor.ID, owner.GameID, owner.UserID, doc.Path, doc.Type, documentId)
@@ -152,7 +174,7 @@ func (s *Service) CreateDocument(actor *domains.Actor, owner *domains.Clan, doc
 func (s *Service) ReadDocument(actor *domains.Actor, owner *domains.Clan, documentId domains.ID, quiet, verbose, debug bool) (*DocumentView, error) {
 	// start transaction
 	if debug {
-		log.Printf("[documents] ReadDocument(%d, (%d, %d) %q) %d\n", actor.ID, owner.GameID, owner.ClanID, documentId)
+		log.Printf("[documents] ReadDocument(%d, (%d, %d), %d)\n", actor.ID, owner.GameID, owner.ClanID, documentId)
 	}
 	ctx := s.db.Context()
 	tx, err := s.db.Stdlib().BeginTx(ctx, nil)
@@ -193,13 +215,14 @@ func (s *Service) ReadDocument(actor *domains.Actor, owner *domains.Clan, docume
 		ID:           fmt.Sprintf("%d", d.DocumentID),
 		OwnerHandle:  handles[owner.UserID],
 		UserHandle:   handles[owner.UserID],
-		GameId:       fmt.Sprintf("%s", d.GameID),
+		GameId:       fmt.Sprintf("%d", d.GameID),
 		ClanNo:       fmt.Sprintf("%04d", owner.ClanNo),
 		DocumentName: d.DocumentName,
 		DocumentType: d.DocumentType,
 		ModifiedAt:   time.Unix(d.ModifiedAt, 0).UTC(),
 		CreatedAt:    time.Unix(d.CreatedAt, 0).UTC(),
 		UpdatedAt:    time.Unix(d.UpdatedAt, 0).UTC(),
+		Shared:       owner.UserID != actor.ID,
 	}
 
 	return view, nil
@@ -209,7 +232,7 @@ func (s *Service) ReadDocument(actor *domains.Actor, owner *domains.Clan, docume
 func (s *Service) ReadDocumentContents(actor *domains.Actor, owner *domains.Clan, documentId domains.ID, quiet, verbose, debug bool) (*domains.Document, error) {
 	// start transaction
 	if debug {
-		log.Printf("[documents] ReadDocumentContents(%d, (%d, %d) %q) %d\n", actor.ID, owner.GameID, owner.ClanID, documentId)
+		log.Printf("[documents] ReadDocumentContents(%d, (%d, %d), %d)\n", actor.ID, owner.GameID, owner.ClanID, documentId)
 	}
 	ctx := s.db.Context()
 	tx, err := s.db.Stdlib().BeginTx(ctx, nil)
@@ -256,6 +279,11 @@ func (s *Service) ReadDocumentContents(actor *domains.Actor, owner *domains.Clan
 	default:
 		return nil, fmt.Errorf("%q: unknown type", d.DocumentType)
 	}
+	contents, err := s.blobs.Get(ctx, qtx, d.ContentsHash)
+	if err != nil {
+		log.Printf("[documents] ReadDocumentContents(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
 
 	return &domains.Document{
 		ID:             domains.ID(d.DocumentID),
@@ -264,9 +292,10 @@ func (s *Service) ReadDocumentContents(actor *domains.Actor, owner *domains.Clan
 		ClanNo:         int(d.Clan),
 		Path:           d.DocumentName,
 		Type:           documentType,
-		ContentsLength: len(d.Contents),
-		ContentType:    d.ContentType,
-		Contents:       d.Contents,
+		ContentsLength: len(contents),
+		ContentsHash:   d.ContentsHash,
+		ContentType:    contentType(documentType, d.ContentType, contents),
+		Contents:       contents,
 		ModifiedAt:     time.Unix(d.ModifiedAt, 0).UTC(),
 		CreatedAt:      time.Unix(d.CreatedAt, 0).UTC(),
 		UpdatedAt:      time.Unix(d.UpdatedAt, 0).UTC(),
@@ -274,6 +303,7 @@ func (s *Service) ReadDocumentContents(actor *domains.Actor, owner *domains.Clan
 }
 
 // ReadDocumentsByUser returns an unsorted list of documents that the actor has permissions to view.
+// Documents that other users have shared with the user are included and marked as shared.
 // Returns an empty list (not a nil list) if there are no documents.
 func (s *Service) ReadDocumentsByUser(actor *domains.Actor, userId domains.ID, docType domains.DocumentType, pageNumber, pageSize int, quiet, verbose, debug bool) ([]*DocumentView, error) {
 	var documentType string
@@ -354,6 +384,7 @@ func (s *Service) ReadDocumentsByUser(actor *domains.Actor, userId domains.ID, d
 				return nil, errors.Join(fmt.Errorf("GetUserHandle(%d)", ownerClan.UserID), err)
 			}
 			handles[ownerClan.UserID] = user.Handle
+			ownerHandle = user.Handle
 		}
 		view := &DocumentView{
 			ID:           fmt.Sprintf("%d", doc.DocumentID),
@@ -369,6 +400,50 @@ func (s *Service) ReadDocumentsByUser(actor *domains.Actor, userId domains.ID, d
 		}
 		list = append(list, view)
 	}
+
+	// add the documents that other users have shared with the user
+	shared, err := qtx.ReadDocumentsSharedWithUser(ctx, sqlc.ReadDocumentsSharedWithUserParams{
+		UserID: int64(userId),
+		Now:    sql.NullInt64{Int64: time.Now().UTC().Unix(), Valid: true},
+	})
+	if err != nil {
+		log.Printf("[documents] ReadDocumentsByUser(%d, %d, %q) %v\n", actor.ID, userId, docType, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	for _, doc := range shared {
+		matchedType := documentType == "*" || documentType == doc.DocumentType
+		if !matchedType {
+			continue
+		}
+		ownerHandle, ok := handles[domains.ID(doc.UserID)]
+		if !ok {
+			user, err := qtx.ReadUserByUserId(ctx, doc.UserID)
+			if err != nil {
+				return nil, errors.Join(fmt.Errorf("GetUserHandle(%d)", doc.UserID), err)
+			}
+			handles[domains.ID(doc.UserID)] = user.Handle
+			ownerHandle = user.Handle
+		}
+		view := &DocumentView{
+			ID:           fmt.Sprintf("%d", doc.DocumentID),
+			OwnerHandle:  ownerHandle,
+			UserHandle:   ownerHandle,
+			GameId:       strconv.FormatInt(doc.GameID, 10),
+			ClanNo:       fmt.Sprintf("%04d", doc.Clan),
+			DocumentName: doc.DocumentName,
+			DocumentType: doc.DocumentType,
+			ModifiedAt:   time.Unix(doc.ModifiedAt, 0).UTC(),
+			CreatedAt:    time.Unix(doc.CreatedAt, 0).UTC(),
+			UpdatedAt:    time.Unix(doc.UpdatedAt, 0).UTC(),
+			Shared:       true,
+		}
+		if doc.ExpiresAt.Valid {
+			sharedUntil := time.Unix(doc.ExpiresAt.Int64, 0).UTC()
+			view.SharedUntil = &sharedUntil
+		}
+		list = append(list, view)
+	}
+
 	if list == nil {
 		list = []*DocumentView{}
 	}
@@ -394,7 +469,9 @@ func (s *Service) ReadDocumentOwner(documentId domains.ID, quiet, verbose, debug
 	}, nil
 }
 
-// ReplaceDocument overwrites a document by deleting (if it exists) and creating a new one
+// ReplaceDocument creates the document if it does not exist; otherwise it
+// replaces the contents and meta-data. The old contents are kept as a revision.
+// A document in the trash is restored.
 func (s *Service) ReplaceDocument(actor *domains.Actor, owner *domains.Clan, doc *domains.Document, quiet, verbose, debug bool) (domains.ID, error) {
 	if doc.Path != html.EscapeString(doc.Path) {
 		return domains.InvalidID, ErrInvalidPath
@@ -419,6 +496,12 @@ func (s *Service) ReplaceDocument(actor *domains.Actor, owner *domains.Clan, doc
 	default:
 		return domains.InvalidID, fmt.Errorf("%q: unknown type", doc.Type)
 	}
+	if err := checkFormat(doc.Type, doc.Contents); err != nil {
+		return domains.InvalidID, err
+	}
+	if err := checkReport(owner, doc); err != nil {
+		return domains.InvalidID, err
+	}
 
 	// start transaction
 	ctx := s.db.Context()
@@ -432,7 +515,8 @@ func (s *Service) ReplaceDocument(actor *domains.Actor, owner *domains.Clan, doc
 	now := time.Now().UTC()
 	createdAt, updatedAt := now.Unix(), now.Unix()
 
-	err = qtx.DeleteDocumentByClanAndNameAuthorized(ctx, sqlc.DeleteDocumentByClanAndNameAuthorizedParams{
+	var documentId int64
+	d, err := qtx.ReadDocumentByClanAndName(ctx, sqlc.ReadDocumentByClanAndNameParams{
 		ClanID:       int64(owner.ClanID),
 		DocumentName: doc.Path,
 	})
@@ -441,34 +525,80 @@ func (s *Service) ReplaceDocument(actor *domains.Actor, owner *domains.Clan, doc
 			log.Printf("[documents] ReplaceDocument(%d, (%d, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
 			return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
 		}
-	}
 
-	// create the document
-	documentId, err := qtx.CreateDocument(ctx, sqlc.CreateDocumentParams{
-		ClanID:       int64(owner.ClanID),
-		DocumentName: doc.Path,
-		DocumentType: documentType,
-		ModifiedAt:   doc.ModifiedAt.Unix(),
-		CreatedAt:    createdAt,
-		UpdatedAt:    updatedAt,
-	})
-	if err != nil {
-		log.Printf("[documents] ReplaceDocument(%d, (%d, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
-		return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
-	}
+		// create the document
+		documentId, err = qtx.CreateDocument(ctx, sqlc.CreateDocumentParams{
+			ClanID:       int64(owner.ClanID),
+			DocumentName: doc.Path,
+			DocumentType: documentType,
+			ModifiedAt:   doc.ModifiedAt.Unix(),
+			CreatedAt:    createdAt,
+			UpdatedAt:    updatedAt,
+		})
+		if err != nil {
+			log.Printf("[documents] ReplaceDocument(%d, (%d, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
+			return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
+		}
 
-	// upload the contents
-	err = qtx.CreateDocumentContents(ctx, sqlc.CreateDocumentContentsParams{
-		DocumentID:    documentId,
-		ContentLength: int64(contentLength),
-		ContentsHash:  contentsHash,
-		Contents:      doc.Contents,
-		CreatedAt:     createdAt,
-		UpdatedAt:     updatedAt,
-	})
-	if err != nil {
-		log.Printf("[documents] ReplaceDocument(%d, (%d, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
-		return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
+		// upload the contents
+		err = qtx.CreateDocumentContents(ctx, sqlc.CreateDocumentContentsParams{
+			DocumentID:    documentId,
+			ContentLength: int64(contentLength),
+			ContentsHash:  contentsHash,
+			CreatedAt:     createdAt,
+			UpdatedAt:     updatedAt,
+		})
+		if err != nil {
+			log.Printf("[documents] ReplaceDocument(%d, (%d, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
+			return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
+		}
+		_, err = s.createRevision(ctx, qtx, actor, documentId, documentType, doc.Contents, contentLength, contentsHash, revisionReason(doc, "created"), createdAt)
+		if err != nil {
+			log.Printf("[documents] ReplaceDocument(%d, (%d, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
+			return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
+		}
+	} else {
+		documentId = d.DocumentID
+
+		// replacing a document in the trash restores it
+		if d.DeletedAt.Valid {
+			if _, err = qtx.RestoreDocument(ctx, sqlc.RestoreDocumentParams{UpdatedAt: updatedAt, DocumentID: documentId}); err != nil {
+				log.Printf("[documents] ReplaceDocument(%d, (%d, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
+				return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
+			}
+		}
+
+		// replace the contents, keeping the old ones as a revision
+		if contentsHash != d.ContentsHash {
+			err = qtx.UpdateDocumentContentsById(ctx, sqlc.UpdateDocumentContentsByIdParams{
+				DocumentID:    documentId,
+				ContentLength: int64(contentLength),
+				ContentsHash:  contentsHash,
+				UpdatedAt:     updatedAt,
+			})
+			if err != nil {
+				log.Printf("[documents] ReplaceDocument(%d, (%d, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
+				return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
+			}
+			_, err = s.createRevision(ctx, qtx, actor, documentId, documentType, doc.Contents, contentLength, contentsHash, revisionReason(doc, "replaced"), updatedAt)
+			if err != nil {
+				log.Printf("[documents] ReplaceDocument(%d, (%d, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
+				return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
+			}
+		}
+
+		err = qtx.UpdateDocumentById(ctx, sqlc.UpdateDocumentByIdParams{
+			ClanID:       d.ClanID,
+			DocumentID:   documentId,
+			DocumentName: d.DocumentName,
+			DocumentType: documentType,
+			ModifiedAt:   doc.ModifiedAt.Unix(),
+			UpdatedAt:    updatedAt,
+		})
+		if err != nil {
+			log.Printf("[documents] ReplaceDocument(%d, (%d, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
+			return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
+		}
 	}
 
 	err = tx.Commit()
@@ -476,6 +606,7 @@ func (s *Service) ReplaceDocument(actor *domains.Actor, owner *domains.Clan, doc
 		log.Printf("[documents] ReplaceDocument(%d, (%d, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
 		return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
 	}
+	s.parseReport(domains.ID(documentId), doc.Type, contentsHash, quiet, verbose, debug)
 
 	if debug {
 		log.Printf("[documents] ReplaceDocument(%d, (%d, %d), %q) %d\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, documentId)
@@ -485,7 +616,7 @@ func (s *Service) ReplaceDocument(actor *domains.Actor, owner *domains.Clan, doc
 }
 
 // SyncDocument will create the document if it does not exist; otherwise it will
-// update it if it has changed.
+// update it if it has changed. A document in the trash is restored.
 func (s *Service) SyncDocument(actor *domains.Actor, owner *domains.Clan, doc *domains.Document, quiet, verbose, debug bool) (domains.ID, error) {
 	if doc.Path != html.EscapeString(doc.Path) {
 		return domains.InvalidID, ErrInvalidPath
@@ -510,6 +641,12 @@ func (s *Service) SyncDocument(actor *domains.Actor, owner *domains.Clan, doc *d
 	default:
 		return domains.InvalidID, fmt.Errorf("%q: unknown type", doc.Type)
 	}
+	if err := checkFormat(doc.Type, doc.Contents); err != nil {
+		return domains.InvalidID, err
+	}
+	if err := checkReport(owner, doc); err != nil {
+		return domains.InvalidID, err
+	}
 
 	// start transaction
 	ctx := s.db.Context()
@@ -553,7 +690,6 @@ func (s *Service) SyncDocument(actor *domains.Actor, owner *domains.Clan, doc *d
 			DocumentID:    documentId,
 			ContentLength: int64(contentLength),
 			ContentsHash:  contentsHash,
-			Contents:      doc.Contents,
 			CreatedAt:     createdAt,
 			UpdatedAt:     updatedAt,
 		})
@@ -561,29 +697,47 @@ func (s *Service) SyncDocument(actor *domains.Actor, owner *domains.Clan, doc *d
 			log.Printf("[documents] SyncDocument(%d, (%q, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
 			return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
 		}
+		_, err = s.createRevision(ctx, qtx, actor, documentId, documentType, doc.Contents, contentLength, contentsHash, revisionReason(doc, "created"), createdAt)
+		if err != nil {
+			log.Printf("[documents] SyncDocument(%d, (%q, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
+			return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
+		}
 
 		err = tx.Commit()
 		if err != nil {
 			log.Printf("[documents] SyncDocument(%d, (%q, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
 			return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
 		}
+		s.parseReport(domains.ID(documentId), doc.Type, contentsHash, quiet, verbose, debug)
 		return domains.ID(documentId), nil
 	}
 	doc.ID = domains.ID(d.DocumentID)
 
+	// syncing a document in the trash restores it
+	if d.DeletedAt.Valid {
+		if _, err = qtx.RestoreDocument(ctx, sqlc.RestoreDocumentParams{UpdatedAt: updatedAt, DocumentID: d.DocumentID}); err != nil {
+			log.Printf("[documents] SyncDocument(%d, (%q, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
+			return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
+		}
+	}
+
 	// do we need to update the document contents and meta-data?
-	if doc.ContentsHash != d.ContentsHash {
+	if contentsHash != d.ContentsHash {
 		err = qtx.UpdateDocumentContentsById(ctx, sqlc.UpdateDocumentContentsByIdParams{
 			DocumentID:    d.DocumentID,
 			ContentLength: int64(contentLength),
 			ContentsHash:  contentsHash,
-			Contents:      doc.Contents,
 			UpdatedAt:     updatedAt,
 		})
 		if err != nil {
 			log.Printf("[documents] SyncDocument(%d, (%q, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
 			return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
 		}
+		_, err = s.createRevision(ctx, qtx, actor, d.DocumentID, documentType, doc.Contents, contentLength, contentsHash, revisionReason(doc, "synced"), updatedAt)
+		if err != nil {
+			log.Printf("[documents] SyncDocument(%d, (%q, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
+			return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
+		}
 
 		err = qtx.UpdateDocumentById(ctx, sqlc.UpdateDocumentByIdParams{
 			ClanID:       d.ClanID,
@@ -604,6 +758,7 @@ func (s *Service) SyncDocument(actor *domains.Actor, owner *domains.Clan, doc *d
 		log.Printf("[documents] SyncDocument(%d, (%q, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
 		return domains.InvalidID, errors.Join(domains.ErrDatabaseError, err)
 	}
+	s.parseReport(doc.ID, doc.Type, contentsHash, quiet, verbose, debug)
 
 	if debug {
 		log.Printf("[documents] SyncDocument(%d, (%q, %d), %q) %d\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, doc.ID)
@@ -637,6 +792,12 @@ func (s *Service) UpdateDocument(actor *domains.Actor, owner *domains.Clan, doc
 	default:
 		return fmt.Errorf("%q: unknown type", doc.Type)
 	}
+	if err := checkFormat(doc.Type, doc.Contents); err != nil {
+		return err
+	}
+	if err := checkReport(owner, doc); err != nil {
+		return err
+	}
 
 	// start transaction
 	ctx := s.db.Context()
@@ -660,23 +821,30 @@ func (s *Service) UpdateDocument(actor *domains.Actor, owner *domains.Clan, doc
 			return errors.Join(domains.ErrDatabaseError, err)
 		}
 		return domains.ErrNotExists
+	} else if d.DeletedAt.Valid {
+		// documents in the trash must be restored before they are updated
+		return domains.ErrNotExists
 	}
 	doc.ID = domains.ID(d.DocumentID)
 
 	// do we need to update the document contents?
 	updatedContents := false
-	if doc.ContentsHash != d.ContentsHash {
+	if contentsHash != d.ContentsHash {
 		err = qtx.UpdateDocumentContentsById(ctx, sqlc.UpdateDocumentContentsByIdParams{
 			DocumentID:    d.DocumentID,
 			ContentLength: int64(contentLength),
 			ContentsHash:  contentsHash,
-			Contents:      doc.Contents,
 			UpdatedAt:     updatedAt,
 		})
 		if err != nil {
 			log.Printf("[documents] UpdateDocument(%d, (%q, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
 			return errors.Join(domains.ErrDatabaseError, err)
 		}
+		_, err = s.createRevision(ctx, qtx, actor, d.DocumentID, documentType, doc.Contents, contentLength, contentsHash, revisionReason(doc, "updated"), updatedAt)
+		if err != nil {
+			log.Printf("[documents] UpdateDocument(%d, (%q, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
+			return errors.Join(domains.ErrDatabaseError, err)
+		}
 		updatedContents = true
 	}
 
@@ -701,6 +869,7 @@ func (s *Service) UpdateDocument(actor *domains.Actor, owner *domains.Clan, doc
 		log.Printf("[documents] UpdateDocument(%d, (%q, %d), %q) %v\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, err)
 		return errors.Join(domains.ErrDatabaseError, err)
 	}
+	s.parseReport(doc.ID, doc.Type, contentsHash, quiet, verbose, debug)
 
 	if debug {
 		log.Printf("[documents] UpdateDocument(%d, (%q, %d), %q) %d\n", actor.ID, owner.GameID, owner.ClanID, doc.Path, doc.ID)
@@ -709,32 +878,40 @@ func (s *Service) UpdateDocument(actor *domains.Actor, owner *domains.Clan, doc
 	return nil
 }
 
-// DeleteDocument will return nil if the document doesn't exist.
+// DeleteDocument moves the document to the trash. It stays there until it
+// is restored or purged after TrashRetention.
+// Returns nil if the document doesn't exist or is already in the trash.
 // Returns an error if the actor is not authorized to delete it.
 func (s *Service) DeleteDocument(actor *domains.Actor, owner *domains.Clan, documentId domains.ID) error {
-	err := s.db.Queries().DeleteDocumentByIdAuthorized(s.db.Context(), sqlc.DeleteDocumentByIdAuthorizedParams{
+	if !s.authzSvc.CanDeleteDocuments(actor, &domains.Actor{ID: owner.UserID}) {
+		return domains.ErrNotAuthorized
+	}
+	now := time.Now().UTC()
+	_, err := s.db.Queries().TrashDocument(s.db.Context(), sqlc.TrashDocumentParams{
+		DeletedAt:  sql.NullInt64{Int64: now.Unix(), Valid: true},
+		DeletedBy:  sql.NullInt64{Int64: int64(actor.ID), Valid: true},
 		DocumentID: int64(documentId),
 		ClanID:     int64(owner.ClanID),
 	})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			// no document, so not an error
-			return nil
-		}
-		log.Printf("[documents] DeleteDocument(%d, (%q, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+		log.Printf("[documents] DeleteDocument(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
 		return errors.Join(domains.ErrDatabaseError, err)
 	}
 	return nil
 }
 
 func (s *Service) ReadReportExtractContents(documentId domains.ID) ([]byte, error) {
-	return s.db.Queries().ReadDocumentContents(s.db.Context(), int64(documentId))
+	contentsHash, err := s.db.Queries().ReadDocumentContentsHash(s.db.Context(), int64(documentId))
+	if err != nil {
+		return nil, err
+	}
+	return s.blobs.Get(s.db.Context(), s.db.Queries(), contentsHash)
 }
 
 func (s *Service) ReadReportExtractMeta() ([]*domains.Document, error) {
 	rows, err := s.db.Queries().ReadReportExtracts(s.db.Context())
 	if err != nil {
-		log.Printf("[documents] ReadReportExtractMeta:\n", err)
+		log.Printf("[documents] ReadReportExtractMeta: %v\n", err)
 		return nil, errors.Join(domains.ErrDatabaseError, err)
 	}
 
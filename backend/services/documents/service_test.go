@@ -0,0 +1,85 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package documents_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/services/documents"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite"
+)
+
+// fixture is a game with two players and an admin who doesn't play.
+type fixture struct {
+	db    *sqlite.DB
+	svc   *documents.Service
+	alice *domains.Actor // owns clan 0987
+	bob   *domains.Actor // owns clan 0988
+	carol *domains.Actor // admin
+	c0987 *domains.Clan
+	c0988 *domains.Clan
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	ctx := context.Background()
+	db, err := sqlite.OpenTempDB(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	for _, stmt := range []string{
+		`INSERT INTO users (user_id, handle, username, email, timezone, is_active, is_user, created_at, updated_at)
+		 VALUES (2, 'alice', 'alice', 'alice@example.com', 'UTC', 1, 1, 1, 1)`,
+		`INSERT INTO users (user_id, handle, username, email, timezone, is_active, is_user, created_at, updated_at)
+		 VALUES (3, 'bob', 'bob', 'bob@example.com', 'UTC', 1, 1, 1, 1)`,
+		`INSERT INTO users (user_id, handle, username, email, timezone, is_active, is_admin, is_user, created_at, updated_at)
+		 VALUES (4, 'carol', 'carol', 'carol@example.com', 'UTC', 1, 1, 1, 1, 1)`,
+		`INSERT INTO games (game_id, code, description, active_turn, setup_turn, orders_due, created_at, updated_at)
+		 VALUES (1, '0301', 'test game', '0899-12', '0899-12', 1, 1, 1)`,
+		`INSERT INTO game_turns (game_id, turn, turn_year, turn_month, turn_no, created_at, updated_at)
+		 VALUES (1, '0899-12', 899, 12, 0, 1, 1)`,
+		`INSERT INTO clans (clan_id, game_id, user_id, clan, setup_turn, created_at, updated_at)
+		 VALUES (1, 1, 2, 987, '0899-12', 1, 1)`,
+		`INSERT INTO clans (clan_id, game_id, user_id, clan, setup_turn, created_at, updated_at)
+		 VALUES (2, 1, 3, 988, '0899-12', 1, 1)`,
+	} {
+		if _, err := db.Stdlib().ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("fixture: %v", err)
+		}
+	}
+
+	svc, err := documents.New(db, nil, nil, nil, nil, true, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &fixture{
+		db:    db,
+		svc:   svc,
+		alice: &domains.Actor{ID: 2, Roles: domains.Roles{Active: true, User: true}},
+		bob:   &domains.Actor{ID: 3, Roles: domains.Roles{Active: true, User: true}},
+		carol: &domains.Actor{ID: 4, Roles: domains.Roles{Active: true, Admin: true, User: true}},
+		c0987: &domains.Clan{GameID: 1, UserID: 2, ClanID: 1, ClanNo: 987, IsActive: true},
+		c0988: &domains.Clan{GameID: 1, UserID: 3, ClanID: 2, ClanNo: 988, IsActive: true},
+	}
+}
+
+// replaceMap stores a map for the clan and returns the document id.
+// Maps aren't validated, so the contents can be anything.
+func (f *fixture) replaceMap(t *testing.T, actor *domains.Actor, owner *domains.Clan, name, contents string) domains.ID {
+	t.Helper()
+	id, err := f.svc.ReplaceDocument(actor, owner, &domains.Document{
+		Path:       name,
+		Type:       domains.WorldographerMap,
+		Contents:   []byte(contents),
+		ModifiedAt: time.Now().UTC(),
+	}, true, false, false)
+	if err != nil {
+		t.Fatalf("replace %s: %v", name, err)
+	}
+	return id
+}
@@ -0,0 +1,186 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package documents
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+// ShareGrant names the clan or user to share a document with.
+// Exactly one of ClanNo and Handle must be set. Clans must be in the
+// same game as the document. A zero ExpiresAt means the share lasts
+// until it is revoked.
+type ShareGrant struct {
+	ClanNo    int
+	Handle    string
+	ExpiresAt time.Time
+}
+
+// ShareDocument grants read access to the document to another clan or user.
+// Returns ErrInvalidShare if the grant is not valid and domains.ErrNotExists
+// if the clan or user doesn't exist.
+func (s *Service) ShareDocument(actor *domains.Actor, owner *domains.Clan, documentId domains.ID, grant ShareGrant, quiet, verbose, debug bool) (*ShareView, error) {
+	if !s.authzSvc.CanShareDocuments(actor, &domains.Actor{ID: owner.UserID}) {
+		return nil, domains.ErrNotAuthorized
+	}
+	if (grant.ClanNo == 0) == (grant.Handle == "") {
+		return nil, errors.Join(ErrInvalidShare, fmt.Errorf("share with a clan or a user"))
+	}
+	now := time.Now().UTC()
+	if !grant.ExpiresAt.IsZero() && !grant.ExpiresAt.After(now) {
+		return nil, errors.Join(ErrInvalidShare, fmt.Errorf("expiry is in the past"))
+	}
+
+	params := sqlc.CreateDocumentShareParams{
+		DocumentID: int64(documentId),
+		GrantedBy:  int64(actor.ID),
+		CreatedAt:  now.Unix(),
+		UpdatedAt:  now.Unix(),
+	}
+	view := &ShareView{
+		DocumentId: fmt.Sprintf("%d", documentId),
+		IsActive:   true,
+		CreatedAt:  time.Unix(now.Unix(), 0).UTC(),
+	}
+	if grant.ClanNo != 0 {
+		clan, err := s.FindClanByGameAndNumber(owner.GameID, grant.ClanNo)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, domains.ErrNotExists
+			}
+			log.Printf("[documents] ShareDocument(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+			return nil, errors.Join(domains.ErrDatabaseError, err)
+		} else if clan.UserID == owner.UserID {
+			return nil, errors.Join(ErrInvalidShare, fmt.Errorf("clan already owns the document"))
+		}
+		params.ClanID = sql.NullInt64{Int64: int64(clan.ClanID), Valid: true}
+		view.Clan = fmt.Sprintf("%04d", clan.ClanNo)
+	} else {
+		userId, err := s.usersSvc.GetUserIDByHandle(grant.Handle)
+		if err != nil {
+			if errors.Is(err, domains.ErrNotExists) {
+				return nil, domains.ErrNotExists
+			}
+			log.Printf("[documents] ShareDocument(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+			return nil, err
+		} else if userId == owner.UserID {
+			return nil, errors.Join(ErrInvalidShare, fmt.Errorf("user already owns the document"))
+		}
+		params.UserID = sql.NullInt64{Int64: int64(userId), Valid: true}
+		view.User = grant.Handle
+	}
+	if !grant.ExpiresAt.IsZero() {
+		params.ExpiresAt = sql.NullInt64{Int64: grant.ExpiresAt.Unix(), Valid: true}
+		expiresAt := time.Unix(grant.ExpiresAt.Unix(), 0).UTC()
+		view.ExpiresAt = &expiresAt
+	}
+
+	grantedBy, err := s.usersSvc.GetUserHandle(actor.ID)
+	if err != nil {
+		log.Printf("[documents] ShareDocument(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	view.GrantedBy = grantedBy
+
+	shareId, err := s.db.Queries().CreateDocumentShare(s.db.Context(), params)
+	if err != nil {
+		log.Printf("[documents] ShareDocument(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	view.ID = fmt.Sprintf("%d", shareId)
+	if debug {
+		log.Printf("[documents] ShareDocument(%d, (%d, %d), %d) share %d\n", actor.ID, owner.GameID, owner.ClanID, documentId, shareId)
+	}
+	return view, nil
+}
+
+// ReadDocumentShares returns all the shares for the document, including
+// the expired and revoked ones, oldest first.
+func (s *Service) ReadDocumentShares(actor *domains.Actor, owner *domains.Clan, documentId domains.ID, quiet, verbose, debug bool) ([]*ShareView, error) {
+	if !s.authzSvc.CanShareDocuments(actor, &domains.Actor{ID: owner.UserID}) {
+		return nil, domains.ErrNotAuthorized
+	}
+	rows, err := s.db.Queries().ReadDocumentShares(s.db.Context(), int64(documentId))
+	if err != nil {
+		log.Printf("[documents] ReadDocumentShares(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	now := time.Now().UTC()
+	list := []*ShareView{}
+	for _, row := range rows {
+		view := &ShareView{
+			ID:         fmt.Sprintf("%d", row.ShareID),
+			DocumentId: fmt.Sprintf("%d", row.DocumentID),
+			GrantedBy:  row.GrantedBy,
+			IsActive:   !row.RevokedAt.Valid,
+			CreatedAt:  time.Unix(row.CreatedAt, 0).UTC(),
+		}
+		if row.Clan.Valid {
+			view.Clan = fmt.Sprintf("%04d", row.Clan.Int64)
+		}
+		if row.Handle.Valid {
+			view.User = row.Handle.String
+		}
+		if row.ExpiresAt.Valid {
+			expiresAt := time.Unix(row.ExpiresAt.Int64, 0).UTC()
+			view.ExpiresAt = &expiresAt
+			view.IsActive = view.IsActive && expiresAt.After(now)
+		}
+		if row.RevokedAt.Valid {
+			revokedAt := time.Unix(row.RevokedAt.Int64, 0).UTC()
+			view.RevokedAt = &revokedAt
+		}
+		list = append(list, view)
+	}
+	return list, nil
+}
+
+// RevokeDocumentShare ends a share. Returns domains.ErrNotExists if the
+// document doesn't have the share or it was already revoked.
+func (s *Service) RevokeDocumentShare(actor *domains.Actor, owner *domains.Clan, documentId, shareId domains.ID, quiet, verbose, debug bool) error {
+	if !s.authzSvc.CanShareDocuments(actor, &domains.Actor{ID: owner.UserID}) {
+		return domains.ErrNotAuthorized
+	}
+	n, err := s.db.Queries().RevokeDocumentShare(s.db.Context(), sqlc.RevokeDocumentShareParams{
+		RevokedAt:  sql.NullInt64{Int64: time.Now().UTC().Unix(), Valid: true},
+		ShareID:    int64(shareId),
+		DocumentID: int64(documentId),
+	})
+	if err != nil {
+		log.Printf("[documents] RevokeDocumentShare(%d, (%d, %d), %d, %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, shareId, err)
+		return errors.Join(domains.ErrDatabaseError, err)
+	} else if n == 0 {
+		return domains.ErrNotExists
+	}
+	if debug {
+		log.Printf("[documents] RevokeDocumentShare(%d, (%d, %d), %d, %d) revoked\n", actor.ID, owner.GameID, owner.ClanID, documentId, shareId)
+	}
+	return nil
+}
+
+// CanReadDocument returns true if the actor owns the document or it has
+// been shared with them.
+func (s *Service) CanReadDocument(actor *domains.Actor, owner *domains.Clan, documentId domains.ID) (bool, error) {
+	if s.authzSvc.CanReadDocument(actor, &domains.Actor{ID: owner.UserID}, false) {
+		return true, nil
+	} else if !actor.IsValid() {
+		return false, nil
+	}
+	n, err := s.db.Queries().IsDocumentSharedWithUser(s.db.Context(), sqlc.IsDocumentSharedWithUserParams{
+		DocumentID: int64(documentId),
+		UserID:     int64(actor.ID),
+		Now:        sql.NullInt64{Int64: time.Now().UTC().Unix(), Valid: true},
+	})
+	if err != nil {
+		log.Printf("[documents] CanReadDocument(%d, (%d, %d), %d) %v\n", actor.ID, owner.GameID, owner.ClanID, documentId, err)
+		return false, errors.Join(domains.ErrDatabaseError, err)
+	}
+	return s.authzSvc.CanReadDocument(actor, &domains.Actor{ID: owner.UserID}, n != 0), nil
+}
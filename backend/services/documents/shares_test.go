@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package documents_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/services/documents"
+)
+
+func TestShares(t *testing.T) {
+	f := newFixture(t)
+	id := f.replaceMap(t, f.alice, f.c0987, "0301.0899-12.0987.wxx", "alice's map")
+
+	canRead := func(actor *domains.Actor) bool {
+		t.Helper()
+		ok, err := f.svc.CanReadDocument(actor, f.c0987, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ok
+	}
+	isListed := func(actor *domains.Actor) bool {
+		t.Helper()
+		list, err := f.svc.ReadDocumentsByUser(actor, actor.ID, "", 0, 0, true, false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, view := range list {
+			if view.ID == strconv.Itoa(int(id)) {
+				return view.Shared
+			}
+		}
+		return false
+	}
+	if canRead(f.bob) || isListed(f.bob) {
+		t.Fatalf("bob can read the document before it is shared")
+	}
+
+	// grants that are not valid
+	for _, tc := range []struct {
+		name  string
+		actor *domains.Actor
+		grant documents.ShareGrant
+		err   error
+	}{
+		{"not the owner", f.bob, documents.ShareGrant{ClanNo: 988}, domains.ErrNotAuthorized},
+		{"nobody", f.alice, documents.ShareGrant{}, documents.ErrInvalidShare},
+		{"both", f.alice, documents.ShareGrant{ClanNo: 988, Handle: "bob"}, documents.ErrInvalidShare},
+		{"own clan", f.alice, documents.ShareGrant{ClanNo: 987}, documents.ErrInvalidShare},
+		{"self", f.alice, documents.ShareGrant{Handle: "alice"}, documents.ErrInvalidShare},
+		{"no such clan", f.alice, documents.ShareGrant{ClanNo: 999}, domains.ErrNotExists},
+		{"no such user", f.alice, documents.ShareGrant{Handle: "mallory"}, domains.ErrNotExists},
+		{"expired", f.alice, documents.ShareGrant{ClanNo: 988, ExpiresAt: time.Now().Add(-time.Hour)}, documents.ErrInvalidShare},
+	} {
+		t.Run("grant "+tc.name, func(t *testing.T) {
+			if _, err := f.svc.ShareDocument(tc.actor, f.c0987, id, tc.grant, true, false, false); !errors.Is(err, tc.err) {
+				t.Errorf("got %v, want %v", err, tc.err)
+			}
+		})
+	}
+
+	share, err := f.svc.ShareDocument(f.alice, f.c0987, id, documents.ShareGrant{ClanNo: 988}, true, false, false)
+	if err != nil {
+		t.Fatalf("share: %v", err)
+	} else if share.Clan != "0988" || share.GrantedBy != "alice" || !share.IsActive {
+		t.Errorf("share: got %q %q %v, want \"0988\" \"alice\" true", share.Clan, share.GrantedBy, share.IsActive)
+	}
+	if !canRead(f.bob) {
+		t.Errorf("shared: bob can't read the document")
+	}
+	if !isListed(f.bob) {
+		t.Errorf("shared: the document isn't in bob's list as shared")
+	}
+	if canRead(f.carol) {
+		t.Errorf("shared: carol can read the document")
+	}
+
+	// a shared reader can't change the document or its shares
+	if _, err := f.svc.RevertDocument(f.bob, f.c0987, id, 1, true, false, false); !errors.Is(err, domains.ErrNotAuthorized) {
+		t.Errorf("revert: got %v, want %v", err, domains.ErrNotAuthorized)
+	}
+	if err := f.svc.DeleteDocument(f.bob, f.c0987, id); !errors.Is(err, domains.ErrNotAuthorized) {
+		t.Errorf("delete: got %v, want %v", err, domains.ErrNotAuthorized)
+	}
+	if _, err := f.svc.ShareDocument(f.bob, f.c0987, id, documents.ShareGrant{Handle: "carol"}, true, false, false); !errors.Is(err, domains.ErrNotAuthorized) {
+		t.Errorf("reshare: got %v, want %v", err, domains.ErrNotAuthorized)
+	}
+	if _, err := f.svc.CreateDocumentLink(f.bob, f.c0987, id, time.Time{}, true, false, false); !errors.Is(err, domains.ErrNotAuthorized) {
+		t.Errorf("link: got %v, want %v", err, domains.ErrNotAuthorized)
+	}
+	shareId, err := strconv.Atoi(share.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.svc.RevokeDocumentShare(f.bob, f.c0987, id, domains.ID(shareId), true, false, false); !errors.Is(err, domains.ErrNotAuthorized) {
+		t.Errorf("revoke: got %v, want %v", err, domains.ErrNotAuthorized)
+	}
+
+	// revoking ends the share, once
+	if err := f.svc.RevokeDocumentShare(f.alice, f.c0987, id, domains.ID(shareId), true, false, false); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if err := f.svc.RevokeDocumentShare(f.alice, f.c0987, id, domains.ID(shareId), true, false, false); !errors.Is(err, domains.ErrNotExists) {
+		t.Errorf("revoke again: got %v, want %v", err, domains.ErrNotExists)
+	}
+	if canRead(f.bob) || isListed(f.bob) {
+		t.Errorf("revoked: bob can still read the document")
+	}
+	shares, err := f.svc.ReadDocumentShares(f.alice, f.c0987, id, true, false, false)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(shares) != 1 || shares[0].IsActive || shares[0].RevokedAt == nil {
+		t.Errorf("revoked: want one inactive share")
+	}
+
+	// a share with a user lasts until it expires
+	if _, err := f.svc.ShareDocument(f.alice, f.c0987, id, documents.ShareGrant{Handle: "carol", ExpiresAt: time.Now().Add(time.Hour)}, true, false, false); err != nil {
+		t.Fatalf("share with user: %v", err)
+	}
+	if !canRead(f.carol) {
+		t.Errorf("shared with user: carol can't read the document")
+	}
+}
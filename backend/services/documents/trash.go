@@ -0,0 +1,125 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package documents
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+// TrashRetention is how long deleted documents stay in the trash before
+// they are purged.
+const TrashRetention = 30 * 24 * time.Hour
+
+// ReadDeletedDocuments returns the documents in the trash, oldest first.
+// GMs and sysop see every deleted document; other users see their own.
+func (s *Service) ReadDeletedDocuments(actor *domains.Actor, quiet, verbose, debug bool) ([]*TrashView, error) {
+	if !actor.IsValid() {
+		return nil, domains.ErrNotAuthorized
+	}
+	var rows []sqlc.ReadDeletedDocumentsRow
+	var err error
+	if s.authzSvc.CanListAllDeletedDocuments(actor) {
+		rows, err = s.db.Queries().ReadDeletedDocuments(s.db.Context())
+	} else {
+		var userRows []sqlc.ReadDeletedDocumentsByUserRow
+		userRows, err = s.db.Queries().ReadDeletedDocumentsByUser(s.db.Context(), int64(actor.ID))
+		for _, row := range userRows {
+			rows = append(rows, sqlc.ReadDeletedDocumentsRow(row))
+		}
+	}
+	if err != nil {
+		log.Printf("[documents] ReadDeletedDocuments(%d) %v\n", actor.ID, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	list := []*TrashView{}
+	for _, row := range rows {
+		deletedAt := time.Unix(row.DeletedAt.Int64, 0).UTC()
+		list = append(list, &TrashView{
+			ID:           fmt.Sprintf("%d", row.DocumentID),
+			OwnerHandle:  row.Owner,
+			GameId:       row.Code,
+			ClanNo:       fmt.Sprintf("%04d", row.Clan),
+			DocumentName: row.DocumentName,
+			DocumentType: row.DocumentType,
+			DeletedBy:    row.DeletedBy.String,
+			DeletedAt:    deletedAt,
+			PurgeAfter:   deletedAt.Add(TrashRetention),
+		})
+	}
+	return list, nil
+}
+
+// RestoreDocument takes a document out of the trash.
+// Returns domains.ErrNotExists if the document isn't in the trash.
+func (s *Service) RestoreDocument(actor *domains.Actor, documentId domains.ID, quiet, verbose, debug bool) error {
+	owner, err := s.db.Queries().ReadDeletedDocumentOwner(s.db.Context(), int64(documentId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domains.ErrNotExists
+		}
+		log.Printf("[documents] RestoreDocument(%d, %d) %v\n", actor.ID, documentId, err)
+		return errors.Join(domains.ErrDatabaseError, err)
+	}
+	if !s.authzSvc.CanRestoreDocuments(actor, &domains.Actor{ID: domains.ID(owner.UserID)}) {
+		return domains.ErrNotAuthorized
+	}
+	n, err := s.db.Queries().RestoreDocument(s.db.Context(), sqlc.RestoreDocumentParams{
+		UpdatedAt:  time.Now().UTC().Unix(),
+		DocumentID: int64(documentId),
+	})
+	if err != nil {
+		log.Printf("[documents] RestoreDocument(%d, %d) %v\n", actor.ID, documentId, err)
+		return errors.Join(domains.ErrDatabaseError, err)
+	} else if n == 0 {
+		// restored by someone else since we looked
+		return domains.ErrNotExists
+	}
+	if debug {
+		log.Printf("[documents] RestoreDocument(%d, %d) restored\n", actor.ID, documentId)
+	}
+	return nil
+}
+
+// PurgeDocuments deletes the documents that have been in the trash for
+// longer than the retention window and returns how many there were.
+// If dryRun is true, the documents are counted but not deleted.
+// The contents are left for the blob garbage collector.
+func (s *Service) PurgeDocuments(actor *domains.Actor, retention time.Duration, dryRun, quiet, verbose, debug bool) (int, error) {
+	if !s.authzSvc.CanPurgeDocuments(actor) {
+		return 0, domains.ErrNotAuthorized
+	}
+	cutoff := time.Now().UTC().Add(-retention).Unix()
+	if dryRun {
+		rows, err := s.db.Queries().ReadDeletedDocuments(s.db.Context())
+		if err != nil {
+			log.Printf("[documents] PurgeDocuments(%d, %v) %v\n", actor.ID, retention, err)
+			return 0, errors.Join(domains.ErrDatabaseError, err)
+		}
+		n := 0
+		for _, row := range rows {
+			if row.DeletedAt.Int64 < cutoff {
+				if verbose {
+					log.Printf("[documents] purge: %s.%04d: %s\n", row.Code, row.Clan, row.DocumentName)
+				}
+				n++
+			}
+		}
+		return n, nil
+	}
+	n, err := s.db.Queries().PurgeDeletedDocuments(s.db.Context(), sql.NullInt64{Int64: cutoff, Valid: true})
+	if err != nil {
+		log.Printf("[documents] PurgeDocuments(%d, %v) %v\n", actor.ID, retention, err)
+		return 0, errors.Join(domains.ErrDatabaseError, err)
+	}
+	if debug {
+		log.Printf("[documents] PurgeDocuments(%d, %v) purged %d\n", actor.ID, retention, n)
+	}
+	return int(n), nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package documents_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/services/search"
+)
+
+func TestTrash(t *testing.T) {
+	f := newFixture(t)
+	searchSvc := search.New(f.db, nil, nil)
+	id, err := f.svc.ReplaceDocument(f.alice, f.c0987, &domains.Document{
+		Path: "0301.0899-12.0987.report.txt",
+		Type: domains.TurnReportExtract,
+		Contents: []byte("Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)\n" +
+			"Current Turn 899-12 (#0), Winter, FINE\n" +
+			"Tribe Movement: Move NE-PR, Copper mine\n"),
+		ModifiedAt: time.Now().UTC(),
+	}, true, false, false)
+	if err != nil {
+		t.Fatalf("replace: %v", err)
+	}
+	link, err := f.svc.CreateDocumentLink(f.alice, f.c0987, id, time.Time{}, true, false, false)
+	if err != nil {
+		t.Fatalf("link: %v", err)
+	}
+
+	// visible returns true if the extract is in alice's list, her search
+	// results, and can be read through the link.
+	visible := func() (listed, found, linked bool) {
+		t.Helper()
+		list, err := f.svc.ReadDocumentsByUser(f.alice, f.alice.ID, "", 0, 0, true, false, false)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		for _, view := range list {
+			listed = listed || view.ID == strconv.Itoa(int(id))
+		}
+		results, err := searchSvc.Search(f.alice, "copper", 1, 0, true, false, false)
+		if err != nil {
+			t.Fatalf("search: %v", err)
+		}
+		for _, result := range results {
+			found = found || result.DocumentId == strconv.Itoa(int(id))
+		}
+		_, err = f.svc.ReadLinkedContents(link.Token, true, false, false)
+		if err != nil && !errors.Is(err, domains.ErrNotExists) {
+			t.Fatalf("link: %v", err)
+		}
+		return listed, found, err == nil
+	}
+	if listed, found, linked := visible(); !listed || !found || !linked {
+		t.Fatalf("before delete: got listed %v, found %v, linked %v, want all true", listed, found, linked)
+	}
+
+	if err := f.svc.DeleteDocument(f.bob, f.c0987, id); !errors.Is(err, domains.ErrNotAuthorized) {
+		t.Errorf("delete by bob: got %v, want %v", err, domains.ErrNotAuthorized)
+	}
+	if err := f.svc.DeleteDocument(f.alice, f.c0987, id); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if listed, found, linked := visible(); listed || found || linked {
+		t.Errorf("in trash: got listed %v, found %v, linked %v, want all false", listed, found, linked)
+	}
+
+	// the trash only shows the user's own documents
+	if trash, err := f.svc.ReadDeletedDocuments(f.alice, true, false, false); err != nil {
+		t.Fatalf("trash: %v", err)
+	} else if len(trash) != 1 || trash[0].ID != strconv.Itoa(int(id)) || trash[0].DeletedBy != "alice" {
+		t.Errorf("trash: want alice's extract, deleted by alice")
+	}
+	if trash, err := f.svc.ReadDeletedDocuments(f.bob, true, false, false); err != nil {
+		t.Fatalf("trash: %v", err)
+	} else if len(trash) != 0 {
+		t.Errorf("trash: bob got %d documents, want 0", len(trash))
+	}
+
+	if err := f.svc.RestoreDocument(f.bob, id, true, false, false); !errors.Is(err, domains.ErrNotAuthorized) {
+		t.Errorf("restore by bob: got %v, want %v", err, domains.ErrNotAuthorized)
+	}
+	if err := f.svc.RestoreDocument(f.alice, id, true, false, false); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+	if err := f.svc.RestoreDocument(f.alice, id, true, false, false); !errors.Is(err, domains.ErrNotExists) {
+		t.Errorf("restore again: got %v, want %v", err, domains.ErrNotExists)
+	}
+	if listed, found, linked := visible(); !listed || !found || !linked {
+		t.Errorf("restored: got listed %v, found %v, linked %v, want all true", listed, found, linked)
+	}
+}
@@ -0,0 +1,428 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package games
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/parsers/bistre"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+// CalendarTurn is a row in the game_turns table.
+type CalendarTurn struct {
+	Turn   string // YYYY-MM
+	Year   int
+	Month  int
+	TurnNo int
+}
+
+// CalendarReport is the turn information from the first "Current Turn"
+// line in a clan's report extract.
+type CalendarReport struct {
+	DocumentID domains.ID
+	Name       string
+	ClanNo     int
+	Current    CalendarTurn
+	Next       *CalendarTurn // nil if the report has no "Next Turn"
+	NextDue    string        // YYYY-MM-DD, the day orders are due for the next turn
+}
+
+// Disagreement is a report that doesn't match the other reports.
+type Disagreement struct {
+	DocumentID domains.ID
+	Name       string
+	ClanNo     int
+	Field      string // turn-no or orders-due
+	Turn       string // YYYY-MM
+	Got        string // value from the report
+	Want       string // value from most of the reports
+}
+
+func (d *Disagreement) String() string {
+	return fmt.Sprintf("%04d: %s: %s: %s: got %s, want %s", d.ClanNo, d.Name, d.Turn, d.Field, d.Got, d.Want)
+}
+
+// Calendar is the game calendar from the database along with the changes
+// that the reports call for.
+type Calendar struct {
+	GameID     domains.GameID
+	Code       string
+	Timezone   string
+	ActiveTurn string         // YYYY-MM
+	OrdersDue  time.Time      // in the game's time zone; zero if not set
+	DueTime    string         // HH:MM, time of day orders are due; empty to keep the time of OrdersDue
+	Turns      []CalendarTurn // existing game_turns rows
+
+	// proposed changes
+	NewActiveTurn string         // empty if active turn doesn't change
+	NewOrdersDue  time.Time      // zero if orders due doesn't change
+	NewTurns      []CalendarTurn // game_turns rows to create or update
+
+	Disagreements []*Disagreement
+}
+
+// HasChanges returns true if the calendar has proposed changes.
+func (c *Calendar) HasChanges() bool {
+	return c.NewActiveTurn != "" || !c.NewOrdersDue.IsZero() || len(c.NewTurns) != 0
+}
+
+// ReadCalendarReport parses the first "Current Turn" line in a report extract.
+// It returns nil if the report doesn't have one.
+func ReadCalendarReport(name string, contents []byte) (*CalendarReport, error) {
+	for _, line := range bytes.Split(contents, []byte("\n")) {
+		line = bytes.TrimRight(line, " \t\r")
+		if !bytes.HasPrefix(line, []byte("Current Turn ")) {
+			continue
+		}
+		va, err := bistre.Parse(name, line, bistre.Entrypoint("TurnInfo"))
+		if err != nil {
+			return nil, err
+		}
+		ti, ok := va.(bistre.TurnInfo_t)
+		if !ok {
+			return nil, fmt.Errorf("%s: want TurnInfo_t, got %T", name, va)
+		}
+		r := &CalendarReport{
+			Name:    name,
+			Current: calendarTurn(ti.CurrentTurn, ti.CurrentTurnNo),
+		}
+		if ti.NextTurn.Year != 0 {
+			next := calendarTurn(ti.NextTurn, ti.NextTurnNo)
+			r.Next = &next
+			r.NextDue = fmt.Sprintf("%04d-%02d-%02d", ti.NextTurnDate.Year, ti.NextTurnDate.Month, ti.NextTurnDate.Day)
+		}
+		return r, nil
+	}
+	return nil, nil
+}
+
+// Propose compares the reports to the calendar and sets the proposed changes.
+//
+// Every report votes for the turn number of its current and next turns,
+// and for the day that orders are due for its current turn. The value with
+// the most votes wins, and reports that voted for anything else are listed
+// as disagreements.
+//
+// The active turn is the first turn whose orders are due after now, or the
+// latest turn if every deadline has passed. It is never moved backwards.
+// Orders are due at DueTime in the game's time zone; if DueTime is empty,
+// they are due at the same time of day as the current deadline. If neither
+// is set, Propose returns ErrDueTimeNotSet along with the other changes.
+func (c *Calendar) Propose(reports []*CalendarReport, now time.Time) error {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return fmt.Errorf("timezone %q: %w", c.Timezone, err)
+	}
+	c.NewActiveTurn, c.NewOrdersDue, c.NewTurns, c.Disagreements = "", time.Time{}, nil, nil
+	if len(reports) == 0 {
+		return nil
+	}
+
+	// find the time of day that orders are due
+	hour, minute, second, hasTime := 0, 0, 0, false
+	if c.DueTime != "" {
+		t, err := time.Parse("15:04", c.DueTime)
+		if err != nil {
+			return fmt.Errorf("due time %q: %w", c.DueTime, err)
+		}
+		hour, minute, hasTime = t.Hour(), t.Minute(), true
+	} else if !c.OrdersDue.IsZero() {
+		hour, minute, second = c.OrdersDue.In(loc).Clock()
+		hasTime = true
+	}
+	deadline := func(day string) (time.Time, error) {
+		d, err := time.ParseInLocation("2006-01-02", day, loc)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("orders due %q: %w", day, err)
+		}
+		return time.Date(d.Year(), d.Month(), d.Day(), hour, minute, second, 0, loc), nil
+	}
+
+	// find the latest turn
+	var latest string
+	for _, r := range reports {
+		if r.Current.Turn > latest {
+			latest = r.Current.Turn
+		}
+	}
+
+	// count the votes
+	turns := map[string]CalendarTurn{}
+	turnNoVotes := map[string]*ballot{}
+	dueVotes := map[string]*ballot{} // keyed by the current turn
+	vote := func(t CalendarTurn) {
+		turns[t.Turn] = t
+		if turnNoVotes[t.Turn] == nil {
+			turnNoVotes[t.Turn] = &ballot{}
+		}
+		turnNoVotes[t.Turn].add(fmt.Sprintf("%d", t.TurnNo))
+	}
+	for _, r := range reports {
+		vote(r.Current)
+		if r.Next != nil {
+			vote(*r.Next)
+			if dueVotes[r.Current.Turn] == nil {
+				dueVotes[r.Current.Turn] = &ballot{}
+			}
+			dueVotes[r.Current.Turn].add(r.NextDue)
+		}
+	}
+
+	// pick the first turn whose orders are due after now
+	active := latest
+	if hasTime {
+		var dueTurns []string
+		for id := range dueVotes {
+			dueTurns = append(dueTurns, id)
+		}
+		sort.Strings(dueTurns)
+		for _, id := range dueTurns {
+			due, err := deadline(dueVotes[id].winner())
+			if err != nil {
+				return err
+			}
+			if due.After(now) {
+				active = id
+				break
+			}
+		}
+	}
+
+	// flag the reports that disagree with the winners
+	disagree := func(r *CalendarReport, field, turn, got, want string) {
+		c.Disagreements = append(c.Disagreements, &Disagreement{
+			DocumentID: r.DocumentID,
+			Name:       r.Name,
+			ClanNo:     r.ClanNo,
+			Field:      field,
+			Turn:       turn,
+			Got:        got,
+			Want:       want,
+		})
+	}
+	for _, r := range reports {
+		if got, want := fmt.Sprintf("%d", r.Current.TurnNo), turnNoVotes[r.Current.Turn].winner(); got != want {
+			disagree(r, "turn-no", r.Current.Turn, got, want)
+		}
+		if r.Next == nil {
+			continue
+		}
+		if got, want := fmt.Sprintf("%d", r.Next.TurnNo), turnNoVotes[r.Next.Turn].winner(); got != want {
+			disagree(r, "turn-no", r.Next.Turn, got, want)
+		}
+		if want := dueVotes[r.Current.Turn].winner(); r.Current.Turn == active && r.NextDue != want {
+			disagree(r, "orders-due", r.Next.Turn, r.NextDue, want)
+		}
+	}
+
+	// propose game_turns rows that are missing or have the wrong turn number
+	existing := map[string]CalendarTurn{}
+	for _, t := range c.Turns {
+		existing[t.Turn] = t
+	}
+	for id, t := range turns {
+		fmt.Sscanf(turnNoVotes[id].winner(), "%d", &t.TurnNo)
+		if e, ok := existing[id]; !ok || e != t {
+			c.NewTurns = append(c.NewTurns, t)
+		}
+	}
+	sort.Slice(c.NewTurns, func(i, j int) bool {
+		return c.NewTurns[i].Turn < c.NewTurns[j].Turn
+	})
+
+	if active > c.ActiveTurn {
+		c.NewActiveTurn = active
+	}
+	if active >= c.ActiveTurn && dueVotes[active] != nil {
+		if !hasTime {
+			return ErrDueTimeNotSet
+		}
+		due, err := deadline(dueVotes[active].winner())
+		if err != nil {
+			return err
+		}
+		if !due.Equal(c.OrdersDue) {
+			c.NewOrdersDue = due
+		}
+	}
+
+	return nil
+}
+
+// SyncCalendar reads the game's report extracts and proposes changes to the
+// active turn, the game turns, and the date that orders are due. If apply is
+// true, the changes are saved.
+//
+// The due time is the time of day (HH:MM) that orders are due. If it is empty,
+// the time of the current deadline is used.
+//
+// It returns ErrCalendarDisagreement, along with the calendar, if the reports
+// disagree and apply is true. It returns ErrDueTimeNotSet, along with the
+// calendar, if the due time is empty and the game has no deadline yet.
+// Nothing is saved in either case.
+func (s *Service) SyncCalendar(gameId domains.GameID, dueTime string, apply bool, quiet, verbose, debug bool) (*Calendar, error) {
+	ctx := s.db.Context()
+	q := s.db.Queries()
+
+	row, err := q.ReadGameCalendar(ctx, int64(gameId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domains.ErrNotFound
+		}
+		log.Printf("[games] SyncCalendar(%d) %v", gameId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	c := &Calendar{
+		GameID:     gameId,
+		Code:       row.Code,
+		Timezone:   row.Timezone,
+		ActiveTurn: row.ActiveTurn,
+		DueTime:    dueTime,
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%s: timezone %q: %w", c.Code, c.Timezone, err)
+	}
+	if row.OrdersDue != 0 {
+		c.OrdersDue = time.Unix(row.OrdersDue, 0).In(loc)
+	}
+	turnRows, err := q.ReadGameTurns(ctx, int64(gameId))
+	if err != nil {
+		log.Printf("[games] SyncCalendar(%d) %v", gameId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	for _, t := range turnRows {
+		c.Turns = append(c.Turns, CalendarTurn{Turn: t.Turn, Year: int(t.TurnYear), Month: int(t.TurnMonth), TurnNo: int(t.TurnNo)})
+	}
+
+	extracts, err := q.ReadGameReportExtracts(ctx, int64(gameId))
+	if err != nil {
+		log.Printf("[games] SyncCalendar(%d) %v", gameId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	var reports []*CalendarReport
+	for _, extract := range extracts {
+		contents, err := s.blobs.Get(ctx, q, extract.ContentsHash)
+		if err != nil {
+			log.Printf("[games] SyncCalendar(%d) %s: %v", gameId, extract.DocumentName, err)
+			return nil, errors.Join(domains.ErrDatabaseError, err)
+		}
+		r, err := ReadCalendarReport(extract.DocumentName, contents)
+		if err != nil {
+			// validation should have caught this when the report was stored
+			if verbose {
+				log.Printf("[games] SyncCalendar(%d) %s: %v", gameId, extract.DocumentName, err)
+			}
+			continue
+		} else if r == nil {
+			continue
+		}
+		r.DocumentID, r.ClanNo = domains.ID(extract.DocumentID), int(extract.Clan)
+		reports = append(reports, r)
+	}
+	if debug {
+		log.Printf("[games] SyncCalendar(%d) %d reports", gameId, len(reports))
+	}
+	if err := c.Propose(reports, time.Now()); errors.Is(err, ErrDueTimeNotSet) {
+		return c, err
+	} else if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.Code, err)
+	}
+
+	if !apply || !c.HasChanges() {
+		return c, nil
+	} else if len(c.Disagreements) != 0 {
+		return c, ErrCalendarDisagreement
+	}
+
+	tx, err := s.db.Stdlib().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	defer tx.Rollback() // rollback if we return early; harmless after commit
+	qtx := q.WithTx(tx)
+	now := time.Now().UTC().Unix()
+
+	for _, t := range c.NewTurns {
+		err = qtx.CreateGameTurn(ctx, sqlc.CreateGameTurnParams{
+			GameID:    int64(gameId),
+			Turn:      t.Turn,
+			TurnYear:  int64(t.Year),
+			TurnMonth: int64(t.Month),
+			TurnNo:    int64(t.TurnNo),
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			log.Printf("[games] SyncCalendar(%d) %s: %v", gameId, t.Turn, err)
+			return nil, errors.Join(domains.ErrDatabaseError, err)
+		}
+	}
+	params := sqlc.UpdateGameCalendarParams{
+		ActiveTurn: c.ActiveTurn,
+		OrdersDue:  row.OrdersDue,
+		UpdatedAt:  now,
+		GameID:     int64(gameId),
+	}
+	if c.NewActiveTurn != "" {
+		params.ActiveTurn = c.NewActiveTurn
+	}
+	if !c.NewOrdersDue.IsZero() {
+		params.OrdersDue = c.NewOrdersDue.UTC().Unix()
+	}
+	if err = qtx.UpdateGameCalendar(ctx, params); err != nil {
+		log.Printf("[games] SyncCalendar(%d) %v", gameId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	if verbose {
+		log.Printf("[games] SyncCalendar(%d) applied: active %q, orders due %v, %d turns", gameId, params.ActiveTurn, time.Unix(params.OrdersDue, 0).In(loc), len(c.NewTurns))
+	}
+
+	return c, nil
+}
+
+// ballot counts votes. Ties go to the value that was seen first.
+type ballot struct {
+	values []string
+	votes  map[string]int
+}
+
+func (b *ballot) add(value string) {
+	if b.votes == nil {
+		b.votes = map[string]int{}
+	}
+	if b.votes[value] == 0 {
+		b.values = append(b.values, value)
+	}
+	b.votes[value]++
+}
+
+func (b *ballot) winner() string {
+	var winner string
+	for _, value := range b.values {
+		if b.votes[value] > b.votes[winner] {
+			winner = value
+		}
+	}
+	return winner
+}
+
+func calendarTurn(d bistre.Date_t, turnNo int) CalendarTurn {
+	return CalendarTurn{
+		Turn:   fmt.Sprintf("%04d-%02d", d.Year, d.Month),
+		Year:   d.Year,
+		Month:  d.Month,
+		TurnNo: turnNo,
+	}
+}
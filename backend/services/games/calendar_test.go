@@ -0,0 +1,87 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package games_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/services/games"
+)
+
+func TestCalendarPropose(t *testing.T) {
+	loc, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skip(err)
+	}
+	var reports []*games.CalendarReport
+	for clanNo, line := range []string{
+		"Current Turn 900-01 (#1), Spring, FINE\tNext Turn 900-02 (#2), 5/12/2025",
+		"Current Turn 900-01 (#1), Spring, FINE\tNext Turn 900-02 (#2), 5/12/2025",
+		"Current Turn 900-01 (#1), Spring, FINE\tNext Turn 900-02 (#2), 6/12/2025",
+		"Current Turn 899-12 (#0), Winter, FINE\tNext Turn 900-01 (#1), 28/11/2025",
+	} {
+		r, err := games.ReadCalendarReport("report.txt", []byte("Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)\n"+line+"\n"))
+		if err != nil || r == nil {
+			t.Fatalf("%d: read: %v", clanNo, err)
+		}
+		r.ClanNo = clanNo + 1
+		reports = append(reports, r)
+	}
+
+	c := &games.Calendar{
+		Timezone:   "America/Chicago",
+		ActiveTurn: "0899-12",
+		OrdersDue:  time.Date(2025, 11, 28, 18, 30, 0, 0, loc),
+		Turns:      []games.CalendarTurn{{Turn: "0899-12", Year: 899, Month: 12, TurnNo: 0}},
+	}
+	now := time.Date(2025, 11, 30, 12, 0, 0, 0, loc)
+	if err := c.Propose(reports, now); err != nil {
+		t.Fatal(err)
+	}
+	if c.NewActiveTurn != "0900-01" {
+		t.Errorf("active turn: want %q, got %q", "0900-01", c.NewActiveTurn)
+	}
+	if want := time.Date(2025, 12, 5, 18, 30, 0, 0, loc); !c.NewOrdersDue.Equal(want) {
+		t.Errorf("orders due: want %v, got %v", want, c.NewOrdersDue)
+	}
+	var turns []string
+	for _, turn := range c.NewTurns {
+		turns = append(turns, turn.Turn)
+	}
+	if got := strings.Join(turns, ", "); got != "0900-01, 0900-02" {
+		t.Errorf("turns: want %q, got %q", "0900-01, 0900-02", got)
+	}
+	if len(c.Disagreements) != 1 {
+		t.Fatalf("disagreements: want 1, got %v", c.Disagreements)
+	} else if got, want := c.Disagreements[0].String(), "0003: report.txt: 0900-02: orders-due: got 2025-12-06, want 2025-12-05"; got != want {
+		t.Errorf("disagreement: want %q, got %q", want, got)
+	}
+
+	// the orders for 899-12 aren't due yet, so it stays the active turn
+	// even though there are reports for 900-01.
+	if err := c.Propose(reports, time.Date(2025, 11, 20, 12, 0, 0, 0, loc)); err != nil {
+		t.Fatal(err)
+	}
+	if c.NewActiveTurn != "" {
+		t.Errorf("before the deadline: active turn: want no change, got %q", c.NewActiveTurn)
+	}
+	if !c.NewOrdersDue.IsZero() {
+		t.Errorf("before the deadline: orders due: want no change, got %v", c.NewOrdersDue)
+	}
+
+	// without a deadline, the time of day must be given
+	c.OrdersDue = time.Time{}
+	if err := c.Propose(reports, now); !errors.Is(err, games.ErrDueTimeNotSet) {
+		t.Errorf("no due time: want %v, got %v", games.ErrDueTimeNotSet, err)
+	}
+	c.DueTime = "20:00"
+	if err := c.Propose(reports, now); err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 12, 5, 20, 0, 0, 0, loc); !c.NewOrdersDue.Equal(want) {
+		t.Errorf("due time: orders due: want %v, got %v", want, c.NewOrdersDue)
+	}
+}
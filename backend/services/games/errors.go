@@ -0,0 +1,14 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package games
+
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+const (
+	ErrCalendarDisagreement = Error("reports disagree on the calendar")
+	ErrDueTimeNotSet        = Error("time of day orders are due is not set")
+)
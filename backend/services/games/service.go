@@ -13,6 +13,7 @@ import (
 	"github.com/playbymail/ottoapp/backend/services/authn"
 	"github.com/playbymail/ottoapp/backend/services/authz"
 	"github.com/playbymail/ottoapp/backend/services/users"
+	"github.com/playbymail/ottoapp/backend/stores/blobs"
 	"github.com/playbymail/ottoapp/backend/stores/sqlite"
 	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
 )
@@ -22,9 +23,10 @@ type Service struct {
 	authnSvc *authn.Service
 	authzSvc *authz.Service
 	usersSvc *users.Service
+	blobs    *blobs.Blobs
 }
 
-func New(db *sqlite.DB, authnSvc *authn.Service, authzSvc *authz.Service, usersSvc *users.Service, quiet, verbose, debug bool) (*Service, error) {
+func New(db *sqlite.DB, authnSvc *authn.Service, authzSvc *authz.Service, usersSvc *users.Service, blobStore *blobs.Blobs, quiet, verbose, debug bool) (*Service, error) {
 	if authzSvc == nil {
 		authzSvc = authz.New(db)
 	}
@@ -38,7 +40,10 @@ func New(db *sqlite.DB, authnSvc *authn.Service, authzSvc *authz.Service, usersS
 		}
 		usersSvc = users.New(db, authnSvc, authzSvc, ianaSvc)
 	}
-	return &Service{db: db, authnSvc: authnSvc, authzSvc: authzSvc, usersSvc: usersSvc}, nil
+	if blobStore == nil {
+		blobStore = blobs.New(db)
+	}
+	return &Service{db: db, authnSvc: authnSvc, authzSvc: authzSvc, usersSvc: usersSvc, blobs: blobStore}, nil
 }
 
 func (s *Service) CreateGame() (domains.ID, error) {
@@ -0,0 +1,40 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package cst
+
+import (
+	"fmt"
+
+	"github.com/playbymail/ottoapp/backend/services/reports/lexers"
+)
+
+// Error is a parse error. It records the position of the token where the
+// error was found so that tools (like the language server) can point at it.
+type Error struct {
+	LineNo, ColNo int
+	Message       string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// errorf returns an error at the position of the current token.
+func (p *parser) errorf(format string, args ...any) error {
+	return p.errorAt(p.peek(), format, args...)
+}
+
+// errorAt returns an error at the position of the token.
+// If the token is nil (we're at the end of the input), the error
+// is placed just after the last token.
+func (p *parser) errorAt(tok *lexers.Token, format string, args ...any) error {
+	e := &Error{Message: fmt.Sprintf(format, args...)}
+	if tok != nil {
+		e.LineNo, e.ColNo = tok.Position()
+	} else if len(p.tokens) != 0 {
+		last := p.tokens[len(p.tokens)-1]
+		e.LineNo, e.ColNo = last.Position()
+		e.ColNo += last.Length()
+	}
+	return e
+}
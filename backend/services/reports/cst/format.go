@@ -0,0 +1,250 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package cst
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/playbymail/ottoapp/backend/services/reports/lexers"
+)
+
+var (
+	ErrReportHasErrors   = errors.New("report has parse errors")
+	ErrFormatChangedTree = errors.New("formatted report does not parse to the same tree")
+
+	reParsedLine = regexp.MustCompile(`^\s*((Courier|Element|Fleet|Garrison|Tribe)\s+\d{4}([cefg]\d)?\s*,|Current\s+Turn\s|Tribe\s+Goes\s+to\s|Tribe\s+Movement\s*:)`)
+)
+
+// IsParsedLine returns true if the line is one that the parser knows:
+// unit, turn, and movement lines. Report extracts have other lines
+// (scouts, status, and so on) that must be removed before parsing.
+func IsParsedLine(line []byte) bool {
+	return reParsedLine.Match(line)
+}
+
+// Format writes the canonical text for the tree. The tokens on each line are
+// separated by single spaces, and directions and terrain use the keyword spelling.
+// It returns ErrReportHasErrors if the tree has parse errors because we
+// can't tell what the text should have been.
+func Format(w io.Writer, t *TurnReportNode) error {
+	if len(t.Errors()) != 0 {
+		return ErrReportHasErrors
+	}
+	for _, line := range formatLines(t) {
+		if _, err := io.WriteString(w, line.text+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatReport formats the unit, turn, and movement lines in a report
+// extract. Other lines are kept, with trailing white space removed.
+//
+// Directions and terrain codes on movement lines are matched without
+// regard to case, so "n-ch" is formatted as "N-CH".
+//
+// The formatted lines are parsed again and compared to the original tree.
+// If they are not Equal, the input is returned unchanged with
+// ErrFormatChangedTree.
+func FormatReport(src []byte) ([]byte, error) {
+	lines := bytes.Split(src, []byte{'\n'})
+
+	// build the text for the parser from the lines it knows
+	parsed := &bytes.Buffer{}
+	var lineMap []int // line in the parsed text (0-based) to index in lines
+	for n, line := range lines {
+		if IsParsedLine(line) {
+			parsed.Write(line)
+			parsed.WriteByte('\n')
+			lineMap = append(lineMap, n)
+		}
+	}
+	tokens := lexers.Scan(parsed.Bytes())
+	foldMovement(tokens)
+	tree := Parse(tokens)
+	if errs := tree.Errors(); len(errs) != 0 {
+		var pe *Error
+		if errors.As(errs[0], &pe) && 0 < pe.LineNo && pe.LineNo <= len(lineMap) {
+			return src, fmt.Errorf("%d: %w: %w", lineMap[pe.LineNo-1]+1, ErrReportHasErrors, errs[0])
+		}
+		return src, errors.Join(ErrReportHasErrors, errs[0])
+	}
+
+	formatted := formatLines(tree)
+	check := &bytes.Buffer{}
+	for _, line := range formatted {
+		check.WriteString(line.text + "\n")
+	}
+	if reparsed := Parse(lexers.Scan(check.Bytes())); len(reparsed.Errors()) != 0 || !Equal(tree, reparsed) {
+		return src, ErrFormatChangedTree
+	}
+
+	for n, line := range lines {
+		lines[n] = bytes.TrimRight(line, " \t\r")
+	}
+	for _, line := range formatted {
+		lines[lineMap[line.lineNo-1]] = []byte(line.text)
+	}
+	return bytes.Join(lines, []byte{'\n'}), nil
+}
+
+// Equal returns true if the trees have the same content.
+// Positions, white space, and punctuation are ignored.
+func Equal(a, b *TurnReportNode) bool {
+	return slices.Equal(content(a), content(b))
+}
+
+// formattedLine is the canonical text for the line in the source.
+type formattedLine struct {
+	lineNo int
+	text   string
+}
+
+func formatLines(t *TurnReportNode) []formattedLine {
+	var lines []formattedLine
+	for _, s := range t.Sections {
+		if n := s.UnitLine; n != nil {
+			lines = append(lines, formattedLine{lineNo: n.LineNo, text: fmt.Sprintf("%s %s, %s, Current Hex = %s, (Previous Hex = %s)",
+				text(n.Keyword), text(n.UnitID), noteText(n.Note), coordsText(n.CurrentHex), coordsText(n.PreviousHex))})
+		}
+		if n := s.TurnLine; n != nil {
+			line := fmt.Sprintf("Current Turn %s %s, %s, %s", text(n.TurnYearMonth1), turnNumberText(n.TurnNumber1), text(n.Season), text(n.Weather))
+			if n.Next != nil {
+				line += fmt.Sprintf(" Next Turn %s %s, %s", text(n.TurnYearMonth2), turnNumberText(n.TurnNumber2), reportDateText(n.ReportDate))
+			}
+			lines = append(lines, formattedLine{lineNo: n.LineNo, text: line})
+		}
+		switch n := s.UnitMovementLine.(type) {
+		case *UnitGoesToLineNode:
+			lines = append(lines, formattedLine{lineNo: n.LineNo, text: fmt.Sprintf("Tribe Goes to %s", coordsText(n.Coords))})
+		case *LandMovementLineNode:
+			line := "Tribe Movement: Move"
+			if n.LandMovement != nil {
+				for i, step := range n.LandMovement.Steps {
+					if i != 0 {
+						line += " \\"
+					} else if !step.IsEmpty() {
+						line += " "
+					}
+					line += stepText(step)
+				}
+			}
+			lines = append(lines, formattedLine{lineNo: n.LineNo, text: line})
+		}
+	}
+	return lines
+}
+
+// content returns the values in the tree, one per node.
+func content(t *TurnReportNode) []string {
+	var values []string
+	add := func(format string, args ...any) {
+		values = append(values, fmt.Sprintf(format, args...))
+	}
+	for _, s := range t.Sections {
+		add("section")
+		if n := s.UnitLine; n != nil {
+			add("unit %q %q %q", text(n.Keyword), text(n.UnitID), noteText(n.Note))
+			add("current %s", coordsText(n.CurrentHex))
+			add("previous %s", coordsText(n.PreviousHex))
+		}
+		if n := s.TurnLine; n != nil {
+			add("turn %q %q %q %q", text(n.TurnYearMonth1), turnNumberText(n.TurnNumber1), text(n.Season), text(n.Weather))
+			if n.Next != nil {
+				add("next %q %q %q", text(n.TurnYearMonth2), turnNumberText(n.TurnNumber2), reportDateText(n.ReportDate))
+			}
+		}
+		switch n := s.UnitMovementLine.(type) {
+		case *UnitGoesToLineNode:
+			add("goes to %s", coordsText(n.Coords))
+		case *LandMovementLineNode:
+			add("movement")
+			if n.LandMovement != nil {
+				for _, step := range n.LandMovement.Steps {
+					add("step %q %q %t", text(step.Direction), text(step.Terrain), step.Comma != nil)
+				}
+			}
+		}
+	}
+	return values
+}
+
+// foldMovement changes directions and terrain codes on movement lines
+// to the keyword spelling. The lexer is case-sensitive, so "n-ch" would
+// otherwise be an error.
+func foldMovement(tokens []*lexers.Token) {
+	inMovement := false
+	for i, tok := range tokens {
+		switch {
+		case tok.Kind == lexers.EOL:
+			inMovement = false
+		case tok.Kind == lexers.Movement:
+			inMovement = true
+		case inMovement && len(tok.Value) == 1:
+			value, kind, ok := lexers.Fold(tok.Value[0].Value)
+			if !ok {
+				continue
+			}
+			nextIsDash := i+1 < len(tokens) && tokens[i+1].Kind == lexers.Dash
+			prevIsDash := i > 0 && tokens[i-1].Kind == lexers.Dash
+			if (kind == lexers.Direction && nextIsDash) || (kind == lexers.TerrainCode && prevIsDash) {
+				tok.Kind, tok.Value[0].Kind, tok.Value[0].Value = kind, kind, value
+			}
+		}
+	}
+}
+
+func text(tok *lexers.Token) string {
+	return string(tok.Bytes())
+}
+
+// noteText collapses the white space in the note.
+func noteText(tok *lexers.Token) string {
+	return strings.Join(strings.Fields(text(tok)), " ")
+}
+
+func coordsText(n CoordsNode) string {
+	switch v := n.(type) {
+	case *GridCoordsNode:
+		if v != nil {
+			return text(v.Grid) + " " + text(v.Number)
+		}
+	case *ObscuredCoordsNode:
+		return text(v.Grid) + " " + text(v.Number)
+	case *NACoordsNode:
+		return text(v.Text)
+	}
+	return ""
+}
+
+func turnNumberText(n *TurnNumberNode) string {
+	if n == nil {
+		return ""
+	}
+	return "(#" + text(n.Number) + ")"
+}
+
+func reportDateText(n *ReportDateNode) string {
+	if n == nil {
+		return ""
+	}
+	return text(n.Day) + "/" + text(n.Month) + "/" + text(n.Year)
+}
+
+func stepText(n *LandStepNode) string {
+	var s string
+	if n.Direction != nil {
+		s = text(n.Direction) + "-" + text(n.Terrain)
+	}
+	if n.Comma != nil {
+		s += ","
+	}
+	return s
+}
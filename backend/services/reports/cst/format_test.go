@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package cst_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/services/reports/cst"
+	"github.com/playbymail/ottoapp/backend/services/reports/lexers"
+)
+
+func TestFormatReport(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+		err   error
+	}{
+		{
+			name:  "canonical",
+			input: "Tribe 0987, , Current Hex = QQ 1509, (Previous Hex = QQ 1410)\nCurrent Turn 899-12 (#0), Winter, FINE Next Turn 900-01 (#1), 28/11/2025\nTribe Movement: Move N-PR, \\NE-GH,\n",
+			want:  "Tribe 0987, , Current Hex = QQ 1509, (Previous Hex = QQ 1410)\nCurrent Turn 899-12 (#0), Winter, FINE Next Turn 900-01 (#1), 28/11/2025\nTribe Movement: Move N-PR, \\NE-GH,\n",
+		},
+		{
+			name:  "spacing",
+			input: "  Tribe  0987 ,,Current Hex=QQ 1509 ,(Previous Hex = N/A )   \nCurrent Turn 899-12 ( # 0 ) ,Winter,FINE\t\tNext Turn 900-01 (#1), 28 / 11 / 2025\nTribe Goes to   QQ 1612\n",
+			want:  "Tribe 0987, , Current Hex = QQ 1509, (Previous Hex = N/A)\nCurrent Turn 899-12 (#0), Winter, FINE Next Turn 900-01 (#1), 28/11/2025\nTribe Goes to QQ 1612\n",
+		},
+		{
+			name:  "note",
+			input: "Element 0987e1,   Scouting   party , Current Hex = ## 1407, (Previous Hex = FF 1410)\n",
+			want:  "Element 0987e1, Scouting party, Current Hex = ## 1407, (Previous Hex = FF 1410)\n",
+		},
+		{
+			name:  "movement spellings",
+			input: "Tribe 0987, , Current Hex = QQ 1509, (Previous Hex = QQ 1410)\nCurrent Turn 900-01 (#1), Spring, FINE\nTribe Movement: Move   \\ n-ch ,\\se-hsm,  \\\n",
+			want:  "Tribe 0987, , Current Hex = QQ 1509, (Previous Hex = QQ 1410)\nCurrent Turn 900-01 (#1), Spring, FINE\nTribe Movement: Move \\N-CH, \\SE-Hsm, \\\n",
+		},
+		{
+			name:  "other lines are kept",
+			input: "Tribe 0987, , Current Hex = QQ 1509, (Previous Hex = QQ 1410)   \r\nCurrent Turn 900-01 (#1), Spring, FINE\nHumans 1000  \n0987 Status: PRAIRIE,  0987\n",
+			want:  "Tribe 0987, , Current Hex = QQ 1509, (Previous Hex = QQ 1410)\nCurrent Turn 900-01 (#1), Spring, FINE\nHumans 1000\n0987 Status: PRAIRIE,  0987\n",
+		},
+		{
+			name:  "parse errors",
+			input: "Humans 1000\nTribe 0987, , Current Hex = QQ 1509 (Previous Hex = QQ 1410)\n",
+			want:  "Humans 1000\nTribe 0987, , Current Hex = QQ 1509 (Previous Hex = QQ 1410)\n",
+			err:   cst.ErrReportHasErrors,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := cst.FormatReport([]byte(tc.input))
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("error: want %v, got %v", tc.err, err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("want %q\n got %q", tc.want, got)
+			}
+			if err != nil {
+				return
+			}
+			// formatting is idempotent
+			if again, err := cst.FormatReport(got); err != nil || !bytes.Equal(again, got) {
+				t.Errorf("format again: %v: got %q", err, again)
+			}
+		})
+	}
+}
+
+func TestEqual(t *testing.T) {
+	a := cst.Parse(lexers.Scan([]byte("Tribe 0987, , Current Hex = QQ 1509, (Previous Hex = QQ 1410)\nTribe Movement: Move N-PR,\n")))
+	b := cst.Parse(lexers.Scan([]byte("Tribe 0987 , ,Current Hex = QQ 1509, (Previous Hex = QQ 1410)\nTribe Movement:Move  N-PR,\n")))
+	c := cst.Parse(lexers.Scan([]byte("Tribe 0987, , Current Hex = QQ 1509, (Previous Hex = QQ 1410)\nTribe Movement: Move N-PR, \\\n")))
+	if !cst.Equal(a, b) {
+		t.Errorf("spacing: want equal")
+	}
+	if cst.Equal(a, c) {
+		t.Errorf("extra step: want not equal")
+	}
+}
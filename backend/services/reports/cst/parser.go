@@ -103,7 +103,7 @@ func (p *parser) expect(kind lexers.Kind) (*lexers.Token, error) {
 		return tok, nil
 	}
 	got := p.peekKind()
-	return nil, fmt.Errorf("expected %s, got %s", kind, got)
+	return nil, p.errorf("expected %s, got %s", kind, got)
 }
 
 // isAtEnd returns true if all tokens have been consumed.
@@ -167,7 +167,7 @@ func (p *parser) parseTurnReport() *TurnReportNode {
 			skipped := p.syncToUnitKeyword()
 			if len(skipped) > 0 {
 				node.tokens = append(node.tokens, skipped...)
-				node.errors = append(node.errors, fmt.Errorf("unexpected tokens before unit section"))
+				node.errors = append(node.errors, p.errorAt(skipped[0], "unexpected tokens before unit section"))
 			}
 		}
 	}
@@ -233,7 +233,7 @@ func (p *parser) parseUnitLine() *UnitLineNode {
 		node.Keyword = tok
 		node.tokens = append(node.tokens, tok)
 	} else {
-		node.errors = append(node.errors, fmt.Errorf("expected unit keyword"))
+		node.errors = append(node.errors, p.errorf("expected unit keyword"))
 		skipped := p.syncToNextLine()
 		node.tokens = append(node.tokens, skipped...)
 		return node
@@ -244,7 +244,7 @@ func (p *parser) parseUnitLine() *UnitLineNode {
 		node.UnitID = tok
 		node.tokens = append(node.tokens, tok)
 	} else {
-		node.errors = append(node.errors, fmt.Errorf("expected unit id"))
+		node.errors = append(node.errors, p.errorf("expected unit id"))
 		skipped := p.syncToNextLine()
 		node.tokens = append(node.tokens, skipped...)
 		return node
@@ -696,13 +696,14 @@ func (p *parser) parseUnitGoesToLine() *UnitGoesToLineNode {
 	}
 
 	// grid_coords
+	at := p.peek()
 	coords := p.parseCoords()
 	node.tokens = append(node.tokens, coords.Tokens()...)
 	node.errors = append(node.errors, coords.Errors()...)
 	if gc, ok := coords.(*GridCoordsNode); ok {
 		node.Coords = gc
 	} else {
-		node.errors = append(node.errors, fmt.Errorf("expected grid coordinates"))
+		node.errors = append(node.errors, p.errorAt(at, "expected grid coordinates"))
 	}
 
 	// EOL
@@ -918,7 +919,7 @@ func (p *parser) parseCoords() CoordsNode {
 		}
 		node.LineNo, node.ColNo = p.peek().Position()
 
-		node.errors = append(node.errors, fmt.Errorf("%s", node.Message))
+		node.errors = append(node.errors, p.errorf("%s", node.Message))
 		return node
 	}
 }
@@ -5,6 +5,7 @@ package docx
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/playbymail/ottoapp/backend/services/reports/office"
 )
@@ -13,30 +14,58 @@ type Docx struct {
 	Text []byte
 }
 
+// ParseDocx extracts the text from a .docx turn report.
 func ParseDocx(r *bytes.Reader, trimLeading, trimTrailing bool) (*Docx, error) {
 	doc, err := office.Parse(r)
 	if err != nil || doc == nil {
 		return nil, err
 	}
-	if trimLeading || trimTrailing {
-		const asciiSpace = " \t\n\v\f\r"
-		lines := bytes.Split(doc.Text, []byte{'\n'})
-		if trimLeading && trimTrailing {
-			for i, line := range lines {
-				lines[i] = bytes.TrimSpace(line)
-			}
-		} else if trimLeading {
-			for i, line := range lines {
-				lines[i] = bytes.TrimLeft(line, asciiSpace)
-			}
-		} else {
-			for i, line := range lines {
-				lines[i] = bytes.TrimRight(line, asciiSpace)
-			}
+	return &Docx{Text: trimLines(doc.Text, trimLeading, trimTrailing)}, nil
+}
+
+// ParseReport extracts the text from a turn report. The format is detected
+// from the contents, not the file name, so DOCX, ODT, and plain text
+// reports are all accepted.
+func ParseReport(data []byte, trimLeading, trimTrailing bool) (*Docx, error) {
+	var doc *office.WordDocument
+	var err error
+	switch dt := office.DetectDocType(data); dt {
+	case office.Docx:
+		doc, err = office.ParseWithOptions(bytes.NewReader(data), office.Options{})
+	case office.Odt:
+		doc, err = office.ParseODT(bytes.NewReader(data), office.Options{})
+	case office.Text:
+		doc, err = office.Extract(data, office.Options{})
+	default:
+		return nil, fmt.Errorf("%w: %s", office.ErrUnsupportedFormat, dt)
+	}
+	if err != nil || doc == nil {
+		return nil, err
+	}
+	return &Docx{Text: trimLines(doc.Text, trimLeading, trimTrailing)}, nil
+}
+
+// trimLines trims the spaces from the start or end of each line.
+func trimLines(text []byte, trimLeading, trimTrailing bool) []byte {
+	if !trimLeading && !trimTrailing {
+		return text
+	}
+	const asciiSpace = " \t\n\v\f\r"
+	lines := bytes.Split(text, []byte{'\n'})
+	if trimLeading && trimTrailing {
+		for i, line := range lines {
+			lines[i] = bytes.TrimSpace(line)
+		}
+	} else if trimLeading {
+		for i, line := range lines {
+			lines[i] = bytes.TrimLeft(line, asciiSpace)
+		}
+	} else {
+		for i, line := range lines {
+			lines[i] = bytes.TrimRight(line, asciiSpace)
 		}
-		doc.Text = bytes.Join(lines, []byte{'\n'})
 	}
-	return &Docx{Text: doc.Text}, nil
+	return bytes.Join(lines, []byte{'\n'})
 }
 
 // ParseClanHeading returns the first heading in the report or an error.
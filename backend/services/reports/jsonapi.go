@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package reports
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReparseView is the JSON:API view for a reparse run.
+type ReparseView struct {
+	ID        string    `jsonapi:"primary,reparse"` // singular when sending a payload
+	DryRun    bool      `jsonapi:"attr,dry-run"`
+	Checked   int       `jsonapi:"attr,checked"`
+	Reparsed  int       `jsonapi:"attr,reparsed"`
+	Changed   int       `jsonapi:"attr,changed"`
+	Failed    int       `jsonapi:"attr,failed"`
+	StaleMaps []string  `jsonapi:"attr,stale-maps"` // "{game}.{clan}: {turns}" for clans whose maps are out of date
+	Errors    []string  `jsonapi:"attr,errors"`     // "{document}: {error}" or "{game}.{clan}: {error}"
+	StartedAt time.Time `jsonapi:"attr,started-at,iso8601"`
+	ElapsedMs int64     `jsonapi:"attr,elapsed-ms"`
+}
+
+// NewReparseView returns the view for a reparse run.
+func NewReparseView(summary *ReparseSummary, dryRun bool, startedAt time.Time) *ReparseView {
+	view := &ReparseView{
+		ID:        fmt.Sprintf("%d", startedAt.Unix()),
+		DryRun:    dryRun,
+		Checked:   summary.Checked,
+		Reparsed:  summary.Reparsed,
+		Changed:   summary.Changed,
+		Failed:    summary.Failed,
+		StaleMaps: []string{},
+		Errors:    []string{},
+		StartedAt: startedAt,
+		ElapsedMs: summary.Elapsed.Milliseconds(),
+	}
+	for _, sm := range summary.StaleMaps {
+		if sm.Error != "" {
+			view.Errors = append(view.Errors, fmt.Sprintf("%s.%04d: %s", sm.Game, sm.ClanNo, sm.Error))
+			continue
+		}
+		view.StaleMaps = append(view.StaleMaps, fmt.Sprintf("%s.%04d: %s", sm.Game, sm.ClanNo, strings.Join(sm.Turns, ", ")))
+	}
+	for _, r := range summary.Results {
+		if r.Error != "" {
+			view.Errors = append(view.Errors, fmt.Sprintf("%s: %s", r.DocumentName, r.Error))
+		}
+	}
+	return view
+}
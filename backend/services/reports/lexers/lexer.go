@@ -5,7 +5,9 @@
 package lexers
 
 import (
+	"bytes"
 	"regexp"
+	"strings"
 )
 
 // Scan returns all the tokens in the input buffer.
@@ -135,6 +137,17 @@ func Scan(input []byte) []*Token {
 	return tokens
 }
 
+// Fold returns the keyword spelling and kind for text, ignoring case.
+// For example, "hsm" returns "Hsm" and TerrainCode.
+// It returns false if text is not a keyword.
+func Fold(text []byte) ([]byte, Kind, bool) {
+	kw, ok := foldedKeywords[string(bytes.ToUpper(text))]
+	if !ok {
+		return nil, Text, false
+	}
+	return []byte(kw), keywords[kw], true
+}
+
 func bdup(src []byte) []byte {
 	dst := make([]byte, len(src))
 	copy(dst, src)
@@ -146,14 +159,18 @@ func init() {
 		isTrivia[ch] = true
 		isDelimiter[ch] = true
 	}
+	for kw := range keywords {
+		foldedKeywords[strings.ToUpper(kw)] = kw
+	}
 	for _, ch := range []byte{0, '\n', '\'', '"', '.', ',', '(', ')', '#', '+', '-', '*', '/', '=', '\\', '$', ':'} {
 		isDelimiter[ch] = true
 	}
 }
 
 var (
-	isDelimiter = [256]bool{}
-	isTrivia    = [256]bool{}
+	foldedKeywords = map[string]string{} // upper-case spelling to keyword
+	isDelimiter    = [256]bool{}
+	isTrivia       = [256]bool{}
 
 	keywords = map[string]Kind{
 		// unit line keywords
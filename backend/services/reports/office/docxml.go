@@ -18,7 +18,7 @@ func DocXML(r *bytes.Reader) ([]byte, error) {
 	}
 	for _, file := range zr.File {
 		if file.Name == "word/document.xml" {
-			rc, err := file.Open()
+			rc, err := DefaultLimits.open(file)
 			if err != nil {
 				return nil, fmt.Errorf("open document.xml: %w", err)
 			}
@@ -26,7 +26,7 @@ func DocXML(r *bytes.Reader) ([]byte, error) {
 			w := &bytes.Buffer{}
 			_, err = io.Copy(w, rc)
 			if err != nil {
-				panic(err)
+				return nil, fmt.Errorf("read document.xml: %w", err)
 			}
 			return w.Bytes(), nil
 		}
@@ -43,7 +43,7 @@ func DocXMLPath(path string) ([]byte, error) {
 	defer zr.Close()
 	for _, file := range zr.File {
 		if file.Name == "word/document.xml" {
-			rc, err := file.Open()
+			rc, err := DefaultLimits.open(file)
 			if err != nil {
 				return nil, fmt.Errorf("open document.xml: %w", err)
 			}
@@ -51,7 +51,7 @@ func DocXMLPath(path string) ([]byte, error) {
 			w := &bytes.Buffer{}
 			_, err = io.Copy(w, rc)
 			if err != nil {
-				panic(err)
+				return nil, fmt.Errorf("read document.xml: %w", err)
 			}
 			return w.Bytes(), nil
 		}
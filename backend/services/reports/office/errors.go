@@ -13,4 +13,15 @@ const (
 	ErrNotAWordDocument        = Error("not a word document")
 	ErrorUncompressFailed      = Error("uncompress failed")
 	ErrWordXmlDocumentNotFound = Error("word/document.xml not found")
+	ErrNotAnOpenDocument       = Error("not an opendocument text file")
+	ErrOdtContentNotFound      = Error("content.xml not found")
+	ErrUnsupportedFormat       = Error("unsupported document format")
+)
+
+// errors for documents that go over the limits
+const (
+	ErrTooManyEntries   = Error("too many entries in archive")
+	ErrDocumentTooLarge = Error("document is too large")
+	ErrNestingTooDeep   = Error("xml nesting is too deep")
+	ErrTextTooLong      = Error("text is too long")
 )
@@ -0,0 +1,49 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package office
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// Extract returns the text of a turn report. The format is detected from
+// the contents (see DetectDocType), not from the file name, so a report
+// saved from LibreOffice or pasted into a text file is read the same way
+// as one from Word.
+func Extract(data []byte, opts Options) (*WordDocument, error) {
+	switch dt := DetectDocType(data); dt {
+	case Docx:
+		return ParseWithOptions(bytes.NewReader(data), opts)
+	case Odt:
+		return ParseODT(bytes.NewReader(data), opts)
+	case Text:
+		return parseText(data, opts)
+	case Doc:
+		return nil, fmt.Errorf("%w: %s: save the report as .docx", ErrUnsupportedFormat, dt)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// parseText normalizes a plain text report. Line endings are converted
+// to line-feeds and the last line is terminated.
+func parseText(data []byte, opts Options) (*WordDocument, error) {
+	limits := opts.Limits.withDefaults()
+	if int64(len(data)) > limits.MaxDocumentBytes {
+		return nil, errors.Join(ErrBadInput, fmt.Errorf("%w: %d bytes, limit is %d", ErrDocumentTooLarge, len(data), limits.MaxDocumentBytes))
+	}
+	doc := &WordDocument{Report: &Report{}}
+	data = bytes.ReplaceAll(data, []byte{'\r', '\n'}, []byte{'\n'})
+	data = bytes.ReplaceAll(data, []byte{'\r'}, []byte{'\n'})
+	text, n := normalize(data)
+	doc.Report.Normalized = n
+	if len(text) != 0 && text[len(text)-1] != '\n' {
+		text = append(text, '\n')
+	}
+	if len(text) > limits.MaxTextBytes {
+		return nil, errors.Join(ErrBadInput, fmt.Errorf("%w: limit is %d", ErrTextTooLong, limits.MaxTextBytes))
+	}
+	doc.Text = text
+	return doc, nil
+}
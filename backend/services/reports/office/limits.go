@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package office
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+)
+
+// Limits protect the server from hostile uploads (zip bombs, deeply
+// nested XML, and so on). A zero value means use the default.
+type Limits struct {
+	MaxEntries       int   // files in the archive
+	MaxDocumentBytes int64 // uncompressed size of each part that is read
+	MaxDepth         int   // nesting of XML elements
+	MaxTextBytes     int   // length of the extracted text
+}
+
+// DefaultLimits are well above the size of any real turn report.
+var DefaultLimits = Limits{
+	MaxEntries:       1_000,
+	MaxDocumentBytes: 32 << 20,
+	MaxDepth:         256,
+	MaxTextBytes:     4 << 20,
+}
+
+// withDefaults returns the limits with the zero values replaced by the defaults.
+func (l Limits) withDefaults() Limits {
+	if l.MaxEntries <= 0 {
+		l.MaxEntries = DefaultLimits.MaxEntries
+	}
+	if l.MaxDocumentBytes <= 0 {
+		l.MaxDocumentBytes = DefaultLimits.MaxDocumentBytes
+	}
+	if l.MaxDepth <= 0 {
+		l.MaxDepth = DefaultLimits.MaxDepth
+	}
+	if l.MaxTextBytes <= 0 {
+		l.MaxTextBytes = DefaultLimits.MaxTextBytes
+	}
+	return l
+}
+
+// checkEntries returns an error if the archive has too many files.
+func (l Limits) checkEntries(zr *zip.Reader) error {
+	if len(zr.File) > l.MaxEntries {
+		return fmt.Errorf("%w: %d entries, limit is %d", ErrTooManyEntries, len(zr.File), l.MaxEntries)
+	}
+	return nil
+}
+
+// open opens a part of the archive. The size in the header is checked
+// first, but it can't be trusted, so the reader also stops with an
+// error as soon as it has returned more than the limit.
+func (l Limits) open(file *zip.File) (io.ReadCloser, error) {
+	if file.UncompressedSize64 > uint64(l.MaxDocumentBytes) {
+		return nil, fmt.Errorf("%w: %s: %d bytes, limit is %d", ErrDocumentTooLarge, file.Name, file.UncompressedSize64, l.MaxDocumentBytes)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	return &limitedReader{rc: rc, name: file.Name, left: l.MaxDocumentBytes}, nil
+}
+
+type limitedReader struct {
+	rc   io.ReadCloser
+	name string
+	left int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.left < 0 {
+		return 0, fmt.Errorf("%w: %s", ErrDocumentTooLarge, r.name)
+	}
+	// read one byte past the limit so that we can tell if there is more
+	if int64(len(p)) > r.left+1 {
+		p = p[:r.left+1]
+	}
+	n, err := r.rc.Read(p)
+	r.left -= int64(n)
+	if r.left < 0 {
+		return 0, fmt.Errorf("%w: %s", ErrDocumentTooLarge, r.name)
+	}
+	return n, err
+}
+
+func (r *limitedReader) Close() error {
+	return r.rc.Close()
+}
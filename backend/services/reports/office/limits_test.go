@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package office_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/services/reports/office"
+)
+
+func TestLimits(t *testing.T) {
+	paragraph := func(text string) string {
+		return wordHead + `<w:p><w:r><w:t>` + text + `</w:t></w:r></w:p>` + wordTail
+	}
+	for _, tc := range []struct {
+		id     string
+		parts  map[string]string
+		limits office.Limits
+		want   error
+	}{
+		{id: "entries",
+			parts:  map[string]string{"word/document.xml": paragraph("ok"), "a.xml": "", "b.xml": ""},
+			limits: office.Limits{MaxEntries: 2},
+			want:   office.ErrTooManyEntries},
+		{id: "zip bomb",
+			parts:  map[string]string{"word/document.xml": paragraph(strings.Repeat("a", 2<<20))},
+			limits: office.Limits{MaxDocumentBytes: 1 << 20},
+			want:   office.ErrDocumentTooLarge},
+		{id: "depth",
+			parts:  map[string]string{"word/document.xml": wordHead + strings.Repeat("<w:r>", 300) + strings.Repeat("</w:r>", 300) + wordTail},
+			limits: office.Limits{},
+			want:   office.ErrNestingTooDeep},
+		{id: "text",
+			parts:  map[string]string{"word/document.xml": paragraph(strings.Repeat("a", 200))},
+			limits: office.Limits{MaxTextBytes: 100},
+			want:   office.ErrTextTooLong},
+		{id: "ok",
+			parts:  map[string]string{"word/document.xml": paragraph(strings.Repeat("a", 200))},
+			limits: office.Limits{MaxEntries: 1, MaxDocumentBytes: 1 << 10, MaxDepth: 5, MaxTextBytes: 201},
+			want:   nil},
+	} {
+		_, err := office.ParseWithOptions(docx(t, tc.parts), office.Options{Limits: tc.limits})
+		if tc.want == nil {
+			if err != nil {
+				t.Errorf("%s: want nil, got %v", tc.id, err)
+			}
+		} else if !errors.Is(err, tc.want) {
+			t.Errorf("%s: want %v, got %v", tc.id, tc.want, err)
+		}
+	}
+}
+
+// FuzzParse feeds arbitrary bytes to the extractor as an archive.
+func FuzzParse(f *testing.F) {
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	w, _ := zw.Create("word/document.xml")
+	_, _ = w.Write([]byte(wordHead + `<w:p><w:r><w:t>Tribe 0987</w:t></w:r></w:p>` + wordTail))
+	_ = zw.Close()
+	f.Add(buf.Bytes())
+	f.Add([]byte("PK\x03\x04"))
+	f.Add([]byte{})
+
+	limits := office.Limits{MaxEntries: 10, MaxDocumentBytes: 1 << 16, MaxDepth: 32, MaxTextBytes: 1 << 12}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		doc, err := office.ParseWithOptions(bytes.NewReader(data), office.Options{HeadersFooters: true, Limits: limits})
+		if err == nil && len(doc.Text) > limits.MaxTextBytes {
+			t.Errorf("text: %d bytes, limit is %d", len(doc.Text), limits.MaxTextBytes)
+		}
+	})
+}
+
+// FuzzParseWordXML feeds arbitrary XML to the extractor as word/document.xml.
+func FuzzParseWordXML(f *testing.F) {
+	f.Add(wordHead + `<w:p><w:r><w:t>Tribe 0987</w:t><w:tab/><w:br/></w:r></w:p>` + wordTail)
+	f.Add(wordHead + `<w:tbl><w:tr><w:tc><w:p><w:r><w:t>a</w:t></w:r></w:p></w:tc></w:tr></w:tbl>` + wordTail)
+	f.Add(wordHead + `<w:p><w:r><w:txbxContent><w:p><w:r><w:t>box</w:t></w:r></w:p></w:txbxContent></w:r></w:p>` + wordTail)
+	f.Add(`<w:p><w:tbl><w:txbxContent></w:p>`)
+	f.Add("“quoted” ")
+
+	limits := office.Limits{MaxDepth: 32, MaxTextBytes: 1 << 12}
+	f.Fuzz(func(t *testing.T, xml string) {
+		doc, err := office.ParseWithOptions(docx(t, map[string]string{"word/document.xml": xml}), office.Options{Limits: limits})
+		if err == nil && len(doc.Text) > limits.MaxTextBytes {
+			t.Errorf("text: %d bytes, limit is %d", len(doc.Text), limits.MaxTextBytes)
+		}
+	})
+}
@@ -2,7 +2,10 @@
 
 package office
 
-import "bytes"
+import (
+	"bytes"
+	"unicode/utf8"
+)
 
 // DocType represents the type of Word document.
 type DocType int
@@ -11,20 +14,60 @@ const (
 	Unknown DocType = iota
 	Doc             // Word 97–2003 Documents
 	Docx            // Word 2007 and Later Documents
+	Odt             // OpenDocument Text (LibreOffice)
+	Text            // plain text, UTF-8
 )
 
+func (dt DocType) String() string {
+	switch dt {
+	case Doc:
+		return "doc"
+	case Docx:
+		return "docx"
+	case Odt:
+		return "odt"
+	case Text:
+		return "text"
+	}
+	return "unknown"
+}
+
 var (
 	docMagicNumber  = []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}
 	docxMagicNumber = []byte{0x50, 0x4B, 0x03, 0x04}
+	// OpenDocument requires the first file in the zip to be an uncompressed
+	// "mimetype" file, so the name and contents follow the 30-byte header.
+	odtMimetype = []byte("mimetypeapplication/vnd.oasis.opendocument.text")
 )
 
 // DetectDocType checks the initial bytes of a file to determine if it's a Word document.
 // Note: this is not a 100% accurate method since DOCX files share the same magic number as ZIP files.
+// Zip files that aren't OpenDocument are reported as DOCX.
 func DetectDocType(data []byte) DocType {
 	if bytes.HasPrefix(data, docMagicNumber) {
 		return Doc
 	} else if bytes.HasPrefix(data, docxMagicNumber) {
+		if len(data) > 30 && bytes.HasPrefix(data[30:], odtMimetype) {
+			return Odt
+		}
 		return Docx
+	} else if isText(data) {
+		return Text
 	}
 	return Unknown
 }
+
+// isText returns true if the start of the data is UTF-8 without any NUL bytes.
+func isText(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+	if len(data) > 8192 {
+		data = data[:8192]
+		// don't fail on a rune that was cut in half
+		for n := 0; n < utf8.UTFMax && len(data) > 0 && !utf8.Valid(data); n++ {
+			data = data[:len(data)-1]
+		}
+	}
+	return utf8.Valid(data) && bytes.IndexByte(data, 0) == -1
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package office
+
+import (
+	"unicode/utf8"
+)
+
+// replacements maps the punctuation and spaces that Word likes to
+// insert to the plain ASCII the report grammar expects.
+// An empty replacement removes the character.
+var replacements = map[rune]string{
+	'\u00a0': " ",   // no-break space
+	'\u00ad': "",    // soft hyphen
+	'\u2000': " ",   // en quad
+	'\u2001': " ",   // em quad
+	'\u2002': " ",   // en space
+	'\u2003': " ",   // em space
+	'\u2004': " ",   // three-per-em space
+	'\u2005': " ",   // four-per-em space
+	'\u2006': " ",   // six-per-em space
+	'\u2007': " ",   // figure space
+	'\u2008': " ",   // punctuation space
+	'\u2009': " ",   // thin space
+	'\u200a': " ",   // hair space
+	'\u200b': "",    // zero width space
+	'\u200c': "",    // zero width non-joiner
+	'\u200d': "",    // zero width joiner
+	'\u2010': "-",   // hyphen
+	'\u2011': "-",   // non-breaking hyphen
+	'\u2012': "-",   // figure dash
+	'\u2013': "-",   // en dash
+	'\u2014': "-",   // em dash
+	'\u2015': "-",   // horizontal bar
+	'\u2018': "'",   // left single quote
+	'\u2019': "'",   // right single quote
+	'\u201a': "'",   // single low quote
+	'\u201b': "'",   // single high reversed quote
+	'\u201c': `"`,   // left double quote
+	'\u201d': `"`,   // right double quote
+	'\u201e': `"`,   // double low quote
+	'\u201f': `"`,   // double high reversed quote
+	'\u2026': "...", // ellipsis
+	'\u2032': "'",   // prime
+	'\u2033': `"`,   // double prime
+	'\u202f': " ",   // narrow no-break space
+	'\u205f': " ",   // medium mathematical space
+	'\u2212': "-",   // minus sign
+	'\u3000': " ",   // ideographic space
+	'\ufeff': "",    // byte order mark
+}
+
+// normalize replaces Unicode punctuation and whitespace with ASCII.
+// It returns the text and the number of characters that were changed.
+func normalize(text []byte) ([]byte, int) {
+	n, out := 0, make([]byte, 0, len(text))
+	for len(text) > 0 {
+		r, size := utf8.DecodeRune(text)
+		if s, ok := replacements[r]; ok {
+			out = append(out, s...)
+			n++
+		} else {
+			out = append(out, text[:size]...)
+		}
+		text = text[size:]
+	}
+	return out, n
+}
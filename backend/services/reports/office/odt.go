@@ -0,0 +1,326 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package office
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// ParseODT reads an OpenDocument Text (.odt) file and returns the same
+// text that Parse returns for the equivalent .docx file: one line per
+// paragraph, tables one row per line with tab-separated cells, and text
+// boxes after the paragraph they are anchored in.
+//
+// Page headers and footers live in styles.xml and are only read when
+// requested. Only the default header and footer of each master page are
+// read; the left-page and first-page variants repeat the same text.
+func ParseODT(r *bytes.Reader, opts Options) (*WordDocument, error) {
+	zr, err := zip.NewReader(r, r.Size())
+	if err != nil {
+		return nil, errors.Join(ErrNotAnOpenDocument, ErrorUncompressFailed, err)
+	}
+	limits := opts.Limits.withDefaults()
+	if err := limits.checkEntries(zr); err != nil {
+		return nil, errors.Join(ErrBadInput, err)
+	}
+	var content, styles *zip.File
+	for _, file := range zr.File {
+		switch file.Name {
+		case "content.xml":
+			content = file
+		case "styles.xml":
+			styles = file
+		}
+	}
+	if content == nil {
+		return nil, errors.Join(ErrNotAnOpenDocument, ErrOdtContentNotFound)
+	}
+
+	doc := &WordDocument{Report: &Report{}}
+	buf := &bytes.Buffer{}
+	// readPart reads the part, keeping only the text inside the regions.
+	// An empty list of regions keeps all the text.
+	readPart := func(file *zip.File, regions ...string) (int, error) {
+		rc, err := limits.open(file)
+		if err != nil {
+			return 0, errors.Join(ErrBadInput, err)
+		}
+		defer rc.Close()
+		text, found, err := parseOdfXML(rc, file.Name, regions, doc.Report, limits.MaxDepth, limits.MaxTextBytes-buf.Len())
+		if err != nil {
+			return 0, errors.Join(ErrBadInput, err)
+		}
+		buf.Write(text)
+		return found, nil
+	}
+
+	if styles != nil && opts.HeadersFooters {
+		n, err := readPart(styles, "header")
+		if err != nil {
+			return nil, err
+		}
+		doc.Report.Headers += n
+	} else if styles != nil {
+		doc.Report.skip(styles.Name, "header")
+	}
+	if _, err := readPart(content); err != nil {
+		return nil, err
+	}
+	if styles != nil && opts.HeadersFooters {
+		n, err := readPart(styles, "footer")
+		if err != nil {
+			return nil, err
+		}
+		doc.Report.Footers += n
+	} else if styles != nil {
+		doc.Report.skip(styles.Name, "footer")
+	}
+	doc.Text = buf.Bytes()
+	return doc, nil
+}
+
+// parseOdfXML walks an OpenDocument part and builds the text.
+// If regions is not empty, only the text inside those elements is kept
+// and the number of regions found is returned.
+// The limits are the same as for parseWordXML.
+func parseOdfXML(r io.Reader, part string, regions []string, report *Report, maxDepth, maxText int) ([]byte, int, error) {
+	dec := xml.NewDecoder(r)
+
+	var (
+		frames = []*frame{{buf: &bytes.Buffer{}}}
+		paras  = 0 // open paragraphs and headings; text only counts inside them
+		region = 0 // open regions
+		found  = 0 // regions found
+		depth  = 0 // open elements
+		// white space at the start and end of a paragraph is dropped
+		leading  = false // no text written since the paragraph started
+		trailing = false // the last byte written was collapsed white space
+	)
+	top := func() *frame {
+		return frames[len(frames)-1]
+	}
+	isRegion := func(name string) bool {
+		for _, r := range regions {
+			if r == name {
+				return true
+			}
+		}
+		return false
+	}
+	keep := func() bool {
+		return len(regions) == 0 || region > 0
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("xml decode: %w", err)
+		}
+
+		switch se := tok.(type) {
+		case xml.StartElement:
+			if depth++; depth > maxDepth {
+				return nil, 0, fmt.Errorf("%w: %s: limit is %d", ErrNestingTooDeep, part, maxDepth)
+			}
+			if isRegion(se.Name.Local) {
+				region++
+				found++
+			}
+			if !keep() {
+				continue
+			}
+			f := top()
+			switch se.Name.Local {
+			case "s", "tab", "line-break":
+				leading, trailing = false, false
+			}
+			switch se.Name.Local {
+			case "p", "h":
+				paras++
+				leading, trailing = true, false
+				if f.inCell() {
+					// paragraphs in a cell are joined with a space
+					t := f.tables[len(f.tables)-1]
+					if t.paras != 0 {
+						f.buf.WriteByte(' ')
+					}
+					t.paras++
+				}
+
+			case "s":
+				// runs of spaces are stored as a count
+				n := 1
+				for _, attr := range se.Attr {
+					if attr.Name.Local == "c" {
+						if c, err := strconv.Atoi(attr.Value); err == nil && c > 0 {
+							n = c
+						}
+					}
+				}
+				if n > maxText {
+					n = maxText + 1 // enough to trip the check below
+				}
+				f.buf.Write(bytes.Repeat([]byte{' '}, n))
+
+			case "tab":
+				if f.inCell() {
+					// tabs separate the cells of a row
+					f.buf.WriteByte(' ')
+				} else {
+					f.buf.WriteByte('\t')
+				}
+
+			case "line-break":
+				if f.inCell() {
+					f.buf.WriteByte(' ')
+				} else {
+					f.buf.WriteByte('\n')
+				}
+
+			case "soft-hyphen":
+				report.Normalized++
+
+			case "table":
+				f.tables = append(f.tables, &table{})
+				if len(f.tables) == 1 {
+					report.Tables++
+				}
+
+			case "table-row":
+				if f.inCell() {
+					f.tables[len(f.tables)-1].cells = 0
+				}
+
+			case "table-cell", "covered-table-cell":
+				if f.inCell() {
+					t := f.tables[len(f.tables)-1]
+					if t.cells != 0 {
+						f.buf.WriteByte('\t')
+					}
+					t.cells, t.paras = t.cells+1, 0
+				}
+
+			case "text-box":
+				frames = append(frames, &frame{buf: &bytes.Buffer{}})
+				report.TextBoxes++
+
+			case "image", "object", "object-ole":
+				report.skip(part, se.Name.Local)
+
+			case "annotation", "note", "tracked-changes":
+				// comments, footnotes, and deleted text aren't part of the report
+				report.skip(part, se.Name.Local)
+				if err := dec.Skip(); err != nil {
+					return nil, 0, fmt.Errorf("xml decode: %w", err)
+				}
+				depth--
+			}
+
+		case xml.EndElement:
+			depth--
+			if keep() {
+				f := top()
+				switch se.Name.Local {
+				case "p", "h":
+					if paras > 0 {
+						paras--
+					}
+					if trailing {
+						f.buf.Truncate(f.buf.Len() - 1)
+						trailing = false
+					}
+					if !f.inCell() {
+						// inject a line-feed at the end of every paragraph
+						f.buf.WriteByte('\n')
+						f.flush()
+					}
+
+				case "table-row":
+					if len(f.tables) == 1 {
+						f.buf.WriteByte('\n')
+						f.flush()
+					} else if f.inCell() {
+						// rows of a nested table stay on the row of the outer table
+						f.buf.WriteByte('\t')
+					}
+
+				case "table":
+					if f.inCell() {
+						f.tables = f.tables[:len(f.tables)-1]
+					}
+
+				case "text-box":
+					if len(frames) > 1 {
+						f.flush()
+						frames = frames[:len(frames)-1]
+						parent := top()
+						parent.pending = append(parent.pending, f.buf.Bytes())
+					}
+					trailing = false
+				}
+			}
+			if isRegion(se.Name.Local) && region > 0 {
+				region--
+			}
+
+		case xml.CharData:
+			if keep() && paras > 0 {
+				text, n := normalize(collapse(se))
+				if leading {
+					text = bytes.TrimLeft(text, " ")
+				}
+				if len(text) != 0 {
+					top().buf.Write(text)
+					leading, trailing = false, text[len(text)-1] == ' '
+				}
+				report.Normalized += n
+			}
+		}
+
+		size := 0
+		for _, f := range frames {
+			size += f.size()
+		}
+		if size > maxText {
+			return nil, 0, fmt.Errorf("%w: %s: limit is %d", ErrTextTooLong, part, maxText)
+		}
+	}
+
+	// text boxes whose frame was never closed still belong in the output
+	for len(frames) > 1 {
+		f := top()
+		f.flush()
+		frames = frames[:len(frames)-1]
+		top().pending = append(top().pending, f.buf.Bytes())
+	}
+	top().flush()
+	return top().buf.Bytes(), found, nil
+}
+
+// collapse applies the OpenDocument white-space rule to character data:
+// tabs and line-feeds are spaces, and runs of spaces are a single space.
+// Significant spaces are written as <text:s/> and <text:tab/> elements.
+func collapse(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	space := false
+	for _, ch := range data {
+		if ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r' {
+			if !space {
+				out = append(out, ' ')
+			}
+			space = true
+			continue
+		}
+		out, space = append(out, ch), false
+	}
+	return out
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package office_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/services/reports/office"
+)
+
+const (
+	odfHead = `<?xml version="1.0" encoding="UTF-8"?>
+<office:document-content xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0" xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0" xmlns:draw="urn:oasis:names:tc:opendocument:xmlns:drawing:1.0"><office:body><office:text>`
+	odfTail = `</office:text></office:body></office:document-content>`
+)
+
+// odt returns an OpenDocument archive with the parts.
+// The mimetype is stored first and uncompressed, as the standard requires.
+func odt(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		t.Fatal(err)
+	} else if _, err = w.Write([]byte("application/vnd.oasis.opendocument.text")); err != nil {
+		t.Fatal(err)
+	}
+	for name, body := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		} else if _, err = w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtract(t *testing.T) {
+	// the same report as TestParse, saved as .odt
+	content := odfHead +
+		`<text:p>Tribe 0987, , Current Hex = OO 0202</text:p>` +
+		"<text:p>\n  “Big” Rock’s N–S\n</text:p>" +
+		`<table:table><table:table-row><table:table-cell><text:p>a</text:p><text:p>b</text:p></table:table-cell><table:table-cell><text:p>c</text:p></table:table-cell></table:table-row>` +
+		`<table:table-row><table:table-cell><text:p>d</text:p></table:table-cell><table:table-cell><text:p>e<text:tab/>f</text:p></table:table-cell></table:table-row></table:table>` +
+		`<text:p>anchor<draw:frame><draw:text-box><text:p>in the box</text:p></draw:text-box></draw:frame> text</text:p>` +
+		`<text:p><draw:frame><draw:image/></draw:frame><office:annotation><text:p>comment</text:p></office:annotation>last</text:p>` +
+		`<text:p>two<text:s text:c="2"/>spaces<text:tab/>tab</text:p>` +
+		odfTail
+	styles := `<office:document-styles xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0" xmlns:style="urn:oasis:names:tc:opendocument:xmlns:style:1.0" xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0">` +
+		`<office:master-styles><style:master-page><style:header><text:p>header</text:p></style:header><style:header-first><text:p>first page header</text:p></style:header-first><style:footer><text:p>footer</text:p></style:footer><style:footer-left><text:p>left page footer</text:p></style:footer-left></style:master-page></office:master-styles></office:document-styles>`
+	data := odt(t, map[string]string{"content.xml": content, "styles.xml": styles})
+	wantBody := "Tribe 0987, , Current Hex = OO 0202\n" +
+		`"Big" Rock's N-S` + "\n" +
+		"a b\tc\n" +
+		"d\te f\n" +
+		"anchor text\n" +
+		"in the box\n" +
+		"last\n" +
+		"two  spaces\ttab\n"
+
+	if dt := office.DetectDocType(data); dt != office.Odt {
+		t.Fatalf("detect: want odt, got %s", dt)
+	}
+	doc, err := office.Extract(data, office.Options{})
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if got := string(doc.Text); got != wantBody {
+		t.Errorf("text: want\n%q\ngot\n%q", wantBody, got)
+	}
+	if r := doc.Report; r.Tables != 1 || r.TextBoxes != 1 || r.Normalized != 5 {
+		t.Errorf("report: got %s", r)
+	}
+	doc, err = office.Extract(data, office.Options{HeadersFooters: true})
+	if err != nil {
+		t.Fatalf("extract: %v", err)
+	}
+	if want, got := "header\n"+wantBody+"footer\n", string(doc.Text); got != want {
+		t.Errorf("headers and footers: want\n%q\ngot\n%q", want, got)
+	}
+
+	for _, tc := range []struct {
+		id   string
+		data []byte
+		want string
+		err  error
+	}{
+		{id: "text", data: []byte("Tribe 0987\r\nCurrent Turn 899-12 (#0)"), want: "Tribe 0987\nCurrent Turn 899-12 (#0)\n"},
+		{id: "doc", data: []byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1, 0}, err: office.ErrUnsupportedFormat},
+		{id: "binary", data: []byte{0x1f, 0x8b, 0, 0}, err: office.ErrUnsupportedFormat},
+		{id: "empty", data: nil, err: office.ErrUnsupportedFormat},
+	} {
+		doc, err := office.Extract(tc.data, office.Options{})
+		if tc.err != nil {
+			if !errors.Is(err, tc.err) {
+				t.Errorf("%s: want %v, got %v", tc.id, tc.err, err)
+			}
+		} else if err != nil {
+			t.Errorf("%s: want nil, got %v", tc.id, err)
+		} else if got := string(doc.Text); got != tc.want {
+			t.Errorf("%s: want %q, got %q", tc.id, tc.want, got)
+		}
+	}
+}
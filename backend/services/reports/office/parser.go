@@ -3,6 +3,12 @@
 // Package office implements a parser to read a .docx file and
 // return a []byte with whitespace preserved, mostly. We inject
 // a line-feed at the end of every paragraph to help parsing.
+//
+// Tables are written one row per line with the cells separated by tabs.
+// Text boxes are written after the paragraph they are anchored in.
+// Word "smart" punctuation and odd spaces are replaced with plain ASCII
+// (see normalize.go). Anything that isn't extracted is listed in the
+// Report that comes back with the text.
 package office
 
 import (
@@ -12,36 +18,39 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"path"
+	"sort"
 	"strings"
 )
 
 type WordDocument struct {
-	Text []byte
+	Text   []byte
+	Report *Report
+}
+
+// Options controls what the extractor reads.
+type Options struct {
+	// HeadersFooters adds the text of the default page headers before
+	// the body and the text of the default page footers after it.
+	HeadersFooters bool
+	// Limits on the archive and the XML. Zero values use DefaultLimits.
+	Limits Limits
 }
 
 // Parse a reader that's loaded a .docx file. Returns the body text with
 // whitespace preserved from <w:t xml:space="preserve"> plus tabs and
 // line breaks. Injects a line-feed at the end of every paragraph.
 func Parse(r *bytes.Reader) (*WordDocument, error) {
+	return ParseWithOptions(r, Options{})
+}
+
+// ParseWithOptions is Parse with options.
+func ParseWithOptions(r *bytes.Reader, opts Options) (*WordDocument, error) {
 	zr, err := zip.NewReader(r, r.Size())
 	if err != nil {
 		return nil, errors.Join(ErrNotAWordDocument, ErrorUncompressFailed, err)
 	}
-	for _, file := range zr.File {
-		if file.Name == "word/document.xml" {
-			rc, err := file.Open()
-			if err != nil {
-				return nil, errors.Join(ErrBadInput, err)
-			}
-			defer rc.Close()
-			text, err := parseWordXML(rc)
-			if err != nil {
-				return nil, errors.Join(ErrBadInput, err)
-			}
-			return &WordDocument{Text: text}, nil
-		}
-	}
-	return nil, errors.Join(ErrNotAWordDocument, ErrWordXmlDocumentNotFound)
+	return parseZip(zr, opts)
 }
 
 // ParsePath is a helper function. It opens and parses a .docx file. Returns
@@ -53,35 +62,256 @@ func ParsePath(path string) (*WordDocument, error) {
 		return nil, fmt.Errorf("open docx: %w", err)
 	}
 	defer zr.Close()
+	return parseZip(&zr.Reader, Options{})
+}
+
+// parseZip extracts the text from the parts of the document.
+func parseZip(zr *zip.Reader, opts Options) (*WordDocument, error) {
+	limits := opts.Limits.withDefaults()
+	if err := limits.checkEntries(zr); err != nil {
+		return nil, errors.Join(ErrBadInput, err)
+	}
+	var body, rels *zip.File
+	var headers, footers []*zip.File
 	for _, file := range zr.File {
-		if file.Name == "word/document.xml" {
-			rc, err := file.Open()
-			if err != nil {
-				return nil, fmt.Errorf("open document.xml: %w", err)
+		switch {
+		case file.Name == "word/document.xml":
+			body = file
+		case file.Name == "word/_rels/document.xml.rels":
+			rels = file
+		case strings.HasPrefix(file.Name, "word/header") && strings.HasSuffix(file.Name, ".xml"):
+			headers = append(headers, file)
+		case strings.HasPrefix(file.Name, "word/footer") && strings.HasSuffix(file.Name, ".xml"):
+			footers = append(footers, file)
+		}
+	}
+	if body == nil {
+		return nil, errors.Join(ErrNotAWordDocument, ErrWordXmlDocumentNotFound)
+	}
+	for _, parts := range [][]*zip.File{headers, footers} {
+		sort.Slice(parts, func(i, j int) bool {
+			if len(parts[i].Name) != len(parts[j].Name) {
+				return len(parts[i].Name) < len(parts[j].Name) // header2 before header10
+			}
+			return parts[i].Name < parts[j].Name
+		})
+	}
+
+	// only the default header and footer of each section are read;
+	// the first-page and even-page variants repeat the same text.
+	var defaultHeaders, defaultFooters []*zip.File
+	if opts.HeadersFooters {
+		var err error
+		defaultHeaders, defaultFooters, err = defaultHeadersFooters(body, rels, headers, footers, limits)
+		if err != nil {
+			return nil, errors.Join(ErrBadInput, err)
+		}
+	}
+
+	doc := &WordDocument{Report: &Report{}}
+	buf := &bytes.Buffer{}
+	readPart := func(file *zip.File) error {
+		rc, err := limits.open(file)
+		if err != nil {
+			return errors.Join(ErrBadInput, err)
+		}
+		defer rc.Close()
+		text, err := parseWordXML(rc, file.Name, doc.Report, limits.MaxDepth, limits.MaxTextBytes-buf.Len())
+		if err != nil {
+			return errors.Join(ErrBadInput, err)
+		}
+		buf.Write(text)
+		return nil
+	}
+
+	for _, file := range headers {
+		if !contains(defaultHeaders, file) {
+			doc.Report.skip(file.Name, "header")
+		}
+	}
+	for _, file := range defaultHeaders {
+		if err := readPart(file); err != nil {
+			return nil, err
+		}
+		doc.Report.Headers++
+	}
+	if err := readPart(body); err != nil {
+		return nil, err
+	}
+	for _, file := range defaultFooters {
+		if err := readPart(file); err != nil {
+			return nil, err
+		}
+		doc.Report.Footers++
+	}
+	for _, file := range footers {
+		if !contains(defaultFooters, file) {
+			doc.Report.skip(file.Name, "footer")
+		}
+	}
+	doc.Text = buf.Bytes()
+	return doc, nil
+}
+
+// defaultHeadersFooters returns the default header and footer parts of
+// the sections in the body, in section order. A section without its own
+// default inherits the previous one, so each part is returned only once.
+// The parts are found through the relationships of the body.
+func defaultHeadersFooters(body, rels *zip.File, headers, footers []*zip.File, limits Limits) ([]*zip.File, []*zip.File, error) {
+	if rels == nil {
+		return nil, nil, nil
+	}
+
+	// map the relationship ids to part names
+	targets := map[string]string{}
+	rc, err := limits.open(rels)
+	if err != nil {
+		return nil, nil, err
+	}
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			rc.Close()
+			return nil, nil, fmt.Errorf("%s: xml decode: %w", rels.Name, err)
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "Relationship" {
+			var id, target string
+			for _, attr := range se.Attr {
+				switch attr.Name.Local {
+				case "Id":
+					id = attr.Value
+				case "Target":
+					target = attr.Value
+				}
 			}
-			defer rc.Close()
-			text, err := parseWordXML(rc)
-			if err != nil {
-				return nil, err
+			// targets are relative to the word folder unless they start with a slash
+			if strings.HasPrefix(target, "/") {
+				targets[id] = path.Clean(target[1:])
+			} else {
+				targets[id] = path.Clean("word/" + target)
 			}
-			return &WordDocument{Text: text}, nil
 		}
 	}
-	return nil, errors.New("word/document.xml not found")
+	rc.Close()
+
+	// find the references in the section properties of the body
+	var defaultHeaders, defaultFooters []*zip.File
+	rc, err = limits.open(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rc.Close()
+	dec = xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("%s: xml decode: %w", body.Name, err)
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || (se.Name.Local != "headerReference" && se.Name.Local != "footerReference") {
+			continue
+		}
+		var kind, id string
+		for _, attr := range se.Attr {
+			switch attr.Name.Local {
+			case "type":
+				kind = attr.Value
+			case "id":
+				id = attr.Value
+			}
+		}
+		if kind != "default" {
+			continue
+		}
+		if se.Name.Local == "headerReference" {
+			if file := partNamed(headers, targets[id]); file != nil && !contains(defaultHeaders, file) {
+				defaultHeaders = append(defaultHeaders, file)
+			}
+		} else if file := partNamed(footers, targets[id]); file != nil && !contains(defaultFooters, file) {
+			defaultFooters = append(defaultFooters, file)
+		}
+	}
+	return defaultHeaders, defaultFooters, nil
+}
+
+// partNamed returns the part with the name, or nil if there isn't one.
+func partNamed(parts []*zip.File, name string) *zip.File {
+	for _, file := range parts {
+		if file.Name == name {
+			return file
+		}
+	}
+	return nil
+}
+
+// contains returns true if the part is in the list.
+func contains(parts []*zip.File, file *zip.File) bool {
+	for _, part := range parts {
+		if part == file {
+			return true
+		}
+	}
+	return false
+}
+
+// frame is the output for the body or for a text box.
+// Text boxes get their own frame since they are anchored inside
+// a paragraph and are written after that paragraph ends.
+type frame struct {
+	buf     *bytes.Buffer
+	tables  []*table // open tables, innermost last
+	pending [][]byte // text boxes waiting for the current paragraph or row to end
+}
+
+type table struct {
+	cells int // cells started in the current row
+	paras int // paragraphs started in the current cell
+}
+
+// inCell returns true if the frame is inside a table cell.
+func (f *frame) inCell() bool {
+	return len(f.tables) != 0
+}
+
+// flush writes the pending text boxes.
+func (f *frame) flush() {
+	for _, text := range f.pending {
+		f.buf.Write(text)
+	}
+	f.pending = nil
+}
+
+// size returns the number of bytes written to the frame.
+func (f *frame) size() int {
+	n := f.buf.Len()
+	for _, text := range f.pending {
+		n += len(text)
+	}
+	return n
 }
 
 // parseWordXML actually walks the XML and builds the text.
-func parseWordXML(r io.Reader) ([]byte, error) {
+// The part is the name of the part being read and is only used in the report.
+// It returns an error if the elements are nested more than maxDepth deep
+// or if the text is longer than maxText bytes.
+func parseWordXML(r io.Reader, part string, report *Report, maxDepth, maxText int) ([]byte, error) {
 	dec := xml.NewDecoder(r)
 
 	var (
-		buf         = &bytes.Buffer{}
-		inP         = false // we're inside a paragraph
-		inT         = false // we're inside a <w:t>
-		preserve    = false // xml:space="preserve" on current <w:t>
-		seenTextInP = false // to know if paragraph has any text
+		frames   = []*frame{{buf: &bytes.Buffer{}}}
+		inT      = false // we're inside a <w:t>
+		drawings = 0     // open drawings, pictures, and objects
+		boxes    = 0     // text boxes found in the open drawings
+		depth    = 0     // open elements
 	)
-	_, _ = inP, seenTextInP
+	top := func() *frame {
+		return frames[len(frames)-1]
+	}
 
 	for {
 		tok, err := dec.Token()
@@ -94,61 +324,159 @@ func parseWordXML(r io.Reader) ([]byte, error) {
 
 		switch se := tok.(type) {
 		case xml.StartElement:
-			local := se.Name.Local
-
-			switch local {
+			if depth++; depth > maxDepth {
+				return nil, fmt.Errorf("%w: %s: limit is %d", ErrNestingTooDeep, part, maxDepth)
+			}
+			f := top()
+			switch se.Name.Local {
 			case "p":
-				inP = true
-				seenTextInP = false
+				if f.inCell() {
+					// paragraphs in a cell are joined with a space
+					t := f.tables[len(f.tables)-1]
+					if t.paras != 0 {
+						f.buf.WriteByte(' ')
+					}
+					t.paras++
+				}
 
 			case "t":
 				inT = true
-				preserve = false
-				// check attributes for xml:space="preserve"
-				for _, a := range se.Attr {
-					if (a.Name.Local == "space" || strings.HasSuffix(a.Name.Local, "space")) &&
-						a.Value == "preserve" {
-						preserve = true
-						break
+
+			case "tab":
+				if f.inCell() {
+					// tabs separate the cells of a row
+					f.buf.WriteByte(' ')
+				} else {
+					f.buf.WriteByte('\t')
+				}
+
+			case "br", "cr":
+				if f.inCell() {
+					f.buf.WriteByte(' ')
+				} else {
+					f.buf.WriteByte('\n')
+				}
+
+			case "noBreakHyphen":
+				f.buf.WriteByte('-')
+				report.Normalized++
+
+			case "tbl":
+				f.tables = append(f.tables, &table{})
+				if len(f.tables) == 1 {
+					report.Tables++
+				}
+
+			case "tr":
+				if f.inCell() {
+					f.tables[len(f.tables)-1].cells = 0
+				}
+
+			case "tc":
+				if f.inCell() {
+					t := f.tables[len(f.tables)-1]
+					if t.cells != 0 {
+						f.buf.WriteByte('\t')
 					}
+					t.cells, t.paras = t.cells+1, 0
 				}
 
-			case "tab":
-				buf.WriteByte('\t')
-				seenTextInP = true
+			case "txbxContent":
+				frames = append(frames, &frame{buf: &bytes.Buffer{}})
+				boxes++
+				report.TextBoxes++
+
+			case "drawing", "pict", "object":
+				drawings++
+				if drawings == 1 {
+					boxes = 0
+				}
 
-			case "br":
-				buf.WriteByte('\n')
-				seenTextInP = true
+			case "Fallback":
+				// the fallback repeats the choice for older readers
+				report.skip(part, "fallback")
+				if err := dec.Skip(); err != nil {
+					return nil, fmt.Errorf("xml decode: %w", err)
+				}
+				depth--
+
+			case "delText", "instrText", "sym", "footnoteReference", "endnoteReference", "commentReference":
+				report.skip(part, se.Name.Local)
 			}
 
 		case xml.EndElement:
-			local := se.Name.Local
-			switch local {
+			depth--
+			f := top()
+			switch se.Name.Local {
 			case "t":
 				inT = false
-				preserve = false
+
 			case "p":
-				// inject a line-feed at the end of every paragraph
-				buf.WriteByte('\n')
-				inP = false
-				seenTextInP = false
+				if !f.inCell() {
+					// inject a line-feed at the end of every paragraph
+					f.buf.WriteByte('\n')
+					f.flush()
+				}
+
+			case "tr":
+				if len(f.tables) == 1 {
+					f.buf.WriteByte('\n')
+					f.flush()
+				} else if f.inCell() {
+					// rows of a nested table stay on the row of the outer table
+					f.buf.WriteByte('\t')
+				}
+
+			case "tbl":
+				if f.inCell() {
+					f.tables = f.tables[:len(f.tables)-1]
+				}
+
+			case "txbxContent":
+				if len(frames) > 1 {
+					f.flush()
+					frames = frames[:len(frames)-1]
+					parent := top()
+					parent.pending = append(parent.pending, f.buf.Bytes())
+				}
+
+			case "drawing", "pict", "object":
+				if drawings > 0 {
+					drawings--
+					if drawings == 0 && boxes == 0 {
+						report.skip(part, se.Name.Local)
+					}
+				}
 			}
 
 		case xml.CharData:
 			if inT {
-				text := string(se)
-				if preserve {
-					buf.WriteString(text)
-				} else {
-					// most Word text here is already “normal”, but just in case,
-					// don’t trim aggressively — just write it.
-					buf.WriteString(text)
+				text, n := normalize(se)
+				if top().inCell() {
+					// tabs separate the cells of a row
+					text = bytes.ReplaceAll(text, []byte{'\t'}, []byte{' '})
 				}
-				seenTextInP = true
+				top().buf.Write(text)
+				report.Normalized += n
 			}
 		}
+
+		size := 0
+		for _, f := range frames {
+			size += f.size()
+		}
+		if size > maxText {
+			return nil, fmt.Errorf("%w: %s: limit is %d", ErrTextTooLong, part, maxText)
+		}
 	}
 
-	return buf.Bytes(), nil
+	// text boxes whose frame was never closed still belong in the output
+	for len(frames) > 1 {
+		f := top()
+		f.flush()
+		frames = frames[:len(frames)-1]
+		top().pending = append(top().pending, f.buf.Bytes())
+	}
+	top().flush()
+	return top().buf.Bytes(), nil
 }
@@ -0,0 +1,117 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package office_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/services/reports/office"
+)
+
+const (
+	wordHead = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:mc="http://schemas.openxmlformats.org/markup-compatibility/2006" xmlns:wps="http://schemas.microsoft.com/office/word/2010/wordprocessingShape" xmlns:v="urn:schemas-microsoft-com:vml" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:body>`
+	wordTail = `</w:body></w:document>`
+)
+
+// docx returns a zip with the parts.
+func docx(t *testing.T, parts map[string]string) *bytes.Reader {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	zw := zip.NewWriter(buf)
+	for name, body := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		} else if _, err = w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestParse(t *testing.T) {
+	body := wordHead +
+		`<w:p><w:r><w:t>Tribe 0987, , Current Hex = OO 0202</w:t></w:r></w:p>` +
+		// smart quotes, a no-break space, and an en dash
+		`<w:p><w:r><w:t>“Big” Rock’s` + "\u00a0" + `N–S</w:t></w:r></w:p>` +
+		// a table with two paragraphs in the first cell
+		`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>a</w:t></w:r></w:p><w:p><w:r><w:t>b</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>c</w:t></w:r></w:p></w:tc></w:tr>` +
+		`<w:tr><w:tc><w:p><w:r><w:t>d</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>e</w:t></w:r><w:r><w:tab/><w:t>f</w:t></w:r></w:p></w:tc></w:tr></w:tbl>` +
+		// a text box with a fallback that repeats it
+		`<w:p><w:r><w:t>anchor</w:t></w:r><w:r><mc:AlternateContent><mc:Choice Requires="wps"><w:drawing><wps:txbx><w:txbxContent><w:p><w:r><w:t>in the box</w:t></w:r></w:p></w:txbxContent></wps:txbx></w:drawing></mc:Choice>` +
+		`<mc:Fallback><w:pict><v:textbox><w:txbxContent><w:p><w:r><w:t>in the box</w:t></w:r></w:p></w:txbxContent></v:textbox></w:pict></mc:Fallback></mc:AlternateContent></w:r><w:r><w:t> text</w:t></w:r></w:p>` +
+		// an image and deleted text
+		`<w:p><w:r><w:drawing/></w:r><w:del><w:r><w:delText>gone</w:delText></w:r></w:del><w:r><w:t>last</w:t></w:r></w:p>` +
+		// the section uses a different header on the first page
+		`<w:sectPr><w:headerReference w:type="first" r:id="rId2"/><w:headerReference w:type="default" r:id="rId1"/><w:footerReference w:type="default" r:id="rId3"/><w:titlePg/></w:sectPr>` +
+		wordTail
+	header := wordHead + `<w:p><w:r><w:t>header</w:t></w:r></w:p>` + wordTail
+	first := wordHead + `<w:p><w:r><w:t>first page header</w:t></w:r></w:p>` + wordTail
+	footer := wordHead + `<w:p><w:r><w:t>footer</w:t></w:r></w:p>` + wordTail
+	rels := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/header" Target="header1.xml"/>` +
+		`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/header" Target="header2.xml"/>` +
+		`<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer" Target="footer1.xml"/>` +
+		`</Relationships>`
+
+	parts := map[string]string{
+		"word/document.xml":            body,
+		"word/_rels/document.xml.rels": rels,
+		"word/header1.xml":             header,
+		"word/header2.xml":             first,
+		"word/footer1.xml":             footer,
+	}
+	wantBody := "Tribe 0987, , Current Hex = OO 0202\n" +
+		`"Big" Rock's N-S` + "\n" +
+		"a b\tc\n" +
+		"d\te f\n" +
+		"anchor text\n" +
+		"in the box\n" +
+		"last\n"
+
+	doc, err := office.Parse(docx(t, parts))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := string(doc.Text); got != wantBody {
+		t.Errorf("text: want\n%q\ngot\n%q", wantBody, got)
+	}
+	r := doc.Report
+	if r.Tables != 1 || r.TextBoxes != 1 || r.Normalized != 5 || r.Headers != 0 || r.Footers != 0 {
+		t.Errorf("report: got %s", r)
+	}
+	skipped := map[string]int{}
+	for _, s := range r.Skipped {
+		skipped[s.Element] += s.Count
+	}
+	for element, want := range map[string]int{"fallback": 1, "drawing": 1, "delText": 1, "header": 2, "footer": 1} {
+		if skipped[element] != want {
+			t.Errorf("skipped %s: want %d, got %d", element, want, skipped[element])
+		}
+	}
+
+	doc, err = office.ParseWithOptions(docx(t, parts), office.Options{HeadersFooters: true})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if want, got := "header\n"+wantBody+"footer\n", string(doc.Text); got != want {
+		t.Errorf("headers and footers: want\n%q\ngot\n%q", want, got)
+	}
+	if doc.Report.Headers != 1 || doc.Report.Footers != 1 {
+		t.Errorf("headers and footers: got %s", doc.Report)
+	}
+	skipped = map[string]int{}
+	for _, s := range doc.Report.Skipped {
+		skipped[s.Part+" "+s.Element] += s.Count
+	}
+	if skipped["word/header2.xml header"] != 1 || skipped["word/header1.xml header"] != 0 {
+		t.Errorf("headers and footers: want the first page header skipped, got %s", doc.Report)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package office
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Report describes how the text was extracted from a document.
+type Report struct {
+	Tables     int // top level tables; rows are written as tab-separated cells
+	TextBoxes  int // text boxes; written after the paragraph they are anchored in
+	Headers    int // header parts read
+	Footers    int // footer parts read
+	Normalized int // characters replaced or removed by normalize
+	Skipped    []*Skipped
+}
+
+// Skipped is content that was not extracted.
+// Element is the name of the element (for example "drawing" or "delText")
+// or "header" and "footer" for parts that weren't requested.
+type Skipped struct {
+	Part    string // name of the part in the zip, e.g. word/document.xml
+	Element string
+	Count   int
+}
+
+// skip records content that was not extracted.
+func (r *Report) skip(part, element string) {
+	for _, s := range r.Skipped {
+		if s.Part == part && s.Element == element {
+			s.Count++
+			return
+		}
+	}
+	r.Skipped = append(r.Skipped, &Skipped{Part: part, Element: element, Count: 1})
+}
+
+// String returns the report as one line per item.
+func (r *Report) String() string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "tables %d, text boxes %d, headers %d, footers %d, normalized %d\n", r.Tables, r.TextBoxes, r.Headers, r.Footers, r.Normalized)
+	for _, s := range r.Skipped {
+		fmt.Fprintf(sb, "skipped %s: %s: %d\n", s.Part, s.Element, s.Count)
+	}
+	return sb.String()
+}
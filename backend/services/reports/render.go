@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package reports
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+// Renderer draws a clan's map for a turn. Maps are cumulative, so the
+// extracts are the clan's extracts for the turn and every turn before it,
+// oldest first.
+type Renderer interface {
+	Render(game string, clanNo int, turnId string, extracts []*MapExtract) ([]byte, error)
+}
+
+// MapExtract is a report extract that a map is drawn from.
+type MapExtract struct {
+	DocumentName string
+	TurnId       string
+	Contents     []byte // text of the extract
+	Output       []byte // JSON encoded parser output; nil if the extract hasn't been parsed
+}
+
+// Ottomap is a Renderer that runs the ottomap command the same way the
+// generated makefiles do (see backend/make). The extracts are written to
+// a temporary clan folder and the map is read back from its output folder.
+type Ottomap struct {
+	Path string // path to the ottomap executable
+}
+
+// Render implements the Renderer interface.
+func (o *Ottomap) Render(game string, clanNo int, turnId string, extracts []*MapExtract) ([]byte, error) {
+	clan := fmt.Sprintf("%04d", clanNo)
+	root, err := os.MkdirTemp("", "ottomap-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(root)
+	inputPath, outputPath := filepath.Join(root, "data", "input"), filepath.Join(root, "data", "output")
+	for _, path := range []string{inputPath, outputPath} {
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			return nil, err
+		}
+	}
+	for _, extract := range extracts {
+		// extracts are stored as {game}.{turnNo}.{clan}.report.txt, ottomap wants {turnNo}.{clan}.report.txt
+		name := strings.TrimPrefix(extract.DocumentName, game+".")
+		if err := os.WriteFile(filepath.Join(inputPath, name), extract.Contents, 0o644); err != nil {
+			return nil, err
+		}
+	}
+	cmd := exec.Command(o.Path, "render", "--clan-id", clan, "--max-turn", turnId, "--show-grid-coords", "--save-with-turn-id")
+	cmd.Dir = root
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%s: render %s.%s: %w: %s", o.Path, turnId, clan, err, bytes.TrimSpace(output))
+	}
+	return os.ReadFile(filepath.Join(outputPath, fmt.Sprintf("%s.%s.wxx", turnId, clan)))
+}
+
+// renderMaps re-renders the clan's stored maps for the turn and every
+// later turn and returns the turns whose new map differs from the stored
+// map. Outputs holds parse outputs that haven't been stored yet; they
+// replace the recorded output of those extracts.
+func (s *Service) renderMaps(game string, clanNo int, from string, outputs map[domains.ID][]byte) ([]string, error) {
+	ctx := s.db.Context()
+	maps, err := s.db.Queries().ReadClanMaps(ctx, sqlc.ReadClanMapsParams{Code: game, Clan: int64(clanNo)})
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.Queries().ReadClanReportParses(ctx, sqlc.ReadClanReportParsesParams{Code: game, Clan: int64(clanNo)})
+	if err != nil {
+		return nil, err
+	}
+	var extracts []*MapExtract
+	for _, row := range rows {
+		extract := &MapExtract{
+			DocumentName: row.DocumentName,
+			TurnId:       domains.TurnIdFromName(row.DocumentName),
+			Output:       row.Output,
+		}
+		if output, ok := outputs[domains.ID(row.DocumentID)]; ok {
+			extract.Output = output
+		}
+		if extract.Contents, err = s.blobs.Get(ctx, s.db.Queries(), row.ContentsHash); err != nil {
+			return nil, err
+		}
+		extracts = append(extracts, extract)
+	}
+	sort.SliceStable(extracts, func(i, j int) bool {
+		return extracts[i].TurnId < extracts[j].TurnId
+	})
+
+	var turns []string
+	for _, m := range maps {
+		// only the maps that the renderer would have written
+		turnId := domains.TurnIdFromName(m.DocumentName)
+		if turnId < from || m.DocumentName != fmt.Sprintf("%s.%s.%04d.wxx", game, turnId, clanNo) {
+			continue
+		}
+		var input []*MapExtract
+		for _, extract := range extracts {
+			if extract.TurnId <= turnId {
+				input = append(input, extract)
+			}
+		}
+		contents, err := s.renderer.Render(game, clanNo, turnId, input)
+		if err != nil {
+			return nil, err
+		}
+		sum := sha256.Sum256(contents)
+		if hex.EncodeToString(sum[:]) != m.ContentsHash {
+			turns = append(turns, turnId)
+		}
+	}
+	sort.Strings(turns)
+	return turns, nil
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package reports
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/maloquacious/semver"
+	"github.com/playbymail/ottoapp/backend/domains"
+)
+
+// ReparseOptions controls a reparse run.
+type ReparseOptions struct {
+	Workers int  // number of parsers to run at once; defaults to the number of CPUs
+	All     bool // re-parse every extract, not just the stale ones
+	DryRun  bool // parse and compare but don't store the results
+}
+
+// ReparseResult is the outcome of re-parsing one extract.
+type ReparseResult struct {
+	DocumentID   domains.ID
+	DocumentName string
+	Game         string
+	ClanNo       int
+	TurnId       string
+	Reason       string         // why the extract was re-parsed
+	From         string         // parser and version of the previous parse; empty if never parsed
+	To           semver.Version // version of the parser that made the new parse
+	Parser       string
+	Changed      bool   // true if the output differs from the previous parse; false if never parsed
+	Error        string // parse error or failure to load the extract
+}
+
+// StaleMap lists the turns for a clan whose stored map differs from the
+// map rendered from the re-parsed extracts. The stored maps for those
+// turns are out of date until they are replaced.
+type StaleMap struct {
+	Game   string
+	ClanNo int
+	Turns  []string // sorted
+	Error  string   // failure to render the clan's maps; Turns is empty
+}
+
+// ReparseSummary is the outcome of a reparse run.
+type ReparseSummary struct {
+	Checked   int // number of extracts checked
+	Reparsed  int // number of extracts re-parsed
+	Changed   int // number of extracts whose output changed
+	Failed    int // number of extracts that failed to parse
+	Results   []*ReparseResult
+	StaleMaps []*StaleMap // clans whose maps are out of date or failed to render
+	Elapsed   time.Duration
+}
+
+// Reparse re-parses stored extracts whose parse is stale (see ReadParseStatus)
+// using a pool of workers, compares the new output with the previous output,
+// and records the new parse. The maps of every clan whose output changed are
+// re-rendered and compared with the stored maps; StaleMaps in the summary
+// lists only the clans and turns whose map is different. The stored maps
+// are not replaced.
+//
+// Parsing runs in the workers; the results are stored by the caller's
+// goroutine, after the maps are rendered, so that writes to the database
+// are never concurrent.
+func (s *Service) Reparse(opts ReparseOptions, quiet, verbose, debug bool) (*ReparseSummary, error) {
+	started := time.Now()
+	if opts.Workers < 1 {
+		opts.Workers = runtime.NumCPU()
+	}
+	list, err := s.ReadParseStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ReparseSummary{Checked: len(list)}
+	var work []*ParseStatus
+	for _, ps := range list {
+		if ps.Stale || opts.All {
+			work = append(work, ps)
+		}
+	}
+	if verbose {
+		log.Printf("[reports] reparse: %d extracts: %d to parse: %d workers\n", len(list), len(work), opts.Workers)
+	}
+
+	type job struct {
+		ps     *ParseStatus
+		result *ReparseResult
+		parse  *Parse
+	}
+	jobs, done := make(chan *job), make(chan *job)
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				p, err := s.parseDocument(j.ps.DocumentID, j.ps.Format, debug)
+				if err != nil {
+					j.result.Error = err.Error()
+				} else {
+					j.parse = p
+					j.result.Parser, j.result.To = p.Parser, p.Version
+					j.result.Error = p.Error
+					// a first parse has nothing to compare with
+					j.result.Changed = j.ps.OutputHash != "" && p.OutputHash != j.ps.OutputHash
+				}
+				done <- j
+			}
+		}()
+	}
+	go func() {
+		for _, ps := range work {
+			result := &ReparseResult{
+				DocumentID:   ps.DocumentID,
+				DocumentName: ps.DocumentName,
+				Game:         ps.Game,
+				ClanNo:       ps.ClanNo,
+				TurnId:       domains.TurnIdFromName(ps.DocumentName),
+				Reason:       ps.Reason,
+			}
+			if result.Reason == "" {
+				result.Reason = "forced"
+			}
+			if ps.Parser != "" {
+				result.From = fmt.Sprintf("%s %s", ps.Parser, ps.Version.Core())
+			}
+			jobs <- &job{ps: ps, result: result}
+		}
+		close(jobs)
+		wg.Wait()
+		close(done)
+	}()
+
+	// collect the results, then render the maps before storing anything
+	var parsed []*job
+	for j := range done {
+		summary.Results = append(summary.Results, j.result)
+		if j.parse != nil {
+			parsed = append(parsed, j)
+		}
+	}
+
+	sort.Slice(summary.Results, func(i, j int) bool {
+		a, b := summary.Results[i], summary.Results[j]
+		if a.Game != b.Game {
+			return a.Game < b.Game
+		}
+		return a.DocumentName < b.DocumentName
+	})
+	type clanKey struct {
+		game   string
+		clanNo int
+	}
+	var changed []clanKey
+	from := map[clanKey]string{} // earliest turn whose output changed
+	for _, r := range summary.Results {
+		if r.Error != "" {
+			summary.Failed++
+		}
+		if r.Parser == "" {
+			// could not load or parse the extract
+			continue
+		}
+		summary.Reparsed++
+		if !r.Changed {
+			continue
+		}
+		summary.Changed++
+		k := clanKey{game: r.Game, clanNo: r.ClanNo}
+		if turnId, ok := from[k]; !ok {
+			changed = append(changed, k)
+			from[k] = r.TurnId
+		} else if r.TurnId < turnId {
+			from[k] = r.TurnId
+		}
+	}
+
+	// render the maps from the new output, which isn't stored yet
+	outputs := map[domains.ID][]byte{}
+	for _, j := range parsed {
+		outputs[j.parse.DocumentID] = j.parse.Output
+	}
+	for _, k := range changed {
+		turns, err := s.renderMaps(k.game, k.clanNo, from[k], outputs)
+		if err != nil {
+			log.Printf("[reports] reparse: %s.%04d: render: %v\n", k.game, k.clanNo, err)
+			summary.StaleMaps = append(summary.StaleMaps, &StaleMap{Game: k.game, ClanNo: k.clanNo, Error: err.Error()})
+		} else if len(turns) != 0 {
+			summary.StaleMaps = append(summary.StaleMaps, &StaleMap{Game: k.game, ClanNo: k.clanNo, Turns: turns})
+		}
+		if verbose {
+			log.Printf("[reports] reparse: %s.%04d: %d maps changed\n", k.game, k.clanNo, len(turns))
+		}
+	}
+	sort.Slice(summary.StaleMaps, func(i, j int) bool {
+		a, b := summary.StaleMaps[i], summary.StaleMaps[j]
+		if a.Game != b.Game {
+			return a.Game < b.Game
+		}
+		return a.ClanNo < b.ClanNo
+	})
+
+	if !opts.DryRun {
+		for _, j := range parsed {
+			if err := s.storeParse(j.parse); err != nil {
+				return nil, err
+			}
+			if verbose {
+				log.Printf("[reports] reparse: %s: %s: changed %v\n", j.result.DocumentName, j.result.Reason, j.result.Changed)
+			}
+		}
+	}
+	summary.Elapsed = time.Since(started)
+
+	return summary, nil
+}
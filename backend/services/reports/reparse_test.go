@@ -0,0 +1,116 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package reports_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/services/documents"
+	"github.com/playbymail/ottoapp/backend/services/reports"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite"
+)
+
+// renderer draws the 0900-01 map differently from the stored map.
+type renderer struct {
+	calls []string
+}
+
+func (r *renderer) Render(game string, clanNo int, turnId string, extracts []*reports.MapExtract) ([]byte, error) {
+	r.calls = append(r.calls, turnId)
+	for _, extract := range extracts {
+		if extract.Output == nil || extract.TurnId > turnId {
+			return nil, domains.ErrBadInput
+		}
+	}
+	if turnId == "0900-01" {
+		return []byte("new map"), nil
+	}
+	return []byte("map " + turnId), nil
+}
+
+func TestReparse_Maps(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlite.OpenTempDB(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	for _, stmt := range []string{
+		`INSERT INTO users (user_id, handle, username, email, timezone, is_active, is_user, created_at, updated_at)
+		 VALUES (2, 'alice', 'alice', 'alice@example.com', 'UTC', 1, 1, 1, 1)`,
+		`INSERT INTO games (game_id, code, description, active_turn, setup_turn, orders_due, created_at, updated_at)
+		 VALUES (1, '0301', 'test game', '0899-12', '0899-12', 1, 1, 1)`,
+		`INSERT INTO game_turns (game_id, turn, turn_year, turn_month, turn_no, created_at, updated_at)
+		 VALUES (1, '0899-12', 899, 12, 0, 1, 1)`,
+		`INSERT INTO clans (clan_id, game_id, user_id, clan, setup_turn, created_at, updated_at)
+		 VALUES (1, 1, 2, 987, '0899-12', 1, 1)`,
+	} {
+		if _, err := db.Stdlib().ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("fixture: %v", err)
+		}
+	}
+	documentsSvc, err := documents.New(db, nil, nil, nil, nil, true, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice := &domains.Actor{ID: 2, Roles: domains.Roles{Active: true, User: true}}
+	c0987 := &domains.Clan{GameID: 1, UserID: 2, ClanID: 1, ClanNo: 987, IsActive: true}
+	for _, doc := range []*domains.Document{
+		{Path: "0301.0899-12.0987.report.txt", Type: domains.TurnReportExtract, Contents: []byte("Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)\n" +
+			"Current Turn 899-12 (#0), Winter, FINE\n" +
+			"Tribe Movement: Move NE-PR\n")},
+		{Path: "0301.0899-12.0987.wxx", Type: domains.WorldographerMap, Contents: []byte("map 0899-12")},
+		{Path: "0301.0900-01.0987.wxx", Type: domains.WorldographerMap, Contents: []byte("map 0900-01")},
+	} {
+		doc.ModifiedAt = time.Now().UTC()
+		if _, err := documentsSvc.ReplaceDocument(alice, c0987, doc, true, false, false); err != nil {
+			t.Fatalf("replace %s: %v", doc.Path, err)
+		}
+	}
+	// pretend that an older parser made different output
+	if _, err := db.Stdlib().ExecContext(ctx, `UPDATE report_parses SET output_hash = 'older'`); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		dryRun bool
+		calls  int
+		want   []string // turns whose map is out of date
+	}{
+		{"dry run", true, 2, []string{"0900-01"}},
+		{"store", false, 2, []string{"0900-01"}},
+		// the stored output is current, so nothing changed and nothing is rendered
+		{"again", false, 0, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &renderer{}
+			summary, err := reports.New(db, nil, nil, r).Reparse(reports.ReparseOptions{All: true, DryRun: tc.dryRun}, true, false, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(r.calls) != tc.calls {
+				t.Errorf("renders: got %v, want %d", r.calls, tc.calls)
+			}
+			if tc.want == nil {
+				if len(summary.StaleMaps) != 0 {
+					t.Errorf("stale maps: got %+v, want none", summary.StaleMaps[0])
+				}
+				return
+			}
+			if len(summary.StaleMaps) != 1 {
+				t.Fatalf("stale maps: got %d clans, want 1", len(summary.StaleMaps))
+			}
+			sm := summary.StaleMaps[0]
+			if sm.Error != "" {
+				t.Fatalf("stale maps: %s", sm.Error)
+			}
+			if sm.Game != "0301" || sm.ClanNo != 987 || len(sm.Turns) != len(tc.want) || sm.Turns[0] != tc.want[0] {
+				t.Errorf("stale maps: got %s.%04d %v, want 0301.0987 %v", sm.Game, sm.ClanNo, sm.Turns, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package scrubbers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// Action is what a rule does to a line that matches it.
+type Action string
+
+const (
+	// Keep the line and stop checking rules.
+	Keep Action = "keep"
+	// Drop the line and stop checking rules.
+	Drop Action = "drop"
+	// Rewrite the matching text with the replacement and keep checking
+	// rules. The rewritten line must still be kept by a later rule.
+	Rewrite Action = "rewrite"
+)
+
+func (a Action) IsValid() bool {
+	switch a {
+	case Keep, Drop, Rewrite:
+		return true
+	}
+	return false
+}
+
+// Rule is one step in scrubbing a line. Rules are checked in order.
+type Rule struct {
+	Name    string `json:"name"`
+	Match   string `json:"match"`             // regular expression
+	Action  Action `json:"action"`            // keep, drop, or rewrite
+	Replace string `json:"replace,omitempty"` // for rewrite; may use $1 and friends
+
+	re *regexp.Regexp
+}
+
+// compile checks the rule and compiles the regular expression.
+func (r *Rule) compile() error {
+	if r.Name == "" {
+		return fmt.Errorf("rule %q: missing name", r.Match)
+	} else if !r.Action.IsValid() {
+		return fmt.Errorf("rule %q: invalid action %q", r.Name, r.Action)
+	} else if r.Action != Rewrite && r.Replace != "" {
+		return fmt.Errorf("rule %q: replace is only allowed on rewrite", r.Name)
+	}
+	re, err := regexp.Compile(r.Match)
+	if err != nil {
+		return fmt.Errorf("rule %q: %w", r.Name, err)
+	}
+	r.re = re
+	return nil
+}
+
+// ParseRules returns the rules from a JSON array. For example,
+//
+//	[
+//	  {"name": "status", "match": "^\\d{4}([cefg]\\d)? Status:", "action": "keep"},
+//	  {"name": "fix-typo", "match": "Prevous Hex", "action": "rewrite", "replace": "Previous Hex"},
+//	  {"name": "tribe-location", "match": "^Tribe \\d{4},", "action": "keep"}
+//	]
+//
+// Lines that aren't kept by any rule are dropped.
+func ParseRules(data []byte) ([]*Rule, error) {
+	var rules []*Rule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if err := rule.compile(); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// LoadRules returns the rules from a JSON file.
+func LoadRules(path string) ([]*Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	rules, err := ParseRules(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rules, nil
+}
+
+// DefaultRules keeps the location, turn, movement, scout, and status lines
+// and drops everything else. Callers get a new copy so that they can add
+// their own rules.
+func DefaultRules() []*Rule {
+	rules := []*Rule{
+		// Courier 0987c1, , Current Hex = QQ 0408, (Previous Hex = QQ 0206)
+		{Name: "courier-location", Match: `^Courier \d{4}c[1-9],`, Action: Keep},
+		// Element 0987e1, , Current Hex = QQ 1601, (Previous Hex = QQ 1303)
+		{Name: "element-location", Match: `^Element \d{4}e[1-9],`, Action: Keep},
+		// Fleet 0987f1, , Current Hex = QQ 0818, (Previous Hex = QQ 0408)
+		{Name: "fleet-location", Match: `^Fleet \d{4}f[1-9],`, Action: Keep},
+		// Garrison 0987g1, , Current Hex = QQ 1408, (Previous Hex = QQ 1408)
+		{Name: "garrison-location", Match: `^Garrison \d{4}g[1-9],`, Action: Keep},
+		// Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)
+		{Name: "tribe-location", Match: `^Tribe \d{4},`, Action: Keep},
+		// Current Turn 899-12 (#0), Winter, FINE Next Turn 900-01 (#1), 28/11/2025
+		{Name: "current-turn", Match: `^Current Turn \d`, Action: Keep},
+		// Tribe Follows 0987
+		{Name: "tribe-follows", Match: `^Tribe Follows`, Action: Keep},
+		// Tribe Movement: Move N-GH, \N-JH, LJm NW,\N-JH, LJm SW, N,,River N NE\
+		{Name: "tribe-movement", Match: `^Tribe Movement: Move`, Action: Keep},
+		// Scout 1:Scout N-PR, , L SW,River N NE\,No Ford on River to N of HEX, Nothing of interest found
+		{Name: "scout", Match: `^Scout [1-8]:Scout `, Action: Keep},
+		// 0987c1 Status: PRAIRIE, O SW, L NE 0987, 1987
+		{Name: "status", Match: `^\d{4}([cefg]\d)? Status:`, Action: Keep},
+	}
+	for _, rule := range rules {
+		if err := rule.compile(); err != nil {
+			panic(fmt.Sprintf("assert(%v == nil)", err))
+		}
+	}
+	return rules
+}
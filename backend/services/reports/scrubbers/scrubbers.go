@@ -1,10 +1,15 @@
 // Copyright (c) 2025 Michael D Henderson. All rights reserved.
 
+// Package scrubbers removes the lines from a turn report that the
+// parser doesn't need. The lines to keep are chosen by an ordered list
+// of rules (see rules.go); every decision can be recorded for an audit.
 package scrubbers
 
 import (
 	"bytes"
+	"fmt"
 	"regexp"
+	"sort"
 )
 
 var (
@@ -12,141 +17,144 @@ var (
 	reRunOfTabs   = regexp.MustCompile(`\t+`)
 )
 
+// Scrub runs the default rules against the input.
+// If patchNA is set, "Previous Hex = N/A" is replaced with the current
+// hex for units that didn't move.
 func Scrub(input [][]byte, patchNA bool) [][]byte {
-	var lines [][]byte
-	for _, line := range input {
-		line = bytes.TrimSpace(line)
-		line = reRunOfTabs.ReplaceAll(line, []byte{' '})
-		line = reRunOfSpaces.ReplaceAll(line, []byte{' '})
-		if acceptUnitLocationLine(line) {
-			if patchNA {
-				// todo - this introduces a bug if the unit has movement
-				line = rePatchNA.ReplaceAll(line, []byte(`Current Hex = $1, (Previous Hex = $1)`))
-			}
-			lines = append(lines, line)
-		} else if acceptCurrentTurnLine(line) {
-			lines = append(lines, line)
-		} else if acceptUnitMovementLine(line) {
-			lines = append(lines, line)
-		} else if acceptScoutLine(line) {
-			lines = append(lines, line)
-		} else if acceptStatusLine(line) {
-			lines = append(lines, line)
-		}
-	}
+	lines, _ := New(DefaultRules(), patchNA).Scrub(input)
 	return lines
 }
 
-var (
-	// Courier 0987c1, , Current Hex = QQ 0408, (Previous Hex = QQ 0206)
-	reCourierLocationLine = regexp.MustCompile(`^Courier [\d]{4}c[1-9],`)
-	// Element 0987e1, , Current Hex = QQ 1601, (Previous Hex = QQ 1303)
-	reElementLocationLine = regexp.MustCompile(`^Element [\d]{4}e[1-9],`)
-	// Fleet 0987f1, , Current Hex = QQ 0818, (Previous Hex = QQ 0408)
-	reFleetLocationLine = regexp.MustCompile(`^Fleet [\d]{4}f[1-9],`)
-	// Garrison 0987g1, , Current Hex = QQ 1408, (Previous Hex = QQ 1408)
-	reGarrisonLocationLine = regexp.MustCompile(`^Garrison [\d]{4}g[1-9],`)
-	// Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)
-	reTribeLocationLine = regexp.MustCompile(`^Tribe [\d]{4},`)
+// Scrubber applies the rules to each line of a report.
+type Scrubber struct {
+	rules   []*Rule
+	patchNA bool
+}
 
-	// this will break things
-	rePatchNA = regexp.MustCompile(`Current Hex = ([A-Z]{2} \d{4}),.*\(Previous Hex = N/A\)`)
-)
+// New returns a scrubber. The rules must come from DefaultRules,
+// ParseRules, or LoadRules.
+func New(rules []*Rule, patchNA bool) *Scrubber {
+	return &Scrubber{rules: rules, patchNA: patchNA}
+}
 
-func acceptUnitLocationLine(line []byte) bool {
-	if reCourierLocationLine.Match(line) {
-		return true
-	} else if reElementLocationLine.Match(line) {
-		return true
-	} else if reFleetLocationLine.Match(line) {
-		return true
-	} else if reGarrisonLocationLine.Match(line) {
-		return true
+// Audit records a line that was dropped or rewritten and why.
+type Audit struct {
+	Line   int    // line number in the input, starting at 1
+	Rule   string // name of the rule, empty if no rule matched
+	Action Action
+	Input  string
+	Output string // the rewritten line; empty if dropped
+}
+
+func (a *Audit) String() string {
+	rule := a.Rule
+	if rule == "" {
+		rule = "no rule matched"
+	}
+	if a.Action == Drop {
+		return fmt.Sprintf("%d: drop: %s: %q", a.Line, rule, a.Input)
 	}
-	return reTribeLocationLine.Match(line)
+	return fmt.Sprintf("%d: %s: %s: %q => %q", a.Line, a.Action, rule, a.Input, a.Output)
 }
 
-var (
-	// Current Turn 899-12 (#0), Winter, FINE Next Turn 900-01 (#1), 28/11/2025
-	// Current Turn 900-01 (#1), Spring, FINE
-	reCurrentTurnLine = regexp.MustCompile(`^Current Turn \d`)
-)
+// Scrub returns the lines that were kept along with the audit of the
+// lines that were dropped or rewritten. Runs of spaces and tabs are
+// collapsed and the line is trimmed before the rules are checked.
+func (s *Scrubber) Scrub(input [][]byte) ([][]byte, []*Audit) {
+	var lines [][]byte
+	var audit []*Audit
 
-func acceptCurrentTurnLine(line []byte) bool {
-	return reCurrentTurnLine.Match(line)
-}
+	// the N/A patch waits for the end of the unit's section since
+	// the movement lines come after the location line.
+	pending, pendingLine, moved := -1, 0, false
+	patch := func() {
+		if pending != -1 && !moved {
+			line := rePatchNA.ReplaceAll(lines[pending], []byte(`Current Hex = $1, (Previous Hex = $1)`))
+			audit = append(audit, &Audit{Line: pendingLine, Rule: "patch-na", Action: Rewrite, Input: string(lines[pending]), Output: string(line)})
+			lines[pending] = line
+		}
+		pending, moved = -1, false
+	}
 
-var (
-	reTribeFollows = regexp.MustCompile(`^Tribe Follows`)
-	// Tribe Movement: Move
-	// Tribe Movement: Move \
-	// Tribe Movement: Move failed due to Insufficient capacity to carry
-	// Tribe Movement: Move N-GH, \N-JH, LJm NW,\N-JH, LJm SW, N,,River N NE\
-	// Tribe Movement: Move SE-PR, \SE-CH, ,River SE S, The Settlement\\
-	reTribeMovement = regexp.MustCompile(`^Tribe Movement: Move`)
-)
+	for n, line := range input {
+		line = bytes.TrimSpace(line)
+		line = reRunOfTabs.ReplaceAll(line, []byte{' '})
+		line = reRunOfSpaces.ReplaceAll(line, []byte{' '})
+		if s.patchNA {
+			if isUnitLocationLine(line) {
+				patch()
+			} else if hasMovement(line) {
+				moved = true
+			}
+		}
 
-func acceptUnitMovementLine(line []byte) bool {
-	if reTribeFollows.Match(line) {
-		return true
+		keep, decided := false, false
+		for _, rule := range s.rules {
+			if !rule.re.Match(line) {
+				continue
+			}
+			if rule.Action == Rewrite {
+				output := rule.re.ReplaceAll(line, []byte(rule.Replace))
+				if !bytes.Equal(output, line) {
+					audit = append(audit, &Audit{Line: n + 1, Rule: rule.Name, Action: Rewrite, Input: string(line), Output: string(output)})
+					line = output
+				}
+				continue
+			}
+			keep, decided = rule.Action == Keep, true
+			if !keep {
+				audit = append(audit, &Audit{Line: n + 1, Rule: rule.Name, Action: Drop, Input: string(line)})
+			}
+			break
+		}
+		if !decided {
+			// fell off the end of the rules
+			audit = append(audit, &Audit{Line: n + 1, Action: Drop, Input: string(line)})
+		}
+		if !keep {
+			continue
+		}
+		if s.patchNA && isUnitLocationLine(line) && rePatchNA.Match(line) {
+			pending, pendingLine = len(lines), n+1
+		}
+		lines = append(lines, line)
 	}
-	return reTribeMovement.Match(line)
+	patch()
+
+	// the N/A patch is recorded late
+	sort.SliceStable(audit, func(i, j int) bool {
+		return audit[i].Line < audit[j].Line
+	})
+	return lines, audit
 }
 
 var (
-	// Scout 1:Scout ,Can't Move on Lake to NW of HEX, Nothing of interest found
-	// Scout 1:Scout ,Can't Move on Ocean to N of HEX, Nothing of interest found
-	// Scout 1:Scout ,No Ford on River to NE of HEX, Nothing of interest found
-	// Scout 1:Scout N-GH, \N-JH, LJm NW,\,Not enough M.P's to move to N into JUNGLE HILLS, Nothing of interest found
-	// Scout 1:Scout N-CH, \N-PR, , O N\,Can't Move on Ocean to N of HEX, Nothing of interest found
-	// Scout 1:Scout N-GH, \N-PR, \N-PR, , O NW, N\,Can't Move on Ocean to N of HEX, Nothing of interest found
-	// Scout 1:Scout N-LCM, Lcm NW,, O NE, SE, N,Find Copper Ore\,Can't Move on Ocean to N of HEX, Nothing of interest found
-	// Scout 1:Scout NW-GH, , L SW, NW, N, S,Find Copper Ore\,Can't Move on Lake to NW of HEX,
-	// Scout 1:Scout N-PR, , L SW,River N NE\,No Ford on River to N of HEX, Nothing of interest found
-	// Scout 1:Scout NE-RH, ,River SE S\NE-RH, , O NE, SE, N,River S\,Can't Move on Ocean to NE of HEX, Nothing of interest found
-	// Scout 1:Scout NE-JH, \N-PR, LJm NW,,River NW\ Nothing of interest found
-	// Scout 1:Scout SW-CH, ,River SE S SW\,No Ford on River to SW of HEX, Nothing of interest found
-	// Scout 1:Scout S-CH, Lcm NE,\S-PR, ,River SE,Ford S\S-PR, ,River SW NW,Ford N\,Not enough M.P's to move to S into PRAIRIE, Nothing of interest found
-	// Scout 1:Scout SE-PR, \SE-PR, \SE-PR, \-, 0987, 1987\SE-PR, \SE-PR, \,Not enough M.P's to move to SE into PRAIRIE, Nothing of interest found
-	// Scout 2:Scout SE-PR, \SE-CH, ,River SE S, The Settlement\,No Ford on River to SE of HEX, Nothing of interest found
-	// Scout 2:Scout N-RH, \NE-BF, , O NE, Mysterious NPC\,Can't Move on Ocean to NE of HEX, Nothing of interest found
-	// Scout 5:Scout S-PR, \SE-GH, \SE-JH, LJm NE,,River SE,Find Coal\,No Ford on River to SE of HEX, Nothing of interest found
-	// Scout 8:Scout SE-PR, \S-GH, \S-CH, Lcm SE,\-, 1520, 0520\,Not enough M.P's to move to S into CONIFER HILLS, Patrolled and found 1987, 0987
-	reScoutLine = regexp.MustCompile(`^Scout [1-8]:Scout `)
-)
+	// Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)
+	reUnitLocationLine = regexp.MustCompile(`^(Courier \d{4}c[1-9]|Element \d{4}e[1-9]|Fleet \d{4}f[1-9]|Garrison \d{4}g[1-9]|Tribe \d{4}),`)
 
-func acceptScoutLine(line []byte) bool {
-	return reScoutLine.Match(line)
-}
+	// Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = N/A)
+	rePatchNA = regexp.MustCompile(`Current Hex = ([A-Z]{2} \d{4}),.*\(Previous Hex = N/A\)`)
 
-var (
-	// 0987 Status: PRAIRIE, 0987
-	// 0987 Status: PRAIRIE, L NW 0987
-	// 0987 Status: PRAIRIE, O NE, SE, L SW 0987
-	// 0987 Status: CONIFER HILLS, Lcm SW, 0987
-	// 0987 Status: CONIFER HILLS, Lcm SE, 1987, 0987
-	// 0987 Status: PRAIRIE,,River SW NW 0987
-	// 0987 Status: JUNGLE HILLS, LJm NE, SE, N,,Pass NE 0987
-	// 0987 Status: CONIFER HILLS, The Settlement,,River SE S 0987
-	// 0987 Status: GRASSY HILLS, Copper Ore, L NE, N 0987
-	// 1987 Status: PRAIRIE, O SW, L NE 0987, 1987
-	// 2987 Status: GRASSY HILLS, 2987, 0987
-	reCourierStatusLine  = regexp.MustCompile(`^[\d]{4}c[\d] Status:`)
-	reElementStatusLine  = regexp.MustCompile(`^[\d]{4}e[\d] Status:`)
-	reFleetStatusLine    = regexp.MustCompile(`^[\d]{4}f[\d] Status:`)
-	reGarrisonStatusLine = regexp.MustCompile(`^[\d]{4}g[\d] Status:`)
-	reTribeStatusLine    = regexp.MustCompile(`^[\d]{4} Status:`)
+	// Tribe Movement: Move N-GH, \N-JH, LJm NW,\
+	// CALM NE Fleet Movement: Move NE-O, ...
+	reMovement = regexp.MustCompile(`Movement: Move(.*)$`)
 )
 
-func acceptStatusLine(line []byte) bool {
-	if reCourierStatusLine.Match(line) {
-		return true
-	} else if reElementStatusLine.Match(line) {
-		return true
-	} else if reFleetStatusLine.Match(line) {
-		return true
-	} else if reGarrisonStatusLine.Match(line) {
+func isUnitLocationLine(line []byte) bool {
+	return reUnitLocationLine.Match(line)
+}
+
+// hasMovement returns true if the line shows that the unit left its hex.
+// "Tribe Movement: Move" with no steps, or a move that failed before the
+// first step, is not movement.
+func hasMovement(line []byte) bool {
+	if bytes.HasPrefix(line, []byte("Tribe Follows")) || bytes.HasPrefix(line, []byte("Tribe Goes to")) {
 		return true
 	}
-	return reTribeStatusLine.Match(line)
+	m := reMovement.FindSubmatch(line)
+	if m == nil {
+		return false
+	}
+	steps := bytes.Trim(m[1], ` \`)
+	return len(steps) != 0 && !bytes.HasPrefix(steps, []byte("failed"))
 }
@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package scrubbers_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/services/reports/scrubbers"
+)
+
+func split(s string) [][]byte {
+	return bytes.Split([]byte(s), []byte{'\n'})
+}
+
+func join(lines [][]byte) string {
+	return string(bytes.Join(lines, []byte{'\n'}))
+}
+
+func TestPatchNA(t *testing.T) {
+	input := "Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = N/A)\n" +
+		"Current Turn 899-12 (#0), Winter, FINE\n" +
+		"Tribe Movement: Move \\\n" +
+		"Humans 1000\n" +
+		"Element 0987e1, , Current Hex = QQ 1203, (Previous Hex = N/A)\n" +
+		"Tribe Movement: Move N-PR, \\\n" +
+		"Courier 0987c1, , Current Hex = QQ 1203, (Previous Hex = N/A)\n" +
+		"Tribe Movement: Move failed due to Insufficient capacity to carry"
+	want := "Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)\n" +
+		"Current Turn 899-12 (#0), Winter, FINE\n" +
+		"Tribe Movement: Move \\\n" +
+		// the element moved, so the previous hex is not the current hex
+		"Element 0987e1, , Current Hex = QQ 1203, (Previous Hex = N/A)\n" +
+		"Tribe Movement: Move N-PR, \\\n" +
+		"Courier 0987c1, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)\n" +
+		"Tribe Movement: Move failed due to Insufficient capacity to carry"
+	lines, audit := scrubbers.New(scrubbers.DefaultRules(), true).Scrub(split(input))
+	if got := join(lines); got != want {
+		t.Errorf("patch: want\n%s\ngot\n%s", want, got)
+	}
+	var got []string
+	for _, a := range audit {
+		got = append(got, a.String())
+	}
+	wantAudit := []string{
+		`1: rewrite: patch-na: "Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = N/A)" => "Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)"`,
+		`4: drop: no rule matched: "Humans 1000"`,
+		`7: rewrite: patch-na: "Courier 0987c1, , Current Hex = QQ 1203, (Previous Hex = N/A)" => "Courier 0987c1, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(wantAudit, "\n") {
+		t.Errorf("audit: want\n%s\ngot\n%s", strings.Join(wantAudit, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestRules(t *testing.T) {
+	rules, err := scrubbers.ParseRules([]byte(`[
+		{"name": "drop-goods", "match": "^Humans ", "action": "drop"},
+		{"name": "fix-typo", "match": "Prevous Hex", "action": "rewrite", "replace": "Previous Hex"},
+		{"name": "tribe-location", "match": "^Tribe \\d{4},", "action": "keep"}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := "Tribe  0987, , Current Hex = QQ 1203, (Prevous Hex = QQ 1203)\n" +
+		"Humans 1000\n" +
+		"Tribe Movement: Move"
+	lines, audit := scrubbers.New(rules, false).Scrub(split(input))
+	if want, got := "Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)", join(lines); got != want {
+		t.Errorf("lines: want %q, got %q", want, got)
+	}
+	if len(audit) != 3 || audit[0].Rule != "fix-typo" || audit[1].Rule != "drop-goods" || audit[2].Rule != "" {
+		t.Errorf("audit: got %v", audit)
+	}
+
+	for _, bad := range []string{
+		`[{"name": "x", "match": "(", "action": "keep"}]`,
+		`[{"name": "x", "match": "a", "action": "ignore"}]`,
+		`[{"name": "x", "match": "a", "action": "keep", "replace": "b"}]`,
+		`[{"match": "a", "action": "keep"}]`,
+	} {
+		if _, err := scrubbers.ParseRules([]byte(bad)); err == nil {
+			t.Errorf("%s: want error, got nil", bad)
+		}
+	}
+}
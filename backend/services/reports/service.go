@@ -0,0 +1,291 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+// Package reports implements a service for parsing stored turn reports.
+//
+// Every parse is recorded with the name and version of the parser that
+// produced it. When a parser is upgraded, the stale parses are re-parsed.
+package reports
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/maloquacious/semver"
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/parsers"
+	"github.com/playbymail/ottoapp/backend/services/dag"
+	"github.com/playbymail/ottoapp/backend/stores/blobs"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+// Service provides turn report parsing operations.
+type Service struct {
+	db       *sqlite.DB
+	registry *parsers.Registry
+	blobs    *blobs.Blobs
+	renderer Renderer
+}
+
+// New returns a new service. If registry is nil, the default registry is used.
+// If renderer is nil, maps are rendered by the ottomap command on the PATH.
+func New(db *sqlite.DB, blobStore *blobs.Blobs, registry *parsers.Registry, renderer Renderer) *Service {
+	if blobStore == nil {
+		blobStore = blobs.New(db)
+	}
+	if registry == nil {
+		registry = parsers.DefaultRegistry()
+	}
+	if renderer == nil {
+		renderer = &Ottomap{Path: "ottomap"}
+	}
+	return &Service{db: db, registry: registry, blobs: blobStore, renderer: renderer}
+}
+
+// Parse is the recorded parse of a turn report extract.
+type Parse struct {
+	DocumentID   domains.ID
+	DocumentName string
+	Game         string
+	ClanNo       int
+	Format       parsers.Format
+	Parser       string
+	Version      semver.Version
+	ContentsHash string // hash of the contents that were parsed
+	Error        string // empty if the parse succeeded
+	Output       []byte // JSON encoded parser output
+	OutputHash   string
+	ParsedAt     time.Time
+}
+
+// ParseStatus reports whether the stored parse of an extract is current.
+type ParseStatus struct {
+	DocumentID   domains.ID
+	DocumentName string
+	Game         string
+	ClanNo       int
+	Format       parsers.Format
+	Parser       string         // parser that produced the stored parse; empty if never parsed
+	Version      semver.Version // version of that parser
+	Current      *parsers.Dialect
+	OutputHash   string
+	Stale        bool
+	Reason       string // why the parse is stale
+}
+
+// ParseDocument parses a turn report extract and records the result.
+// Parse errors are recorded, not returned; the caller should check Parse.Error.
+func (s *Service) ParseDocument(documentId domains.ID, format parsers.Format, quiet, verbose, debug bool) (*Parse, error) {
+	if debug {
+		log.Printf("[reports] ParseDocument(%d, %q)\n", documentId, format)
+	}
+	p, err := s.parseDocument(documentId, format, verbose)
+	if err != nil {
+		return nil, err
+	}
+	err = s.storeParse(p)
+	if err != nil {
+		return nil, err
+	}
+	if debug {
+		log.Printf("[reports] %s: parsed by %s %s\n", p.DocumentName, p.Parser, p.Version.Core())
+	}
+	return p, nil
+}
+
+// parseDocument loads and parses an extract without storing the result.
+// It is safe to call from multiple goroutines.
+func (s *Service) parseDocument(documentId domains.ID, format parsers.Format, verbose bool) (*Parse, error) {
+	if !format.IsValid() {
+		return nil, parsers.ErrUnknownFormat
+	}
+	row, err := s.db.Queries().ReadReportExtractParse(s.db.Context(), int64(documentId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domains.ErrNotExists
+		}
+		log.Printf("[reports] ParseDocument(%d) %v\n", documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+
+	p := &Parse{
+		DocumentID:   domains.ID(row.DocumentID),
+		DocumentName: row.DocumentName,
+		Game:         row.Code,
+		ClanNo:       int(row.Clan),
+		Format:       format,
+		ContentsHash: row.ContentsHash,
+		ParsedAt:     time.Now().UTC(),
+	}
+	contents, err := s.blobs.Get(s.db.Context(), s.db.Queries(), row.ContentsHash)
+	if err != nil {
+		log.Printf("[reports] ParseDocument(%d) %v\n", documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	result, err := s.registry.Parse(format, row.Code, row.DocumentName, domains.TurnIdFromName(row.DocumentName), contents)
+	if errors.Is(err, parsers.ErrNoDialect) {
+		return nil, err
+	} else if err != nil {
+		dialect, _ := s.registry.Lookup(format, row.Code)
+		p.Parser, p.Version = dialect.Name, dialect.Version
+		p.Error = err.Error()
+		if verbose {
+			log.Printf("[reports] %s: %s %s: %v\n", row.DocumentName, p.Parser, p.Version.Core(), err)
+		}
+	} else {
+		p.Parser, p.Version = result.Parser, result.Version
+		p.Output, err = json.Marshal(result.Output)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("%s: marshal output", row.DocumentName), err)
+		}
+	}
+	sum := sha256.Sum256(p.Output)
+	p.OutputHash = hex.EncodeToString(sum[:])
+	return p, nil
+}
+
+// storeParse records the parse, replacing any earlier parse of the document.
+// If the output differs from the earlier parse, the maps drawn from the
+// extract are marked stale in the document lineage.
+func (s *Service) storeParse(p *Parse) error {
+	ctx := s.db.Context()
+	tx, err := s.db.Stdlib().BeginTx(ctx, nil)
+	if err != nil {
+		log.Printf("[reports] storeParse(%d) %v\n", p.DocumentID, err)
+		return errors.Join(domains.ErrDatabaseError, err)
+	}
+	defer tx.Rollback() // rollback if we return early; harmless after commit
+	qtx := s.db.Queries().WithTx(tx)
+
+	// a first parse has nothing to compare with
+	changed := false
+	if prev, err := qtx.ReadReportParse(ctx, int64(p.DocumentID)); err == nil {
+		changed = prev.OutputHash != p.OutputHash
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("[reports] storeParse(%d) %v\n", p.DocumentID, err)
+		return errors.Join(domains.ErrDatabaseError, err)
+	}
+
+	err = qtx.UpsertReportParse(ctx, sqlc.UpsertReportParseParams{
+		DocumentID:   int64(p.DocumentID),
+		ReportFormat: string(p.Format),
+		ParserName:   p.Parser,
+		ParserMajor:  int64(p.Version.Major),
+		ParserMinor:  int64(p.Version.Minor),
+		ParserPatch:  int64(p.Version.Patch),
+		ContentsHash: p.ContentsHash,
+		ParseError:   p.Error,
+		Output:       p.Output,
+		OutputHash:   p.OutputHash,
+		CreatedAt:    p.ParsedAt.Unix(),
+		UpdatedAt:    p.ParsedAt.Unix(),
+	})
+	if err != nil {
+		log.Printf("[reports] storeParse(%d) %v\n", p.DocumentID, err)
+		return errors.Join(domains.ErrDatabaseError, err)
+	}
+	if changed {
+		if err = dag.MarkStale(ctx, qtx, int64(p.DocumentID), p.ParsedAt.Unix()); err != nil {
+			log.Printf("[reports] storeParse(%d) %v\n", p.DocumentID, err)
+			return errors.Join(domains.ErrDatabaseError, err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		log.Printf("[reports] storeParse(%d) %v\n", p.DocumentID, err)
+		return errors.Join(domains.ErrDatabaseError, err)
+	}
+	return nil
+}
+
+// ReadExtract returns the name and contents of a stored turn report extract.
+func (s *Service) ReadExtract(documentId domains.ID) (string, []byte, error) {
+	row, err := s.db.Queries().ReadReportExtractParse(s.db.Context(), int64(documentId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, domains.ErrNotExists
+		}
+		log.Printf("[reports] ReadExtract(%d) %v\n", documentId, err)
+		return "", nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	contents, err := s.blobs.Get(s.db.Context(), s.db.Queries(), row.ContentsHash)
+	if err != nil {
+		log.Printf("[reports] ReadExtract(%d) %v\n", documentId, err)
+		return "", nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	return row.DocumentName, contents, nil
+}
+
+// ReadParse returns the recorded parse for a document.
+func (s *Service) ReadParse(documentId domains.ID) (*Parse, error) {
+	row, err := s.db.Queries().ReadReportParse(s.db.Context(), int64(documentId))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domains.ErrNotExists
+		}
+		log.Printf("[reports] ReadParse(%d) %v\n", documentId, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	return &Parse{
+		DocumentID:   domains.ID(row.DocumentID),
+		Format:       parsers.Format(row.ReportFormat),
+		Parser:       row.ParserName,
+		Version:      semver.Version{Major: int(row.ParserMajor), Minor: int(row.ParserMinor), Patch: int(row.ParserPatch)},
+		ContentsHash: row.ContentsHash,
+		Error:        row.ParseError,
+		Output:       row.Output,
+		OutputHash:   row.OutputHash,
+		ParsedAt:     time.Unix(row.UpdatedAt, 0).UTC(),
+	}, nil
+}
+
+// ReadParseStatus returns the status of the parse for every stored extract.
+//
+// A parse is stale if the extract has never been parsed, if the contents
+// changed since the parse, or if the dialect for the format and game is
+// not the parser (or a newer version of the parser) that made the parse.
+func (s *Service) ReadParseStatus() ([]*ParseStatus, error) {
+	rows, err := s.db.Queries().ReadReportExtractParses(s.db.Context())
+	if err != nil {
+		log.Printf("[reports] ReadParseStatus() %v\n", err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	var list []*ParseStatus
+	for _, row := range rows {
+		ps := &ParseStatus{
+			DocumentID:   domains.ID(row.DocumentID),
+			DocumentName: row.DocumentName,
+			Game:         row.Code,
+			ClanNo:       int(row.Clan),
+			Format:       parsers.DefaultFormat,
+			OutputHash:   row.OutputHash.String,
+		}
+		if row.ReportFormat.Valid && parsers.Format(row.ReportFormat.String).IsValid() {
+			ps.Format = parsers.Format(row.ReportFormat.String)
+		}
+		ps.Current, err = s.registry.Lookup(ps.Format, ps.Game)
+		if err != nil {
+			return nil, err
+		}
+		if !row.ParserName.Valid {
+			ps.Stale, ps.Reason = true, "never parsed"
+		} else {
+			ps.Parser = row.ParserName.String
+			ps.Version = semver.Version{Major: int(row.ParserMajor.Int64), Minor: int(row.ParserMinor.Int64), Patch: int(row.ParserPatch.Int64)}
+			if ps.Parser != ps.Current.Name {
+				ps.Stale, ps.Reason = true, fmt.Sprintf("parser changed from %s to %s", ps.Parser, ps.Current.Name)
+			} else if ps.Version.Less(ps.Current.Version) {
+				ps.Stale, ps.Reason = true, fmt.Sprintf("parser upgraded from %s to %s", ps.Version.Core(), ps.Current.Version.Core())
+			} else if row.ParsedHash.String != row.ContentsHash {
+				ps.Stale, ps.Reason = true, "contents changed"
+			}
+		}
+		list = append(list, ps)
+	}
+	return list, nil
+}
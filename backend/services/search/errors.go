@@ -0,0 +1,13 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package search
+
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+const (
+	ErrEmptyQuery = Error("empty query")
+)
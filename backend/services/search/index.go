@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+// maxLines is the most lines indexed for one extract.
+// The rowid of a line is (document_id << 20) + line_no.
+const maxLines = 1<<20 - 1
+
+var (
+	// unit sections start with "{kind} {unitId}, "
+	reSection = regexp.MustCompile(`^(?:Courier|Element|Fleet|Garrison|Tribe) (\d{4}(?:[cefg][1-9])?),`)
+)
+
+// Index replaces the indexed lines for the document with the lines from
+// the contents. Blank lines are skipped. Each line is tagged with the unit
+// section it is in. Callers should run this in the same transaction that
+// updates the document's contents.
+func Index(ctx context.Context, q *sqlc.Queries, documentId int64, contents []byte) error {
+	if err := q.DeleteReportExtractLines(ctx, documentId); err != nil {
+		return err
+	}
+	var unitId string
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNo := 1; scanner.Scan() && lineNo <= maxLines; lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if m := reSection.FindStringSubmatch(line); m != nil {
+			unitId = m[1]
+		}
+		err := q.CreateReportExtractLine(ctx, sqlc.CreateReportExtractLineParams{
+			DocumentID: documentId,
+			LineNo:     int64(lineNo),
+			Line:       line,
+			UnitID:     unitId,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// Unindex removes the indexed lines for the document.
+// It is not an error if the document isn't indexed.
+func Unindex(ctx context.Context, q *sqlc.Queries, documentId int64) error {
+	return q.DeleteReportExtractLines(ctx, documentId)
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package search_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/services/search"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite"
+)
+
+func TestIndex(t *testing.T) {
+	ctx := context.Background()
+	db, err := sqlite.OpenTempDB(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	q := db.Queries()
+
+	extract := []byte("Tribe 0987, , Current Hex = QQ 1203, (Previous Hex = QQ 1203)\n" +
+		"\n" +
+		"Current Turn 899-12 (#0), Winter, FINE\n" +
+		"Courier 0987c1, , Current Hex = QQ 1204, (Previous Hex = QQ 1203)\n" +
+		"Scout 1:Scout N-PR, Copper mine\n")
+	if err := search.Index(ctx, q, 7, extract); err != nil {
+		t.Fatalf("index: %v", err)
+	}
+	// indexing again replaces the lines instead of adding to them
+	if err := search.Index(ctx, q, 7, extract); err != nil {
+		t.Fatalf("reindex: %v", err)
+	}
+
+	type line struct {
+		unitId string
+		lineNo int
+	}
+	var got []line
+	rows, err := db.Stdlib().QueryContext(ctx, `SELECT unit_id, line_no FROM report_extract_lines WHERE document_id = 7 ORDER BY line_no`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var l line
+		if err := rows.Scan(&l.unitId, &l.lineNo); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, l)
+	}
+	_ = rows.Close()
+	want := []line{{"0987", 1}, {"0987", 3}, {"0987c1", 4}, {"0987c1", 5}}
+	if len(got) != len(want) {
+		t.Fatalf("lines: got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d: got %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if err := search.Unindex(ctx, q, 7); err != nil {
+		t.Fatalf("unindex: %v", err)
+	}
+	var n int
+	if err := db.Stdlib().QueryRowContext(ctx, `SELECT count(*) FROM report_extract_lines`).Scan(&n); err != nil {
+		t.Fatal(err)
+	} else if n != 0 {
+		t.Errorf("unindex: got %d lines, want 0", n)
+	}
+}
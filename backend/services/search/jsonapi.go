@@ -0,0 +1,32 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package search
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/jsonapi"
+)
+
+// ResultView is the JSON:API view for a line that matched a search.
+type ResultView struct {
+	ID           string `jsonapi:"primary,search-result"` // singular when sending a payload
+	DocumentId   string `jsonapi:"attr,document-id"`
+	DocumentName string `jsonapi:"attr,document-name"`
+	Game         string `jsonapi:"attr,game"`
+	Clan         string `jsonapi:"attr,clan"`    // four-digit clan number
+	Turn         string `jsonapi:"attr,turn"`    // YYYY-MM
+	Unit         string `jsonapi:"attr,unit"`    // unit section the line is in
+	Line         int    `jsonapi:"attr,line"`    // starts at 1
+	Snippet      string `jsonapi:"attr,snippet"` // HTML escaped, matches are wrapped in <mark>
+}
+
+// JSONAPILinks implements the jsonapi.Linkable interface for search-result-links
+func (r *ResultView) JSONAPILinks() *jsonapi.Links {
+	return &jsonapi.Links{
+		"document": fmt.Sprintf("/api/documents/%s", r.DocumentId),
+		"contents": jsonapi.Link{
+			Href: fmt.Sprintf("/api/documents/%s/contents", r.DocumentId),
+		},
+	}
+}
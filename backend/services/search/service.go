@@ -0,0 +1,163 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+// Package search implements full-text search over turn report extracts.
+//
+// The index is a SQLite FTS5 table with one row per line of the latest
+// revision of each extract. The documents service keeps it up to date
+// when extracts are created, replaced, synced, or reverted.
+package search
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/services/authz"
+	"github.com/playbymail/ottoapp/backend/stores/blobs"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+// Service provides search operations.
+type Service struct {
+	db       *sqlite.DB
+	authzSvc *authz.Service
+	blobs    *blobs.Blobs
+}
+
+func New(db *sqlite.DB, authzSvc *authz.Service, blobStore *blobs.Blobs) *Service {
+	if authzSvc == nil {
+		authzSvc = authz.New(db)
+	}
+	if blobStore == nil {
+		blobStore = blobs.New(db)
+	}
+	return &Service{db: db, authzSvc: authzSvc, blobs: blobStore}
+}
+
+// Search returns the lines that contain all the terms in the query, best
+// match first. It searches the actor's extracts and the extracts that other
+// users have shared with the actor. Terms are separated by spaces; use
+// double quotes to search for a phrase. Matching ignores case.
+//
+// Page numbers start at 1. A page size of 0 returns the first 25 results.
+func (s *Service) Search(actor *domains.Actor, query string, pageNumber, pageSize int, quiet, verbose, debug bool) ([]*ResultView, error) {
+	if !actor.IsValid() {
+		return nil, domains.ErrNotAuthorized
+	}
+	match := matchQuery(query)
+	if match == "" {
+		return nil, ErrEmptyQuery
+	}
+	if pageNumber < 1 {
+		pageNumber = 1
+	}
+	if pageSize < 1 {
+		pageSize = 25
+	}
+	if debug {
+		log.Printf("[search] Search(%d, %q) %q\n", actor.ID, query, match)
+	}
+	rows, err := s.db.Queries().SearchReportExtractsByUser(s.db.Context(), sqlc.SearchReportExtractsByUserParams{
+		Query:  match,
+		UserID: int64(actor.ID),
+		Now:    sql.NullInt64{Int64: time.Now().UTC().Unix(), Valid: true},
+		Limit:  int64(pageSize),
+		Offset: int64((pageNumber - 1) * pageSize),
+	})
+	if err != nil {
+		log.Printf("[search] Search(%d, %q) %v\n", actor.ID, query, err)
+		return nil, errors.Join(domains.ErrDatabaseError, err)
+	}
+	list := []*ResultView{}
+	for _, row := range rows {
+		list = append(list, &ResultView{
+			ID:           fmt.Sprintf("%d-%d", row.DocumentID, row.LineNo),
+			DocumentId:   fmt.Sprintf("%d", row.DocumentID),
+			DocumentName: row.DocumentName,
+			Game:         row.Code,
+			Clan:         fmt.Sprintf("%04d", row.Clan),
+			Turn:         domains.TurnIdFromName(row.DocumentName),
+			Unit:         row.UnitID,
+			Line:         int(row.LineNo),
+			Snippet:      markSnippet(row.Snippet),
+		})
+	}
+	return list, nil
+}
+
+// Reindex rebuilds the index for every report extract.
+// Returns the number of extracts indexed.
+func (s *Service) Reindex(actor *domains.Actor, quiet, verbose, debug bool) (int, error) {
+	if !actor.IsSysop() {
+		return 0, domains.ErrNotAuthorized
+	}
+	ctx := s.db.Context()
+	rows, err := s.db.Queries().ReadReportExtractsToIndex(ctx)
+	if err != nil {
+		log.Printf("[search] Reindex: %v\n", err)
+		return 0, errors.Join(domains.ErrDatabaseError, err)
+	}
+	for _, row := range rows {
+		contents, err := s.blobs.Get(ctx, s.db.Queries(), row.ContentsHash)
+		if err != nil {
+			log.Printf("[search] Reindex: %d: %v\n", row.DocumentID, err)
+			return 0, errors.Join(domains.ErrDatabaseError, err)
+		}
+		err = func() error {
+			tx, err := s.db.Stdlib().BeginTx(ctx, nil)
+			if err != nil {
+				return err
+			}
+			defer tx.Rollback() // rollback if we return early; harmless after commit
+			if err := Index(ctx, s.db.Queries().WithTx(tx), row.DocumentID, contents); err != nil {
+				return err
+			}
+			return tx.Commit()
+		}()
+		if err != nil {
+			log.Printf("[search] Reindex: %d: %v\n", row.DocumentID, err)
+			return 0, errors.Join(domains.ErrDatabaseError, err)
+		}
+		if verbose {
+			log.Printf("[search] Reindex: %d: indexed\n", row.DocumentID)
+		}
+	}
+	return len(rows), nil
+}
+
+// matchQuery converts the user's query into an FTS5 query. Every term
+// and phrase is quoted so that punctuation and FTS5 keywords in the query
+// are searched for instead of being treated as syntax.
+func matchQuery(query string) string {
+	var terms []string
+	for n, part := range strings.Split(query, `"`) {
+		if n%2 == 1 { // inside quotes
+			if phrase := strings.Join(strings.Fields(part), " "); phrase != "" {
+				terms = append(terms, quote(phrase))
+			}
+			continue
+		}
+		for _, term := range strings.Fields(part) {
+			terms = append(terms, quote(term))
+		}
+	}
+	return strings.Join(terms, " ")
+}
+
+func quote(term string) string {
+	return `"` + strings.ReplaceAll(term, `"`, `""`) + `"`
+}
+
+// markSnippet escapes the snippet for HTML and wraps the matched terms,
+// which the query delimits with STX and ETX, in <mark> tags.
+func markSnippet(snippet string) string {
+	snippet = html.EscapeString(snippet)
+	snippet = strings.ReplaceAll(snippet, "\x02", "<mark>")
+	return strings.ReplaceAll(snippet, "\x03", "</mark>")
+}
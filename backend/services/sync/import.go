@@ -3,6 +3,7 @@
 package sync
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -15,6 +16,9 @@ import (
 	"github.com/mdhender/phrases/v2"
 	"github.com/playbymail/ottoapp/backend/domains"
 	"github.com/playbymail/ottoapp/backend/services/authz"
+	"github.com/playbymail/ottoapp/backend/services/documents"
+	"github.com/playbymail/ottoapp/backend/services/games"
+	"github.com/playbymail/ottoapp/backend/services/reports/office"
 	"github.com/playbymail/ottoapp/backend/stores/jsondb"
 	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
 )
@@ -96,6 +100,7 @@ func (s *Service) ImportGames(path string, codes ...string) error {
 			SetupTurn:   fmt.Sprintf("%04d-%02d", data.SetupTurn.Year, data.SetupTurn.Month),
 			ActiveTurn:  fmt.Sprintf("%04d-%02d", data.ActiveTurn.Year, data.ActiveTurn.Month),
 			OrdersDue:   data.OrdersDue.UTC().Unix(),
+			Timezone:    data.Timezone,
 			CreatedAt:   createdAt,
 			UpdatedAt:   updatedAt,
 		})
@@ -550,7 +555,15 @@ func (s *Service) ImportReportExtractFiles(path string, quiet, verbose, debug bo
 				UpdatedAt:  file.ModTime,
 			}
 			documentId, err := s.documentsSvc.ReplaceDocument(actor, &file.Clan, doc, quiet, false, debug)
-			if err != nil {
+			var reportErr *documents.ReportError
+			if errors.As(err, &reportErr) {
+				// reject the report but keep importing the others
+				log.Printf("sync: import: %s: skipping: %v\n", file.Path, documents.ErrInvalidReport)
+				for _, v := range reportErr.Violations {
+					log.Printf("sync: import: %s: %s\n", file.Path, v)
+				}
+				continue
+			} else if err != nil {
 				log.Printf("sync: import: ReplaceDocument(%q): %v\n", file.Path, err)
 				return err
 			}
@@ -561,6 +574,28 @@ func (s *Service) ImportReportExtractFiles(path string, quiet, verbose, debug bo
 		}
 	}
 
+	// update the game calendars from the turn lines in the reports.
+	// if the clans' reports disagree, log the problems and leave the calendar alone.
+	for _, game := range gamesList {
+		cal, err := s.gameSvc.SyncCalendar(game.ID, "", true, quiet, verbose, debug)
+		if errors.Is(err, games.ErrDueTimeNotSet) {
+			log.Printf("sync: import: game %q: calendar: %v: run game calendar with --due-time\n", game.Code, err)
+			continue
+		} else if errors.Is(err, games.ErrCalendarDisagreement) {
+			log.Printf("sync: import: game %q: calendar: %v\n", game.Code, err)
+			for _, d := range cal.Disagreements {
+				log.Printf("sync: import: game %q: calendar: %s\n", game.Code, d)
+			}
+			continue
+		} else if err != nil {
+			log.Printf("sync: import: game %q: calendar: %v\n", game.Code, err)
+			return err
+		}
+		if verbose && cal.HasChanges() {
+			log.Printf("sync: import: game %q: calendar: active turn %q, orders due %v, %d turns\n", game.Code, cal.NewActiveTurn, cal.NewOrdersDue, len(cal.NewTurns))
+		}
+	}
+
 	return nil
 }
 
@@ -644,7 +679,7 @@ func (s *Service) ImportTurnReportFiles(path string, quiet, verbose, debug bool)
 			if entry.IsDir() {
 				continue
 			}
-			// report file Name is {code}.{turnNo}.{clanNo}.docx
+			// report file Name is {code}.{turnNo}.{clanNo}.{ext}; the format comes from the contents
 			reportPath, reportName := turnReportFilePath, entry.Name()
 			matches := reClanReportFile.FindStringSubmatch(reportName)
 			if matches == nil {
@@ -721,13 +756,26 @@ func (s *Service) ImportTurnReportFiles(path string, quiet, verbose, debug bool)
 			log.Printf("sync: import: %s: %v\n", file.Path, err)
 			return err
 		}
+		// trust the contents, not the name of the file
+		var ext string
+		switch dt := office.DetectDocType(contents); dt {
+		case office.Docx:
+			ext = "docx"
+		case office.Odt:
+			ext = "odt"
+		case office.Text:
+			ext = "txt"
+		default:
+			log.Printf("sync: import: %s: contents are %s: skipping\n", file.Path, dt)
+			continue
+		}
 		doc := &domains.Document{
 			GameID:     file.Clan.GameID,
 			ClanId:     file.Clan.ClanID,
 			Turn:       file.Turn,
 			ClanNo:     file.Clan.ClanNo,
 			UnitId:     fmt.Sprintf("%4d", file.Clan.ClanNo),
-			Path:       fmt.Sprintf("%s.%s.%04d.docx", file.code, file.Turn, file.Clan.ClanNo),
+			Path:       fmt.Sprintf("%s.%s.%04d.%s", file.code, file.Turn, file.Clan.ClanNo, ext),
 			Type:       domains.TurnReportFile,
 			Contents:   contents,
 			ModifiedAt: file.ModTime,
@@ -749,11 +797,11 @@ func (s *Service) ImportTurnReportFiles(path string, quiet, verbose, debug bool)
 }
 
 var (
-	// report file Name is {game}.{turnNo}.{Clan}.docx
-	reClanReportFile = regexp.MustCompile(`^(\d{4})\.(\d{4}-\d{2})\.(0\d{3})\.docx$`)
+	// report file Name is {game}.{turnNo}.{Clan}.{ext}
+	reClanReportFile = regexp.MustCompile(`^(\d{4})\.(\d{4}-\d{2})\.(0\d{3})\.[^.]+$`)
 
-	// report file Name is {game}.{turnNo}.{unitId}.docx
-	reUnitReportFile = regexp.MustCompile(`^(\d{4})\.(\d{4}-\d{2})\.(\d{4}([cefg][1-9])?)\.docx$`)
+	// report file Name is {game}.{turnNo}.{unitId}.{ext}
+	reUnitReportFile = regexp.MustCompile(`^(\d{4})\.(\d{4}-\d{2})\.(\d{4}([cefg][1-9])?)\.[^.]+$`)
 )
 
 func (s *Service) ImportUsers(path string, handles ...string) error {
@@ -0,0 +1,244 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+// Package blobs implements the content-addressed store for document contents.
+//
+// Blobs are keyed by the SHA-256 hash of the contents (see documents.Hash),
+// so identical uploads from different clans, or different revisions of the
+// same document, share a single row. Text extracts and Worldographer maps
+// compress well and are stored gzipped; the caller always sees the original
+// bytes.
+//
+// The data is kept in the blobs table (SQLiteStore) or in files on disk
+// (DiskStore), so that large uploads don't have to live in ottoapp.db.
+package blobs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/playbymail/ottoapp/backend/stores/sqlite"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+// Encodings for the data column.
+const (
+	Identity = "identity"
+	Gzip     = "gzip"
+)
+
+// Config keys for choosing the store.
+const (
+	StoreKey = "blobs.store" // name of the store for new blobs; defaults to SQLite
+	PathKey  = "blobs.path"  // directory for the disk store; relative paths are from the database directory
+)
+
+type Error string
+
+func (e Error) Error() string {
+	return string(e)
+}
+
+const (
+	ErrCorrupt         = Error("blob is corrupt")
+	ErrNotExists       = Error("blob does not exist")
+	ErrUnknownEncoding = Error("unknown encoding")
+	ErrUnknownStore    = Error("unknown store")
+)
+
+// Blobs reads and writes document contents.
+//
+// The blobs table is the index for every store. It records the hash,
+// the lengths, the encoding, and the store that holds the data. Reads
+// use the store recorded for the blob; writes use the store named by
+// the config table, so a running server picks up a migration without
+// a restart.
+type Blobs struct {
+	dbPath string // directory holding the database file
+}
+
+func New(db *sqlite.DB) *Blobs {
+	return &Blobs{dbPath: db.Path()}
+}
+
+// Put stores the contents under their hash. It is not an error for the
+// blob to exist already; the contents are the same, so the row is kept.
+// The row alone doesn't prove that a file store has the data, so Put
+// writes the file again if it is missing (see Collect).
+// Callers should pass the queries for their transaction so that the blob
+// is written (or rolled back) with the rows that reference it.
+func (b *Blobs) Put(ctx context.Context, q *sqlc.Queries, hash string, contents []byte, compress bool, createdAt int64) error {
+	store, err := b.Current(ctx, q)
+	if err != nil {
+		return err
+	}
+	encoding, data, err := Encode(contents, compress)
+	if err != nil {
+		return err
+	}
+	n, err := q.CreateBlob(ctx, sqlc.CreateBlobParams{
+		ContentsHash:  hash,
+		ContentLength: int64(len(contents)),
+		Encoding:      encoding,
+		StoredLength:  int64(len(data)),
+		Store:         store.Name(),
+		CreatedAt:     createdAt,
+	})
+	if err != nil {
+		return err
+	} else if n == 0 {
+		// the blob already exists
+		return b.restore(ctx, q, hash, contents)
+	}
+	return store.Put(ctx, q, hash, data)
+}
+
+// restore writes the data for an existing blob if its store has lost it.
+// The SQLite store keeps the data in the row, so it can't lose it.
+func (b *Blobs) restore(ctx context.Context, q *sqlc.Queries, hash string, contents []byte) error {
+	row, err := q.ReadBlob(ctx, hash)
+	if err != nil {
+		return err
+	} else if row.Store == SQLite {
+		return nil
+	}
+	store, err := b.Store(ctx, q, row.Store)
+	if err != nil {
+		return err
+	}
+	if _, err = store.Get(ctx, q, hash); err == nil {
+		return nil
+	} else if !errors.Is(err, ErrNotExists) {
+		return err
+	}
+	encoding, data, err := Encode(contents, row.Encoding == Gzip)
+	if err != nil {
+		return err
+	}
+	err = q.UpdateBlobEncoding(ctx, sqlc.UpdateBlobEncodingParams{
+		Encoding:     encoding,
+		StoredLength: int64(len(data)),
+		ContentsHash: hash,
+	})
+	if err != nil {
+		return err
+	}
+	return store.Put(ctx, q, hash, data)
+}
+
+// Get returns the contents stored under the hash.
+// Returns ErrNotExists if there is no blob for the hash and ErrCorrupt
+// if the stored data doesn't match the hash.
+func (b *Blobs) Get(ctx context.Context, q *sqlc.Queries, hash string) ([]byte, error) {
+	row, err := q.ReadBlob(ctx, hash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotExists
+		}
+		return nil, err
+	}
+	store, err := b.Store(ctx, q, row.Store)
+	if err != nil {
+		return nil, err
+	}
+	data, err := store.Get(ctx, q, hash)
+	if err != nil {
+		return nil, err
+	}
+	contents, err := Decode(row.Encoding, data)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(contents)) != row.ContentLength || !Verify(hash, contents) {
+		return nil, errors.Join(ErrCorrupt, fmt.Errorf("%s: contents do not match hash", hash))
+	}
+	return contents, nil
+}
+
+// Current returns the store for new blobs.
+func (b *Blobs) Current(ctx context.Context, q *sqlc.Queries) (Store, error) {
+	name, err := q.ReadConfigKeyValue(ctx, StoreKey)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+		name = SQLite
+	}
+	return b.Store(ctx, q, name)
+}
+
+// Store returns the store with the given name.
+func (b *Blobs) Store(ctx context.Context, q *sqlc.Queries, name string) (Store, error) {
+	switch name {
+	case SQLite:
+		return &SQLiteStore{}, nil
+	case Disk:
+		path, err := q.ReadConfigKeyValue(ctx, PathKey)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return nil, err
+			}
+			path = "blobs"
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(b.dbPath, path)
+		}
+		return NewDiskStore(path), nil
+	}
+	return nil, fmt.Errorf("%q: %w", name, ErrUnknownStore)
+}
+
+// Encode returns the encoding and data to store for the contents.
+// If compress is set, the contents are gzipped, but only kept that way
+// if that saves space.
+func Encode(contents []byte, compress bool) (string, []byte, error) {
+	if !compress {
+		return Identity, contents, nil
+	}
+	buf := &bytes.Buffer{}
+	zw, err := gzip.NewWriterLevel(buf, gzip.BestCompression)
+	if err != nil {
+		return "", nil, err
+	}
+	if _, err := zw.Write(contents); err != nil {
+		return "", nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return "", nil, err
+	}
+	if buf.Len() >= len(contents) {
+		return Identity, contents, nil
+	}
+	return Gzip, buf.Bytes(), nil
+}
+
+// Decode reverses Encode.
+func Decode(encoding string, data []byte) ([]byte, error) {
+	switch encoding {
+	case Identity:
+		return data, nil
+	case Gzip:
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, errors.Join(ErrCorrupt, err)
+		}
+		defer zr.Close()
+		contents, err := io.ReadAll(zr)
+		if err != nil {
+			return nil, errors.Join(ErrCorrupt, err)
+		}
+		return contents, nil
+	}
+	return nil, fmt.Errorf("%q: %w", encoding, ErrUnknownEncoding)
+}
+
+// Verify returns true if the contents match the hash.
+func Verify(hash string, contents []byte) bool {
+	return fmt.Sprintf("%x", sha256.Sum256(contents)) == hash
+}
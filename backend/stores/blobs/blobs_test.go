@@ -0,0 +1,156 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package blobs_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/playbymail/ottoapp/backend/stores/blobs"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	text := bytes.Repeat([]byte("Current Turn 899-12 (#0), Winter, FINE\n"), 100)
+	tests := []struct {
+		name     string
+		contents []byte
+		compress bool
+		want     string
+	}{
+		{name: "identity", contents: text, compress: false, want: blobs.Identity},
+		{name: "gzip", contents: text, compress: true, want: blobs.Gzip},
+		{name: "too small", contents: []byte("x"), compress: true, want: blobs.Identity},
+		{name: "empty", contents: []byte{}, compress: true, want: blobs.Identity},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			encoding, data, err := blobs.Encode(tc.contents, tc.compress)
+			if err != nil {
+				t.Fatalf("encode: %v", err)
+			}
+			if encoding != tc.want {
+				t.Errorf("encoding: want %q, got %q", tc.want, encoding)
+			}
+			got, err := blobs.Decode(encoding, data)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if !bytes.Equal(got, tc.contents) {
+				t.Errorf("decode: contents do not match")
+			}
+		})
+	}
+	if _, err := blobs.Decode("br", nil); err == nil {
+		t.Errorf("decode: want error for unknown encoding")
+	}
+}
+
+func TestDiskStore(t *testing.T) {
+	ctx := context.Background()
+	store := blobs.NewDiskStore(t.TempDir())
+	contents := []byte("Current Turn 899-12 (#0), Winter, FINE\n")
+	hash := fmt.Sprintf("%x", sha256.Sum256(contents))
+
+	if _, err := store.Get(ctx, nil, hash); !errors.Is(err, blobs.ErrNotExists) {
+		t.Fatalf("get: want ErrNotExists, got %v", err)
+	}
+	if err := store.Put(ctx, nil, hash, contents); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(store.Root(), hash[:2], hash[2:4], hash)); err != nil {
+		t.Errorf("put: %v", err)
+	}
+	got, err := store.Get(ctx, nil, hash)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	} else if !bytes.Equal(got, contents) {
+		t.Errorf("get: contents do not match")
+	}
+	if err := store.Delete(ctx, nil, hash); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := store.Delete(ctx, nil, hash); err != nil {
+		t.Errorf("delete: missing blob: %v", err)
+	}
+	if err := store.Put(ctx, nil, "../../etc/passwd", contents); err == nil {
+		t.Errorf("put: want error for invalid hash")
+	}
+}
+
+// TestCollectConcurrentPut checks that a Put racing a collection of the
+// same blob never leaves a row whose file has been deleted.
+func TestCollectConcurrentPut(t *testing.T) {
+	ctx := context.Background()
+	// use a file database so that the two goroutines contend for the
+	// write lock the way the server and the gc command do
+	path := t.TempDir()
+	if err := sqlite.Init(ctx, path, false); err != nil {
+		t.Fatal(err)
+	}
+	db, err := sqlite.Open(ctx, path, true, true, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	q := db.Queries()
+	for key, value := range map[string]string{blobs.StoreKey: blobs.Disk, blobs.PathKey: "blobs"} {
+		_, err := q.UpsertConfigKeyValue(ctx, sqlc.UpsertConfigKeyValueParams{Key: key, Value: value, CreatedAt: 1, UpdatedAt: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	b := blobs.New(db)
+	contents := []byte("Current Turn 899-12 (#0), Winter, FINE\n")
+	hash := fmt.Sprintf("%x", sha256.Sum256(contents))
+
+	// nothing references the blob, so every collection deletes it.
+	// errors from the database being busy are expected and ignored.
+	for round := 0; round < 100; round++ {
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = b.Put(ctx, q, hash, contents, false, 1)
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = b.Collect(db, false, true, false, false)
+		}()
+		wg.Wait()
+		if _, err := q.ReadBlob(ctx, hash); err != nil {
+			continue // collected
+		}
+		if _, err := b.Get(ctx, q, hash); err != nil {
+			t.Fatalf("round %d: get: %v", round, err)
+		}
+	}
+
+	// a row that lost its file, as a collection that failed to commit
+	// leaves it, gets the file back from the next Put
+	if err := b.Put(ctx, q, hash, contents, false, 1); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	store, err := b.Current(ctx, q)
+	if err != nil {
+		t.Fatal(err)
+	} else if err := store.Delete(ctx, q, hash); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Put(ctx, q, hash, contents, false, 1); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if got, err := b.Get(ctx, q, hash); err != nil {
+		t.Fatalf("get: %v", err)
+	} else if !bytes.Equal(got, contents) {
+		t.Errorf("get: contents do not match")
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package blobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+// DiskStore keeps the data in files, sharded by hash prefix:
+//
+//	root/ab/cd/abcd...
+//
+// Writes go to a temporary file in the shard that is synced and renamed
+// into place, so readers never see a partial blob.
+//
+// If the caller's transaction rolls back after Put, the file is left
+// behind. That is harmless; the next Put of the same contents replaces it.
+type DiskStore struct {
+	root string
+}
+
+func NewDiskStore(root string) *DiskStore {
+	return &DiskStore{root: root}
+}
+
+func (s *DiskStore) Name() string {
+	return Disk
+}
+
+// Root returns the directory that holds the shards.
+func (s *DiskStore) Root() string {
+	return s.root
+}
+
+func (s *DiskStore) Put(ctx context.Context, q *sqlc.Queries, hash string, data []byte) error {
+	path, err := s.path(hash)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, "."+hash+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // fails harmlessly after the rename
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+func (s *DiskStore) Get(ctx context.Context, q *sqlc.Queries, hash string) ([]byte, error) {
+	path, err := s.path(hash)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotExists
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+func (s *DiskStore) Delete(ctx context.Context, q *sqlc.Queries, hash string) error {
+	path, err := s.path(hash)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path returns the file name for the hash. The hash is checked because
+// it becomes part of a path.
+func (s *DiskStore) path(hash string) (string, error) {
+	if len(hash) != 64 {
+		return "", fmt.Errorf("%q: invalid hash", hash)
+	}
+	for _, ch := range hash {
+		if !('0' <= ch && ch <= '9') && !('a' <= ch && ch <= 'f') {
+			return "", fmt.Errorf("%q: invalid hash", hash)
+		}
+	}
+	return filepath.Join(s.root, hash[:2], hash[2:4], hash), nil
+}
+
+// syncDir flushes the directory entry for a renamed file.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
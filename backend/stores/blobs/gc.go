@@ -0,0 +1,133 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package blobs
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/stores/sqlite"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+// Stats reports the work done by Collect.
+type Stats struct {
+	Deleted      int   // unreferenced blobs removed
+	DeletedBytes int64 // stored bytes freed by removing them
+	Compressed   int   // blobs converted from identity to gzip
+	SavedBytes   int64 // stored bytes saved by compressing them
+}
+
+// Collect deletes blobs that no document or revision references and
+// compresses text blobs that were stored before compression was added
+// (the migration copies the old rows without compressing them). Only
+// blobs in the SQLite store are compressed, since rewriting a file can't
+// be rolled back with the transaction.
+//
+// Files for deleted blobs are removed inside the transaction, after the
+// row is deleted and the references are checked again. The delete holds
+// the database write lock until the commit, so a Put of the same hash
+// can't add the row back while the file is being removed. If the commit
+// fails, the row is left without its file; the next Put of the contents
+// writes it again.
+//
+// With dryRun set, Collect reports what it would do without changing
+// the database. Run `db compact` afterward to return the space to the
+// file system.
+func (b *Blobs) Collect(db *sqlite.DB, dryRun, quiet, verbose, debug bool) (*Stats, error) {
+	started := time.Now()
+	ctx := db.Context()
+	tx, err := db.Stdlib().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() // rollback if we return early; harmless after commit
+	qtx := db.Queries().WithTx(tx)
+
+	stats := &Stats{}
+
+	unreferenced, err := qtx.ReadUnreferencedBlobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range unreferenced {
+		if debug {
+			log.Printf("[blobs] gc: %s: unreferenced, %d bytes\n", row.ContentsHash, row.StoredLength)
+		}
+		if !dryRun {
+			n, err := qtx.DeleteUnreferencedBlob(ctx, row.ContentsHash)
+			if err != nil {
+				return nil, errors.Join(fmt.Errorf("%s: delete", row.ContentsHash), err)
+			} else if n == 0 {
+				// something started referencing it
+				continue
+			}
+			if row.Store != SQLite {
+				store, err := b.Store(ctx, qtx, row.Store)
+				if err != nil {
+					return nil, errors.Join(fmt.Errorf("%s: delete", row.ContentsHash), err)
+				} else if err = store.Delete(ctx, qtx, row.ContentsHash); err != nil {
+					return nil, errors.Join(fmt.Errorf("%s: delete", row.ContentsHash), err)
+				}
+			}
+		}
+		stats.Deleted++
+		stats.DeletedBytes += row.StoredLength
+	}
+
+	uncompressed, err := qtx.ReadUncompressedBlobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, hash := range uncompressed {
+		row, err := qtx.ReadBlob(ctx, hash)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("%s: read", hash), err)
+		}
+		store, err := b.Store(ctx, qtx, row.Store)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("%s: read", hash), err)
+		}
+		contents, err := store.Get(ctx, qtx, hash)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("%s: read", hash), err)
+		}
+		encoding, data, err := Encode(contents, true)
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("%s: encode", hash), err)
+		} else if encoding == Identity {
+			// compressing doesn't help, so leave it alone
+			continue
+		}
+		if debug {
+			log.Printf("[blobs] gc: %s: compressed %d to %d bytes\n", hash, row.StoredLength, len(data))
+		}
+		if !dryRun {
+			err = qtx.UpdateBlobEncoding(ctx, sqlc.UpdateBlobEncodingParams{
+				Encoding:     encoding,
+				StoredLength: int64(len(data)),
+				ContentsHash: hash,
+			})
+			if err != nil {
+				return nil, errors.Join(fmt.Errorf("%s: update", hash), err)
+			}
+			if err := store.Put(ctx, qtx, hash, data); err != nil {
+				return nil, errors.Join(fmt.Errorf("%s: update", hash), err)
+			}
+		}
+		stats.Compressed++
+		stats.SavedBytes += row.StoredLength - int64(len(data))
+	}
+
+	if !dryRun {
+		if err := tx.Commit(); err != nil {
+			return nil, err
+		}
+	}
+	if verbose {
+		log.Printf("[blobs] gc: completed in %v\n", time.Since(started))
+	}
+	return stats, nil
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package blobs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/stores/sqlite"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+// MigrateStats reports the work done by Migrate.
+type MigrateStats struct {
+	Verified   int   // blobs read and checked against their hash
+	Moved      int   // blobs moved to the new store
+	MovedBytes int64 // stored bytes moved
+}
+
+// Migrate moves every blob to the named store and makes it the store
+// for new blobs.
+//
+// Each blob is read from its current store and checked against its hash,
+// written to the new store, then read back and compared before the row
+// is updated. Blobs are moved one transaction at a time, so an interrupted
+// migration can be run again to finish the job. The old copy is deleted
+// after the row is committed.
+//
+// With dryRun set, Migrate only reads and verifies the blobs that would
+// be moved.
+func (b *Blobs) Migrate(db *sqlite.DB, to string, dryRun, quiet, verbose, debug bool) (*MigrateStats, error) {
+	started := time.Now()
+	ctx := db.Context()
+	target, err := b.Store(ctx, db.Queries(), to)
+	if err != nil {
+		return nil, err
+	}
+
+	if !dryRun {
+		// switch first so that uploads made while we're moving land in the new store
+		now := time.Now().UTC().Unix()
+		_, err = db.Queries().UpsertConfigKeyValue(ctx, sqlc.UpsertConfigKeyValueParams{
+			Key:       StoreKey,
+			Value:     target.Name(),
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := db.Queries().ReadBlobsNotInStore(ctx, target.Name())
+	if err != nil {
+		return nil, err
+	}
+	stats := &MigrateStats{}
+	for _, row := range rows {
+		from, n, err := b.migrateBlob(db, row.ContentsHash, target, dryRun)
+		if err != nil {
+			return stats, errors.Join(fmt.Errorf("%s", row.ContentsHash), err)
+		}
+		stats.Verified++
+		if dryRun {
+			continue
+		}
+		stats.Moved++
+		stats.MovedBytes += n
+		if err := from.Delete(ctx, db.Queries(), row.ContentsHash); err != nil {
+			// the row points at the new store, so the old copy is just wasted space
+			log.Printf("[blobs] migrate: %s: %s: %v\n", row.ContentsHash, from.Name(), err)
+		}
+		if debug {
+			log.Printf("[blobs] migrate: %s: %s to %s, %d bytes\n", row.ContentsHash, from.Name(), target.Name(), n)
+		}
+	}
+	if verbose {
+		log.Printf("[blobs] migrate: completed in %v\n", time.Since(started))
+	}
+	return stats, nil
+}
+
+// migrateBlob copies one blob to the target store and updates its row.
+// Returns the store that held the blob and the number of bytes moved.
+func (b *Blobs) migrateBlob(db *sqlite.DB, hash string, target Store, dryRun bool) (Store, int64, error) {
+	ctx := db.Context()
+	tx, err := db.Stdlib().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer tx.Rollback() // rollback if we return early; harmless after commit
+	qtx := db.Queries().WithTx(tx)
+
+	row, err := qtx.ReadBlob(ctx, hash)
+	if err != nil {
+		return nil, 0, err
+	}
+	from, err := b.Store(ctx, qtx, row.Store)
+	if err != nil {
+		return nil, 0, err
+	}
+	data, err := from.Get(ctx, qtx, hash)
+	if err != nil {
+		return nil, 0, err
+	}
+	contents, err := Decode(row.Encoding, data)
+	if err != nil {
+		return nil, 0, err
+	} else if int64(len(contents)) != row.ContentLength || !Verify(hash, contents) {
+		return nil, 0, errors.Join(ErrCorrupt, fmt.Errorf("%s: contents do not match hash", from.Name()))
+	}
+	if dryRun {
+		return from, 0, nil
+	}
+
+	if err := target.Put(ctx, qtx, hash, data); err != nil {
+		return nil, 0, err
+	}
+	check, err := target.Get(ctx, qtx, hash)
+	if err != nil {
+		return nil, 0, err
+	} else if !bytes.Equal(check, data) {
+		return nil, 0, errors.Join(ErrCorrupt, fmt.Errorf("%s: read back does not match", target.Name()))
+	}
+	err = qtx.UpdateBlobStore(ctx, sqlc.UpdateBlobStoreParams{Store: target.Name(), ContentsHash: hash})
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, 0, err
+	}
+	return from, int64(len(data)), nil
+}
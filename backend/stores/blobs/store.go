@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package blobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/playbymail/ottoapp/backend/stores/sqlite/sqlc"
+)
+
+// Store names, as recorded in the blobs table.
+const (
+	SQLite = "sqlite"
+	Disk   = "disk"
+)
+
+// Store holds the encoded data for blobs. The row in the blobs table
+// must exist before Put is called; Blobs.Put takes care of that.
+//
+// The queries are for the caller's transaction. Stores that don't keep
+// their data in the database ignore them.
+type Store interface {
+	// Name is the name recorded in the blobs table.
+	Name() string
+	// Put writes the data for the hash, replacing any earlier data.
+	Put(ctx context.Context, q *sqlc.Queries, hash string, data []byte) error
+	// Get returns the data for the hash or ErrNotExists.
+	Get(ctx context.Context, q *sqlc.Queries, hash string) ([]byte, error)
+	// Delete removes the data for the hash. It is not an error if
+	// there is no data.
+	Delete(ctx context.Context, q *sqlc.Queries, hash string) error
+}
+
+// SQLiteStore keeps the data in the blobs table.
+type SQLiteStore struct{}
+
+func (s *SQLiteStore) Name() string {
+	return SQLite
+}
+
+func (s *SQLiteStore) Put(ctx context.Context, q *sqlc.Queries, hash string, data []byte) error {
+	return q.UpdateBlobData(ctx, sqlc.UpdateBlobDataParams{Data: data, ContentsHash: hash})
+}
+
+func (s *SQLiteStore) Get(ctx context.Context, q *sqlc.Queries, hash string) ([]byte, error) {
+	data, err := q.ReadBlobData(ctx, hash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotExists
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// Delete empties the data column. Deleting the row is up to the caller.
+func (s *SQLiteStore) Delete(ctx context.Context, q *sqlc.Queries, hash string) error {
+	return q.UpdateBlobData(ctx, sqlc.UpdateBlobDataParams{Data: []byte{}, ContentsHash: hash})
+}
@@ -25,6 +25,8 @@ type Game struct {
 		Year, Month int
 	}
 	OrdersDue time.Time
+	// Timezone is the IANA time zone that orders are due in
+	Timezone string
 	// Clans is a map of Handle to ClanSetup
 	Clans map[string]ClanSetup
 }
@@ -46,6 +48,7 @@ func LoadGames(path string) (Games, error) {
 		SetupTurn   string               `json:"setup-turn"`
 		ActiveTurn  string               `json:"active-turn"`
 		OrdersDue   string               `json:"orders-due,omitempty"`
+		Timezone    string               `json:"timezone,omitempty"`
 		Clans       map[string]ClanSetup `json:"clans"`
 	}{}
 	err = json.Unmarshal(data, &jsonGames)
@@ -76,6 +79,16 @@ func LoadGames(path string) (Games, error) {
 				return nil, fmt.Errorf("%s: %w", game.Code, err)
 			}
 		}
+		// the time zone defaults to the zone that orders are due in
+		game.Timezone = jsonGame.Timezone
+		if game.Timezone == "" && jsonGame.OrdersDue != "" {
+			game.Timezone = jsonGame.OrdersDue[strings.LastIndex(jsonGame.OrdersDue, " ")+1:]
+		} else if game.Timezone == "" {
+			game.Timezone = "UTC"
+		}
+		if _, err = time.LoadLocation(game.Timezone); err != nil {
+			return nil, fmt.Errorf("%s: timezone %q: %w", game.Code, game.Timezone, err)
+		}
 		for handle, clan := range jsonGame.Clans {
 			if clan.SetupTurn == "" {
 				clan.SetupTurn = fmt.Sprintf("%04d-%02d", game.SetupTurn.Year, game.SetupTurn.Month)
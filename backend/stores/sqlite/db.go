@@ -16,7 +16,7 @@ import (
 
 const (
 	// the version of the database this application expects
-	expectedSchemaVersion = "20251029_0004"
+	expectedSchemaVersion = "20261018_0009"
 )
 
 type DB struct {
@@ -37,6 +37,12 @@ func (d *DB) Stdlib() *sql.DB {
 	return d.db
 }
 
+// Path returns the directory that holds the database file.
+// It is ":memory:" for a temporary database.
+func (d *DB) Path() string {
+	return d.path
+}
+
 // Context gives access to the default context for the store
 func (d *DB) Context() context.Context {
 	return d.ctx
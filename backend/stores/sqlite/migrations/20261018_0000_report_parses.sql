@@ -0,0 +1,38 @@
+--  Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+-- foreign keys must be enabled with every database connection
+PRAGMA foreign_keys = ON;
+
+-- The Report_Parses table records the output of parsing a turn report extract
+-- along with the parser that produced it.
+--
+-- The parser version is split into major, minor, and patch so that we can
+-- find parses made by an older version of a parser with a single query.
+-- There is at most one parse per document; re-parsing replaces the row.
+CREATE TABLE report_parses
+(
+    document_id    INTEGER NOT NULL,
+
+    report_format  TEXT    NOT NULL, -- tn-3.0, tn-3.1
+    parser_name    TEXT    NOT NULL, -- bistre
+    parser_major   INTEGER NOT NULL CHECK (parser_major >= 0),
+    parser_minor   INTEGER NOT NULL CHECK (parser_minor >= 0),
+    parser_patch   INTEGER NOT NULL CHECK (parser_patch >= 0),
+
+    contents_hash  TEXT    NOT NULL, -- hash of the document contents that were parsed
+    parse_error    TEXT    NOT NULL, -- empty if the parse succeeded
+    output         BLOB    NOT NULL, -- JSON encoded parser output
+    output_hash    TEXT    NOT NULL, -- hex encoded SHA-256 of the output
+
+    -- audit (unix seconds, UTC)
+    created_at     INTEGER NOT NULL, -- set in app
+    updated_at     INTEGER NOT NULL, -- set in app
+
+    PRIMARY KEY (document_id),
+    FOREIGN KEY (document_id)
+        REFERENCES documents (document_id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_report_parses_parser
+    ON report_parses (parser_name, parser_major, parser_minor, parser_patch);
@@ -0,0 +1,10 @@
+--  Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+-- foreign keys must be enabled with every database connection
+PRAGMA foreign_keys = ON;
+
+-- The game calendar is synced from the "Current Turn" and "Next Turn" lines
+-- in the clans' report extracts. The "Next Turn" line has the day that orders
+-- are due but not the time or zone, so we keep the game's IANA time zone.
+ALTER TABLE games
+    ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
@@ -0,0 +1,50 @@
+--  Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+-- foreign keys must be enabled with every database connection
+PRAGMA foreign_keys = ON;
+
+-- The Document_Revisions table keeps every version of a document's contents.
+-- Revisions are numbered from 1 for each document and are never updated.
+-- The Document_Contents table still holds the latest revision so that
+-- downloads don't have to look up the revision number first.
+CREATE TABLE document_revisions
+(
+    document_id    INTEGER NOT NULL,
+    revision       INTEGER NOT NULL CHECK (revision > 0),
+
+    content_length INTEGER NOT NULL, -- size in bytes
+    contents_hash  TEXT    NOT NULL, -- hex encoded SHA-256
+    contents       BLOB    NOT NULL,
+
+    uploaded_by    INTEGER NOT NULL, -- user that uploaded the revision
+    reason         TEXT    NOT NULL, -- created, replaced, synced, updated, reverted to revision 2
+
+    -- audit (unix seconds, UTC)
+    created_at     INTEGER NOT NULL, -- set in app
+
+    PRIMARY KEY (document_id, revision),
+    FOREIGN KEY (document_id)
+        REFERENCES documents (document_id)
+        ON DELETE CASCADE,
+    FOREIGN KEY (uploaded_by)
+        REFERENCES users (user_id)
+);
+
+-- the existing contents become the first revision, uploaded by the clan's owner
+INSERT INTO document_revisions (document_id, revision,
+                                content_length, contents_hash, contents,
+                                uploaded_by, reason,
+                                created_at)
+SELECT document_contents.document_id,
+       1,
+       document_contents.content_length,
+       document_contents.contents_hash,
+       document_contents.contents,
+       clans.user_id,
+       'created',
+       document_contents.updated_at
+FROM document_contents,
+     documents,
+     clans
+WHERE documents.document_id = document_contents.document_id
+  AND clans.clan_id = documents.clan_id;
@@ -0,0 +1,57 @@
+--  Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+-- foreign keys must be enabled with every database connection
+PRAGMA foreign_keys = ON;
+
+-- The Blobs table stores document contents once, keyed by the SHA-256 of
+-- the uncompressed contents. Documents and revisions refer to blobs by hash,
+-- so identical uploads (the same map sent to every clan, a revert, a re-sync)
+-- share a single row.
+--
+-- Text extracts and maps are stored gzip compressed when that saves space.
+-- Blobs that nothing refers to are removed by "ottoapp db gc".
+CREATE TABLE blobs
+(
+    contents_hash  TEXT    NOT NULL, -- hex encoded SHA-256 of the uncompressed contents
+    content_length INTEGER NOT NULL, -- uncompressed size in bytes
+    encoding       TEXT    NOT NULL CHECK (encoding IN ('identity', 'gzip')),
+    stored_length  INTEGER NOT NULL, -- size of data in bytes
+    data           BLOB    NOT NULL,
+
+    -- audit (unix seconds, UTC)
+    created_at     INTEGER NOT NULL, -- set in app
+
+    PRIMARY KEY (contents_hash)
+);
+
+-- move the existing contents to blobs. they're stored as-is; "ottoapp db gc"
+-- compresses them later.
+INSERT OR IGNORE INTO blobs (contents_hash, content_length, encoding, stored_length, data, created_at)
+SELECT contents_hash,
+       content_length,
+       'identity',
+       length(CAST(contents AS BLOB)),
+       contents,
+       created_at
+FROM document_revisions;
+
+INSERT OR IGNORE INTO blobs (contents_hash, content_length, encoding, stored_length, data, created_at)
+SELECT contents_hash,
+       content_length,
+       'identity',
+       length(CAST(contents AS BLOB)),
+       contents,
+       created_at
+FROM document_contents;
+
+ALTER TABLE document_revisions
+    DROP COLUMN contents;
+
+ALTER TABLE document_contents
+    DROP COLUMN contents;
+
+CREATE INDEX idx_document_contents_hash
+    ON document_contents (contents_hash);
+
+CREATE INDEX idx_document_revisions_hash
+    ON document_revisions (contents_hash);
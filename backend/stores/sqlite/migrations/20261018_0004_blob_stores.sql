@@ -0,0 +1,15 @@
+--  Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+-- foreign keys must be enabled with every database connection
+PRAGMA foreign_keys = ON;
+
+-- Blob data can be kept in the blobs table (sqlite) or in files on disk,
+-- sharded by hash prefix. The blobs table is the index for both stores;
+-- data is empty for blobs on disk.
+--
+-- New blobs go to the store named by the "blobs.store" config key.
+-- "ottoapp db blobs migrate" moves existing blobs between stores.
+ALTER TABLE blobs
+    ADD COLUMN store TEXT NOT NULL DEFAULT 'sqlite' CHECK (store IN ('sqlite', 'disk'));
+
+CREATE INDEX idx_blobs_store ON blobs (store);
@@ -0,0 +1,31 @@
+--  Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+-- foreign keys must be enabled with every database connection
+PRAGMA foreign_keys = ON;
+
+-- The Report_Extract_Lines table is the full-text index over the lines of
+-- the turn report extracts. There is one row for every non-blank line in
+-- the latest revision of each extract.
+--
+-- The rowid is (document_id << 20) + line_no so that all the lines for a
+-- document can be deleted with a range scan. FTS5 tables can't have
+-- foreign keys, so a trigger removes the lines when a document is deleted.
+--
+-- The table starts empty; "ottoapp db reindex" indexes existing extracts.
+CREATE VIRTUAL TABLE report_extract_lines USING fts5
+(
+    line,                  -- text of the line
+    unit_id UNINDEXED,     -- unit section the line is in, empty before the first section
+    document_id UNINDEXED,
+    line_no UNINDEXED,     -- starts at 1
+    tokenize = 'unicode61'
+);
+
+CREATE TRIGGER report_extract_lines_ad
+    AFTER DELETE
+    ON documents
+BEGIN
+    DELETE
+    FROM report_extract_lines
+    WHERE rowid BETWEEN (old.document_id << 20) AND (old.document_id << 20) + 1048575;
+END;
@@ -0,0 +1,45 @@
+--  Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+-- foreign keys must be enabled with every database connection
+PRAGMA foreign_keys = ON;
+
+-- The Document_Shares table grants read access to a document to another
+-- clan or user. A share names exactly one of them. Sharing with a clan
+-- grants access to the user that controls the clan, so the share follows
+-- the clan if it changes hands.
+--
+-- Shares without an expiry last until they are revoked. Revoked shares
+-- are kept so the owner can see who had access.
+CREATE TABLE document_shares
+(
+    share_id    INTEGER PRIMARY KEY AUTOINCREMENT,
+    document_id INTEGER NOT NULL,
+
+    clan_id     INTEGER, -- clan the document is shared with
+    user_id     INTEGER, -- user the document is shared with
+
+    granted_by  INTEGER NOT NULL, -- user that shared the document
+    expires_at  INTEGER,          -- unix seconds, UTC; null for no expiry
+    revoked_at  INTEGER,          -- unix seconds, UTC; null until revoked
+
+    -- audit (unix seconds, UTC)
+    created_at  INTEGER NOT NULL, -- set in app
+    updated_at  INTEGER NOT NULL, -- set in app
+
+    CHECK ((clan_id IS NULL) != (user_id IS NULL)),
+    FOREIGN KEY (document_id)
+        REFERENCES documents (document_id)
+        ON DELETE CASCADE,
+    FOREIGN KEY (clan_id)
+        REFERENCES clans (clan_id)
+        ON DELETE CASCADE,
+    FOREIGN KEY (user_id)
+        REFERENCES users (user_id)
+        ON DELETE CASCADE,
+    FOREIGN KEY (granted_by)
+        REFERENCES users (user_id)
+);
+
+CREATE INDEX idx_document_shares_document ON document_shares (document_id);
+CREATE INDEX idx_document_shares_clan ON document_shares (clan_id) WHERE clan_id IS NOT NULL;
+CREATE INDEX idx_document_shares_user ON document_shares (user_id) WHERE user_id IS NOT NULL;
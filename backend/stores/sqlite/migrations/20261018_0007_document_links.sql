@@ -0,0 +1,41 @@
+--  Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+-- foreign keys must be enabled with every database connection
+PRAGMA foreign_keys = ON;
+
+-- The Document_Links table holds the public links to a document. Anyone
+-- with the link can read the revision that was current when the link was
+-- created, until the link expires or is revoked. The link token is signed
+-- with the links.secret key from the config table, so a link can't be
+-- forged or altered to point at another document.
+--
+-- Revoked and expired links are kept so the owner can see how often each
+-- link was used.
+CREATE TABLE document_links
+(
+    link_id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    document_id      INTEGER NOT NULL,
+    revision         INTEGER NOT NULL, -- revision served by the link
+
+    created_by       INTEGER NOT NULL,           -- user that created the link
+    expires_at       INTEGER NOT NULL,           -- unix seconds, UTC
+    revoked_at       INTEGER,                    -- unix seconds, UTC; null until revoked
+    access_count     INTEGER NOT NULL DEFAULT 0, -- number of times the link was used
+    last_accessed_at INTEGER,                    -- unix seconds, UTC; null until used
+
+    -- audit (unix seconds, UTC)
+    created_at       INTEGER NOT NULL, -- set in app
+    updated_at       INTEGER NOT NULL, -- set in app
+
+    FOREIGN KEY (document_id, revision)
+        REFERENCES document_revisions (document_id, revision)
+        ON DELETE CASCADE,
+    FOREIGN KEY (created_by)
+        REFERENCES users (user_id)
+);
+
+CREATE INDEX idx_document_links_document ON document_links (document_id);
+
+-- Changing the secret invalidates every link.
+INSERT INTO config (key, value, created_at, updated_at)
+VALUES ('links.secret', lower(hex(randomblob(32))), strftime('%s', 'now'), strftime('%s', 'now'));
@@ -0,0 +1,18 @@
+--  Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+-- foreign keys must be enabled with every database connection
+PRAGMA foreign_keys = ON;
+
+-- Deleting a document moves it to the trash. Documents in the trash are
+-- hidden from every listing, search, share, and link, but keep their
+-- contents and revisions so they can be restored. The purge job deletes
+-- them for good once the retention window has passed.
+--
+-- Documents in the trash keep their names, so uploading a document with
+-- the same name restores it instead of creating a new one.
+ALTER TABLE documents
+    ADD COLUMN deleted_at INTEGER; -- unix seconds, UTC; null unless in the trash
+ALTER TABLE documents
+    ADD COLUMN deleted_by INTEGER REFERENCES users (user_id); -- user that deleted the document
+
+CREATE INDEX idx_documents_trash ON documents (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,38 @@
+--  Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+-- foreign keys must be enabled with every database connection
+PRAGMA foreign_keys = ON;
+
+-- The Document_Lineage table records which documents were derived from
+-- which: report extracts from turn report files, and maps from the parsed
+-- extracts for the turn and the turns before it. The edges are found from
+-- the document names when documents are stored.
+--
+-- Upstream_hash is the contents of the upstream document when the
+-- downstream document was last stored. When an upstream document gets new
+-- contents, every edge below it is marked stale until the downstream
+-- document is stored again.
+CREATE TABLE document_lineage
+(
+    upstream_id   INTEGER NOT NULL, -- document that was derived from
+    downstream_id INTEGER NOT NULL, -- document that was derived
+    relation      TEXT    NOT NULL, -- extracted, mapped
+
+    upstream_hash TEXT    NOT NULL, -- hex encoded SHA-256
+    stale_at      INTEGER,          -- unix seconds, UTC; null while up to date
+
+    -- audit (unix seconds, UTC)
+    created_at    INTEGER NOT NULL, -- set in app
+    updated_at    INTEGER NOT NULL, -- set in app
+
+    PRIMARY KEY (upstream_id, downstream_id),
+    CHECK (upstream_id != downstream_id),
+    FOREIGN KEY (upstream_id)
+        REFERENCES documents (document_id)
+        ON DELETE CASCADE,
+    FOREIGN KEY (downstream_id)
+        REFERENCES documents (document_id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX idx_document_lineage_downstream ON document_lineage (downstream_id);
@@ -0,0 +1,78 @@
+-- name: CreateBlob :execrows
+INSERT INTO blobs (contents_hash, content_length, encoding, stored_length, store, data, created_at)
+VALUES (:contents_hash, :content_length, :encoding, :stored_length, :store, X'', :created_at)
+ON CONFLICT (contents_hash) DO NOTHING;
+
+-- name: ReadBlob :one
+SELECT contents_hash,
+       content_length,
+       encoding,
+       stored_length,
+       store,
+       created_at
+FROM blobs
+WHERE contents_hash = :contents_hash;
+
+-- name: ReadBlobData :one
+SELECT data
+FROM blobs
+WHERE contents_hash = :contents_hash;
+
+-- name: ReadBlobsNotInStore :many
+SELECT contents_hash,
+       store
+FROM blobs
+WHERE store != :store
+ORDER BY contents_hash;
+
+-- name: UpdateBlobData :exec
+UPDATE blobs
+SET data = :data
+WHERE contents_hash = :contents_hash;
+
+-- name: UpdateBlobEncoding :exec
+UPDATE blobs
+SET encoding      = :encoding,
+    stored_length = :stored_length
+WHERE contents_hash = :contents_hash;
+
+-- name: UpdateBlobStore :exec
+UPDATE blobs
+SET store = :store
+WHERE contents_hash = :contents_hash;
+
+-- name: ReadUnreferencedBlobs :many
+SELECT contents_hash,
+       stored_length,
+       store
+FROM blobs
+WHERE NOT EXISTS (SELECT 1
+                  FROM document_revisions
+                  WHERE document_revisions.contents_hash = blobs.contents_hash)
+  AND NOT EXISTS (SELECT 1
+                  FROM document_contents
+                  WHERE document_contents.contents_hash = blobs.contents_hash)
+ORDER BY contents_hash;
+
+-- name: ReadUncompressedBlobs :many
+SELECT DISTINCT blobs.contents_hash
+FROM blobs,
+     document_revisions,
+     documents
+WHERE blobs.encoding = 'identity'
+  AND blobs.store = 'sqlite'
+  AND document_revisions.contents_hash = blobs.contents_hash
+  AND documents.document_id = document_revisions.document_id
+  AND documents.document_type IN ('turn-report-extract', 'worldographer-map')
+ORDER BY blobs.contents_hash;
+
+-- name: DeleteUnreferencedBlob :execrows
+DELETE
+FROM blobs
+WHERE contents_hash = :contents_hash
+  AND NOT EXISTS (SELECT 1
+                  FROM document_revisions
+                  WHERE document_revisions.contents_hash = blobs.contents_hash)
+  AND NOT EXISTS (SELECT 1
+                  FROM document_contents
+                  WHERE document_contents.contents_hash = blobs.contents_hash);
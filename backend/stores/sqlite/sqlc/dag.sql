@@ -1 +1,113 @@
--- name: ReadTurnReportFiles :many
+--  Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+-- CreateDocumentLineage adds an edge from the upstream document to the
+-- downstream document, recording the upstream contents hash. It does
+-- nothing if the edge already exists.
+--
+-- name: CreateDocumentLineage :exec
+INSERT INTO document_lineage (upstream_id, downstream_id, relation,
+                              upstream_hash,
+                              created_at, updated_at)
+SELECT document_contents.document_id,
+       :downstream_id,
+       :relation,
+       document_contents.contents_hash,
+       :created_at,
+       :created_at
+FROM document_contents
+WHERE document_contents.document_id = :upstream_id
+ON CONFLICT (upstream_id, downstream_id) DO NOTHING;
+
+-- MarkDocumentLineageStale marks every edge below the document as stale.
+-- Edges that are already stale keep the time they went stale.
+--
+-- name: MarkDocumentLineageStale :exec
+WITH RECURSIVE descendants(document_id) AS (SELECT CAST(:document_id AS INTEGER)
+                                            UNION
+                                            SELECT document_lineage.downstream_id
+                                            FROM document_lineage
+                                                     JOIN descendants
+                                                          ON descendants.document_id = document_lineage.upstream_id)
+UPDATE document_lineage
+SET stale_at   = :stale_at,
+    updated_at = :stale_at
+WHERE upstream_id IN (SELECT document_id FROM descendants)
+  AND stale_at IS NULL;
+
+-- ReadDocumentLineage returns the edges above and below the document.
+-- Edges to documents in the trash are skipped.
+--
+-- name: ReadDocumentLineage :many
+WITH RECURSIVE ancestors(document_id) AS (SELECT CAST(:document_id AS INTEGER)
+                                          UNION
+                                          SELECT document_lineage.upstream_id
+                                          FROM document_lineage
+                                                   JOIN ancestors
+                                                        ON ancestors.document_id = document_lineage.downstream_id),
+               descendants(document_id) AS (SELECT CAST(:document_id AS INTEGER)
+                                            UNION
+                                            SELECT document_lineage.downstream_id
+                                            FROM document_lineage
+                                                     JOIN descendants
+                                                          ON descendants.document_id = document_lineage.upstream_id)
+SELECT document_lineage.upstream_id,
+       document_lineage.downstream_id,
+       document_lineage.relation,
+       document_lineage.upstream_hash,
+       document_lineage.stale_at
+FROM document_lineage
+         JOIN documents AS upstream ON upstream.document_id = document_lineage.upstream_id
+         JOIN documents AS downstream ON downstream.document_id = document_lineage.downstream_id
+WHERE (document_lineage.downstream_id IN (SELECT document_id FROM ancestors)
+    OR document_lineage.upstream_id IN (SELECT document_id FROM descendants))
+  AND upstream.deleted_at IS NULL
+  AND downstream.deleted_at IS NULL
+ORDER BY document_lineage.upstream_id, document_lineage.downstream_id;
+
+-- name: ReadLineageClans :many
+SELECT DISTINCT documents.clan_id
+FROM documents
+WHERE documents.deleted_at IS NULL
+ORDER BY documents.clan_id;
+
+-- name: ReadLineageDocuments :many
+SELECT documents.document_id,
+       documents.document_name,
+       documents.document_type
+FROM documents
+WHERE documents.clan_id = :clan_id
+  AND documents.deleted_at IS NULL
+ORDER BY documents.document_name, documents.document_id;
+
+-- ReadLineageNode returns a document in the lineage, with the parser
+-- results if it is a report extract that has been parsed.
+--
+-- name: ReadLineageNode :one
+SELECT documents.document_id,
+       documents.document_name,
+       documents.document_type,
+       document_contents.contents_hash,
+       documents.updated_at,
+       report_parses.parser_name,
+       report_parses.parser_major,
+       report_parses.parser_minor,
+       report_parses.parser_patch,
+       report_parses.contents_hash as parsed_hash,
+       report_parses.parse_error
+FROM documents
+         JOIN document_contents ON document_contents.document_id = documents.document_id
+         LEFT JOIN report_parses ON report_parses.document_id = documents.document_id
+WHERE documents.document_id = :document_id
+  AND documents.deleted_at IS NULL;
+
+-- RefreshDocumentLineage marks the edges into the document as up to date
+-- with the current contents of the upstream documents.
+--
+-- name: RefreshDocumentLineage :exec
+UPDATE document_lineage
+SET upstream_hash = (SELECT document_contents.contents_hash
+                     FROM document_contents
+                     WHERE document_contents.document_id = document_lineage.upstream_id),
+    stale_at      = NULL,
+    updated_at    = :updated_at
+WHERE downstream_id = :document_id;
@@ -31,7 +31,8 @@ SELECT clans.game_id,
        document_contents.contents_hash,
        documents.modified_at,
        documents.created_at,
-       documents.updated_at
+       documents.updated_at,
+       documents.deleted_at
 FROM clans,
      documents,
      document_contents
@@ -81,18 +82,16 @@ WHERE document_id = :document_id
 INSERT INTO document_contents(document_id,
                               content_length,
                               contents_hash,
-                              contents,
                               created_at,
                               updated_at)
 VALUES (:document_id,
         :content_length,
         :contents_hash,
-        :contents,
         :created_at,
         :updated_at);
 
--- name: ReadDocumentContents :one
-SELECT contents
+-- name: ReadDocumentContentsHash :one
+SELECT contents_hash
 FROM document_contents
 WHERE document_id = :document_id;
 
@@ -105,7 +104,7 @@ SELECT clans.game_id,
        documents.document_name,
        documents.document_type,
        document_types.content_type,
-       document_contents.contents,
+       document_contents.contents_hash,
        documents.modified_at,
        documents.created_at,
        documents.updated_at
@@ -124,7 +123,6 @@ WHERE documents.document_id = :document_id
 UPDATE document_contents
 SET content_length = :content_length,
     contents_hash  = :contents_hash,
-    contents       = :contents,
     updated_at     = :updated_at
 WHERE document_contents.document_id = :document_id;
 
@@ -184,7 +182,28 @@ SELECT clans.game_id,
 FROM clans,
      documents
 WHERE clans.user_id = :user_id
-  AND documents.clan_id = clans.clan_id;
+  AND documents.clan_id = clans.clan_id
+  AND documents.deleted_at IS NULL;
+
+-- name: ReadDocumentArchiveByUser :many
+SELECT games.code,
+       clans.clan,
+       documents.document_id,
+       documents.document_name,
+       documents.document_type,
+       document_contents.content_length,
+       document_contents.contents_hash,
+       documents.modified_at
+FROM clans,
+     games,
+     documents,
+     document_contents
+WHERE clans.user_id = :user_id
+  AND games.game_id = clans.game_id
+  AND documents.clan_id = clans.clan_id
+  AND document_contents.document_id = documents.document_id
+  AND documents.deleted_at IS NULL
+ORDER BY games.code, documents.document_name;
 
 -- name: ReadDocumentOwner :one
 SELECT clans.game_id,
@@ -196,7 +215,8 @@ SELECT clans.game_id,
 FROM documents,
      clans
 WHERE documents.document_id = :document_id
-  AND clans.clan_id = documents.clan_id;
+  AND clans.clan_id = documents.clan_id
+  AND documents.deleted_at IS NULL;
 
 -- name: ReadReportExtracts :many
 select documents.document_id,
@@ -208,6 +228,58 @@ select documents.document_id,
        documents.created_at,
        documents.updated_at
 from documents, clans
-where documents.document_type = 'txt'
+where documents.document_type = 'turn-report-extract'
 and clans.clan_id = documents.clan_id
+and documents.deleted_at is null
 order by game_id, turn_no, clan;
+
+-- name: CreateDocumentRevision :one
+INSERT INTO document_revisions (document_id, revision,
+                                content_length, contents_hash,
+                                uploaded_by, reason,
+                                created_at)
+SELECT :document_id,
+       COALESCE(MAX(revision), 0) + 1,
+       :content_length,
+       :contents_hash,
+       :uploaded_by,
+       :reason,
+       :created_at
+FROM document_revisions
+WHERE document_id = :document_id
+RETURNING revision;
+
+-- name: ReadDocumentRevisions :many
+SELECT document_revisions.document_id,
+       document_revisions.revision,
+       document_revisions.content_length,
+       document_revisions.contents_hash,
+       users.handle AS uploaded_by,
+       document_revisions.reason,
+       document_revisions.created_at
+FROM document_revisions,
+     documents,
+     users
+WHERE document_revisions.document_id = :document_id
+  AND documents.document_id = document_revisions.document_id
+  AND documents.clan_id = :clan_id
+  AND users.user_id = document_revisions.uploaded_by
+ORDER BY document_revisions.revision DESC;
+
+-- name: ReadDocumentRevision :one
+SELECT documents.document_id,
+       documents.document_name,
+       documents.document_type,
+       document_types.content_type,
+       document_revisions.revision,
+       document_revisions.content_length,
+       document_revisions.contents_hash,
+       document_revisions.created_at
+FROM documents,
+     document_types,
+     document_revisions
+WHERE document_revisions.document_id = :document_id
+  AND document_revisions.revision = :revision
+  AND documents.document_id = document_revisions.document_id
+  AND documents.clan_id = :clan_id
+  AND document_types.document_type = documents.document_type;
@@ -7,6 +7,7 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createDocument = `-- name: CreateDocument :one
@@ -48,22 +49,19 @@ const createDocumentContents = `-- name: CreateDocumentContents :exec
 INSERT INTO document_contents(document_id,
                               content_length,
                               contents_hash,
-                              contents,
                               created_at,
                               updated_at)
 VALUES (?1,
         ?2,
         ?3,
         ?4,
-        ?5,
-        ?6)
+        ?5)
 `
 
 type CreateDocumentContentsParams struct {
 	DocumentID    int64
 	ContentLength int64
 	ContentsHash  string
-	Contents      []byte
 	CreatedAt     int64
 	UpdatedAt     int64
 }
@@ -73,13 +71,52 @@ func (q *Queries) CreateDocumentContents(ctx context.Context, arg CreateDocument
 		arg.DocumentID,
 		arg.ContentLength,
 		arg.ContentsHash,
-		arg.Contents,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
 	return err
 }
 
+const createDocumentRevision = `-- name: CreateDocumentRevision :one
+INSERT INTO document_revisions (document_id, revision,
+                                content_length, contents_hash,
+                                uploaded_by, reason,
+                                created_at)
+SELECT ?1,
+       COALESCE(MAX(revision), 0) + 1,
+       ?2,
+       ?3,
+       ?4,
+       ?5,
+       ?6
+FROM document_revisions
+WHERE document_id = ?1
+RETURNING revision
+`
+
+type CreateDocumentRevisionParams struct {
+	DocumentID    int64
+	ContentLength int64
+	ContentsHash  string
+	UploadedBy    int64
+	Reason        string
+	CreatedAt     int64
+}
+
+func (q *Queries) CreateDocumentRevision(ctx context.Context, arg CreateDocumentRevisionParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, createDocumentRevision,
+		arg.DocumentID,
+		arg.ContentLength,
+		arg.ContentsHash,
+		arg.UploadedBy,
+		arg.Reason,
+		arg.CreatedAt,
+	)
+	var revision int64
+	err := row.Scan(&revision)
+	return revision, err
+}
+
 const deleteDocumentByClanAndNameAuthorized = `-- name: DeleteDocumentByClanAndNameAuthorized :exec
 DELETE
 FROM documents
@@ -136,6 +173,70 @@ func (q *Queries) DeleteDocumentContentsById(ctx context.Context, documentID int
 	return err
 }
 
+const readDocumentArchiveByUser = `-- name: ReadDocumentArchiveByUser :many
+SELECT games.code,
+       clans.clan,
+       documents.document_id,
+       documents.document_name,
+       documents.document_type,
+       document_contents.content_length,
+       document_contents.contents_hash,
+       documents.modified_at
+FROM clans,
+     games,
+     documents,
+     document_contents
+WHERE clans.user_id = ?1
+  AND games.game_id = clans.game_id
+  AND documents.clan_id = clans.clan_id
+  AND document_contents.document_id = documents.document_id
+  AND documents.deleted_at IS NULL
+ORDER BY games.code, documents.document_name
+`
+
+type ReadDocumentArchiveByUserRow struct {
+	Code          string
+	Clan          int64
+	DocumentID    int64
+	DocumentName  string
+	DocumentType  string
+	ContentLength int64
+	ContentsHash  string
+	ModifiedAt    int64
+}
+
+func (q *Queries) ReadDocumentArchiveByUser(ctx context.Context, userID int64) ([]ReadDocumentArchiveByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, readDocumentArchiveByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadDocumentArchiveByUserRow
+	for rows.Next() {
+		var i ReadDocumentArchiveByUserRow
+		if err := rows.Scan(
+			&i.Code,
+			&i.Clan,
+			&i.DocumentID,
+			&i.DocumentName,
+			&i.DocumentType,
+			&i.ContentLength,
+			&i.ContentsHash,
+			&i.ModifiedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const readDocumentByClanAndName = `-- name: ReadDocumentByClanAndName :one
 SELECT clans.game_id,
        clans.user_id,
@@ -147,7 +248,8 @@ SELECT clans.game_id,
        document_contents.contents_hash,
        documents.modified_at,
        documents.created_at,
-       documents.updated_at
+       documents.updated_at,
+       documents.deleted_at
 FROM clans,
      documents,
      document_contents
@@ -174,6 +276,7 @@ type ReadDocumentByClanAndNameRow struct {
 	ModifiedAt   int64
 	CreatedAt    int64
 	UpdatedAt    int64
+	DeletedAt    sql.NullInt64
 }
 
 func (q *Queries) ReadDocumentByClanAndName(ctx context.Context, arg ReadDocumentByClanAndNameParams) (ReadDocumentByClanAndNameRow, error) {
@@ -191,6 +294,7 @@ func (q *Queries) ReadDocumentByClanAndName(ctx context.Context, arg ReadDocumen
 		&i.ModifiedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.DeletedAt,
 	)
 	return i, err
 }
@@ -222,19 +326,6 @@ func (q *Queries) ReadDocumentById(ctx context.Context, documentID int64) (Docum
 	return i, err
 }
 
-const readDocumentContents = `-- name: ReadDocumentContents :one
-SELECT contents
-FROM document_contents
-WHERE document_id = ?1
-`
-
-func (q *Queries) ReadDocumentContents(ctx context.Context, documentID int64) ([]byte, error) {
-	row := q.db.QueryRowContext(ctx, readDocumentContents, documentID)
-	var contents []byte
-	err := row.Scan(&contents)
-	return contents, err
-}
-
 const readDocumentContentsByIdAuthorized = `-- name: ReadDocumentContentsByIdAuthorized :one
 SELECT clans.game_id,
        clans.user_id,
@@ -244,7 +335,7 @@ SELECT clans.game_id,
        documents.document_name,
        documents.document_type,
        document_types.content_type,
-       document_contents.contents,
+       document_contents.contents_hash,
        documents.modified_at,
        documents.created_at,
        documents.updated_at
@@ -273,7 +364,7 @@ type ReadDocumentContentsByIdAuthorizedRow struct {
 	DocumentName string
 	DocumentType string
 	ContentType  string
-	Contents     []byte
+	ContentsHash string
 	ModifiedAt   int64
 	CreatedAt    int64
 	UpdatedAt    int64
@@ -291,7 +382,7 @@ func (q *Queries) ReadDocumentContentsByIdAuthorized(ctx context.Context, arg Re
 		&i.DocumentName,
 		&i.DocumentType,
 		&i.ContentType,
-		&i.Contents,
+		&i.ContentsHash,
 		&i.ModifiedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
@@ -299,6 +390,19 @@ func (q *Queries) ReadDocumentContentsByIdAuthorized(ctx context.Context, arg Re
 	return i, err
 }
 
+const readDocumentContentsHash = `-- name: ReadDocumentContentsHash :one
+SELECT contents_hash
+FROM document_contents
+WHERE document_id = ?1
+`
+
+func (q *Queries) ReadDocumentContentsHash(ctx context.Context, documentID int64) (string, error) {
+	row := q.db.QueryRowContext(ctx, readDocumentContentsHash, documentID)
+	var contents_hash string
+	err := row.Scan(&contents_hash)
+	return contents_hash, err
+}
+
 const readDocumentOwner = `-- name: ReadDocumentOwner :one
 SELECT clans.game_id,
        clans.user_id,
@@ -310,6 +414,7 @@ FROM documents,
      clans
 WHERE documents.document_id = ?1
   AND clans.clan_id = documents.clan_id
+  AND documents.deleted_at IS NULL
 `
 
 type ReadDocumentOwnerRow struct {
@@ -335,6 +440,122 @@ func (q *Queries) ReadDocumentOwner(ctx context.Context, documentID int64) (Read
 	return i, err
 }
 
+const readDocumentRevision = `-- name: ReadDocumentRevision :one
+SELECT documents.document_id,
+       documents.document_name,
+       documents.document_type,
+       document_types.content_type,
+       document_revisions.revision,
+       document_revisions.content_length,
+       document_revisions.contents_hash,
+       document_revisions.created_at
+FROM documents,
+     document_types,
+     document_revisions
+WHERE document_revisions.document_id = ?1
+  AND document_revisions.revision = ?2
+  AND documents.document_id = document_revisions.document_id
+  AND documents.clan_id = ?3
+  AND document_types.document_type = documents.document_type
+`
+
+type ReadDocumentRevisionParams struct {
+	DocumentID int64
+	Revision   int64
+	ClanID     int64
+}
+
+type ReadDocumentRevisionRow struct {
+	DocumentID    int64
+	DocumentName  string
+	DocumentType  string
+	ContentType   string
+	Revision      int64
+	ContentLength int64
+	ContentsHash  string
+	CreatedAt     int64
+}
+
+func (q *Queries) ReadDocumentRevision(ctx context.Context, arg ReadDocumentRevisionParams) (ReadDocumentRevisionRow, error) {
+	row := q.db.QueryRowContext(ctx, readDocumentRevision, arg.DocumentID, arg.Revision, arg.ClanID)
+	var i ReadDocumentRevisionRow
+	err := row.Scan(
+		&i.DocumentID,
+		&i.DocumentName,
+		&i.DocumentType,
+		&i.ContentType,
+		&i.Revision,
+		&i.ContentLength,
+		&i.ContentsHash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const readDocumentRevisions = `-- name: ReadDocumentRevisions :many
+SELECT document_revisions.document_id,
+       document_revisions.revision,
+       document_revisions.content_length,
+       document_revisions.contents_hash,
+       users.handle AS uploaded_by,
+       document_revisions.reason,
+       document_revisions.created_at
+FROM document_revisions,
+     documents,
+     users
+WHERE document_revisions.document_id = ?1
+  AND documents.document_id = document_revisions.document_id
+  AND documents.clan_id = ?2
+  AND users.user_id = document_revisions.uploaded_by
+ORDER BY document_revisions.revision DESC
+`
+
+type ReadDocumentRevisionsParams struct {
+	DocumentID int64
+	ClanID     int64
+}
+
+type ReadDocumentRevisionsRow struct {
+	DocumentID    int64
+	Revision      int64
+	ContentLength int64
+	ContentsHash  string
+	UploadedBy    string
+	Reason        string
+	CreatedAt     int64
+}
+
+func (q *Queries) ReadDocumentRevisions(ctx context.Context, arg ReadDocumentRevisionsParams) ([]ReadDocumentRevisionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, readDocumentRevisions, arg.DocumentID, arg.ClanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadDocumentRevisionsRow
+	for rows.Next() {
+		var i ReadDocumentRevisionsRow
+		if err := rows.Scan(
+			&i.DocumentID,
+			&i.Revision,
+			&i.ContentLength,
+			&i.ContentsHash,
+			&i.UploadedBy,
+			&i.Reason,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const readDocumentsByGameAndClanNo = `-- name: ReadDocumentsByGameAndClanNo :many
 SELECT clans.game_id,
        clans.user_id,
@@ -472,6 +693,7 @@ FROM clans,
      documents
 WHERE clans.user_id = ?1
   AND documents.clan_id = clans.clan_id
+  AND documents.deleted_at IS NULL
 `
 
 type ReadDocumentsByUserRow struct {
@@ -531,8 +753,9 @@ select documents.document_id,
        documents.created_at,
        documents.updated_at
 from documents, clans
-where documents.document_type = 'txt'
+where documents.document_type = 'turn-report-extract'
 and clans.clan_id = documents.clan_id
+and documents.deleted_at is null
 order by game_id, turn_no, clan
 `
 
@@ -645,15 +868,13 @@ const updateDocumentContentsById = `-- name: UpdateDocumentContentsById :exec
 UPDATE document_contents
 SET content_length = ?1,
     contents_hash  = ?2,
-    contents       = ?3,
-    updated_at     = ?4
-WHERE document_contents.document_id = ?5
+    updated_at     = ?3
+WHERE document_contents.document_id = ?4
 `
 
 type UpdateDocumentContentsByIdParams struct {
 	ContentLength int64
 	ContentsHash  string
-	Contents      []byte
 	UpdatedAt     int64
 	DocumentID    int64
 }
@@ -662,7 +883,6 @@ func (q *Queries) UpdateDocumentContentsById(ctx context.Context, arg UpdateDocu
 	_, err := q.db.ExecContext(ctx, updateDocumentContentsById,
 		arg.ContentLength,
 		arg.ContentsHash,
-		arg.Contents,
 		arg.UpdatedAt,
 		arg.DocumentID,
 	)
@@ -1,11 +1,12 @@
 -- name: CreateGame :one
-INSERT INTO games (code, description, active_turn, setup_turn, orders_due, created_at, updated_at)
-VALUES (:code, :description, :active_turn, :setup_turn, :orders_due, :created_at, :updated_at)
+INSERT INTO games (code, description, active_turn, setup_turn, orders_due, timezone, created_at, updated_at)
+VALUES (:code, :description, :active_turn, :setup_turn, :orders_due, :timezone, :created_at, :updated_at)
 ON CONFLICT (code)
     DO UPDATE SET description = excluded.description,
                   active_turn = excluded.active_turn,
                   setup_turn  = excluded.setup_turn,
                   orders_due  = excluded.orders_due,
+                  timezone    = excluded.timezone,
                   updated_at  = excluded.updated_at
 RETURNING game_id;
 
@@ -14,9 +15,9 @@ INSERT INTO game_turns(game_id, turn, turn_year, turn_month, turn_no, created_at
 VALUES (:game_id, :turn, :turn_year, :turn_month, :turn_no, :created_at, :updated_at)
 ON CONFLICT (game_id, turn)
     DO UPDATE SET turn_year  = excluded.turn_year,
-                  turn_month = excluded.turn,
+                  turn_month = excluded.turn_month,
                   turn_no    = excluded.turn_no,
-                  updated_at = excluded.updated_at;;
+                  updated_at = excluded.updated_at;
 
 -- name: ReadGame :one
 SELECT games.game_id,
@@ -61,6 +62,46 @@ SET active_turn = :turn,
     updated_at  = :updated_at
 WHERE game_id = :game_id;
 
+-- name: ReadGameCalendar :one
+SELECT games.game_id,
+       games.code,
+       games.active_turn,
+       games.orders_due,
+       games.timezone
+FROM games
+WHERE games.game_id = :game_id;
+
+-- name: ReadGameTurns :many
+SELECT game_turns.turn,
+       game_turns.turn_year,
+       game_turns.turn_month,
+       game_turns.turn_no
+FROM game_turns
+WHERE game_turns.game_id = :game_id
+ORDER BY game_turns.turn;
+
+-- name: ReadGameReportExtracts :many
+SELECT documents.document_id,
+       documents.document_name,
+       clans.clan,
+       document_contents.contents_hash
+FROM documents,
+     document_contents,
+     clans
+WHERE clans.game_id = :game_id
+  AND documents.clan_id = clans.clan_id
+  AND documents.document_type = 'turn-report-extract'
+  AND document_contents.document_id = documents.document_id
+  AND documents.deleted_at IS NULL
+ORDER BY clans.clan, documents.document_name;
+
+-- name: UpdateGameCalendar :exec
+UPDATE games
+SET active_turn = :active_turn,
+    orders_due  = :orders_due,
+    updated_at  = :updated_at
+WHERE game_id = :game_id;
+
 -- name: DeleteGame :exec
 DELETE
 FROM games
@@ -10,13 +10,14 @@ import (
 )
 
 const createGame = `-- name: CreateGame :one
-INSERT INTO games (code, description, active_turn, setup_turn, orders_due, created_at, updated_at)
-VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)
+INSERT INTO games (code, description, active_turn, setup_turn, orders_due, timezone, created_at, updated_at)
+VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8)
 ON CONFLICT (code)
     DO UPDATE SET description = excluded.description,
                   active_turn = excluded.active_turn,
                   setup_turn  = excluded.setup_turn,
                   orders_due  = excluded.orders_due,
+                  timezone    = excluded.timezone,
                   updated_at  = excluded.updated_at
 RETURNING game_id
 `
@@ -27,6 +28,7 @@ type CreateGameParams struct {
 	ActiveTurn  string
 	SetupTurn   string
 	OrdersDue   int64
+	Timezone    string
 	CreatedAt   int64
 	UpdatedAt   int64
 }
@@ -38,6 +40,7 @@ func (q *Queries) CreateGame(ctx context.Context, arg CreateGameParams) (int64,
 		arg.ActiveTurn,
 		arg.SetupTurn,
 		arg.OrdersDue,
+		arg.Timezone,
 		arg.CreatedAt,
 		arg.UpdatedAt,
 	)
@@ -51,7 +54,7 @@ INSERT INTO game_turns(game_id, turn, turn_year, turn_month, turn_no, created_at
 VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)
 ON CONFLICT (game_id, turn)
     DO UPDATE SET turn_year  = excluded.turn_year,
-                  turn_month = excluded.turn,
+                  turn_month = excluded.turn_month,
                   turn_no    = excluded.turn_no,
                   updated_at = excluded.updated_at
 `
@@ -135,6 +138,133 @@ func (q *Queries) ReadGame(ctx context.Context, gameID int64) (ReadGameRow, erro
 	return i, err
 }
 
+const readGameCalendar = `-- name: ReadGameCalendar :one
+SELECT games.game_id,
+       games.code,
+       games.active_turn,
+       games.orders_due,
+       games.timezone
+FROM games
+WHERE games.game_id = ?1
+`
+
+type ReadGameCalendarRow struct {
+	GameID     int64
+	Code       string
+	ActiveTurn string
+	OrdersDue  int64
+	Timezone   string
+}
+
+func (q *Queries) ReadGameCalendar(ctx context.Context, gameID int64) (ReadGameCalendarRow, error) {
+	row := q.db.QueryRowContext(ctx, readGameCalendar, gameID)
+	var i ReadGameCalendarRow
+	err := row.Scan(
+		&i.GameID,
+		&i.Code,
+		&i.ActiveTurn,
+		&i.OrdersDue,
+		&i.Timezone,
+	)
+	return i, err
+}
+
+const readGameReportExtracts = `-- name: ReadGameReportExtracts :many
+SELECT documents.document_id,
+       documents.document_name,
+       clans.clan,
+       document_contents.contents_hash
+FROM documents,
+     document_contents,
+     clans
+WHERE clans.game_id = ?1
+  AND documents.clan_id = clans.clan_id
+  AND documents.document_type = 'turn-report-extract'
+  AND document_contents.document_id = documents.document_id
+  AND documents.deleted_at IS NULL
+ORDER BY clans.clan, documents.document_name
+`
+
+type ReadGameReportExtractsRow struct {
+	DocumentID   int64
+	DocumentName string
+	Clan         int64
+	ContentsHash string
+}
+
+func (q *Queries) ReadGameReportExtracts(ctx context.Context, gameID int64) ([]ReadGameReportExtractsRow, error) {
+	rows, err := q.db.QueryContext(ctx, readGameReportExtracts, gameID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadGameReportExtractsRow
+	for rows.Next() {
+		var i ReadGameReportExtractsRow
+		if err := rows.Scan(
+			&i.DocumentID,
+			&i.DocumentName,
+			&i.Clan,
+			&i.ContentsHash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const readGameTurns = `-- name: ReadGameTurns :many
+SELECT game_turns.turn,
+       game_turns.turn_year,
+       game_turns.turn_month,
+       game_turns.turn_no
+FROM game_turns
+WHERE game_turns.game_id = ?1
+ORDER BY game_turns.turn
+`
+
+type ReadGameTurnsRow struct {
+	Turn      string
+	TurnYear  int64
+	TurnMonth int64
+	TurnNo    int64
+}
+
+func (q *Queries) ReadGameTurns(ctx context.Context, gameID int64) ([]ReadGameTurnsRow, error) {
+	rows, err := q.db.QueryContext(ctx, readGameTurns, gameID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReadGameTurnsRow
+	for rows.Next() {
+		var i ReadGameTurnsRow
+		if err := rows.Scan(
+			&i.Turn,
+			&i.TurnYear,
+			&i.TurnMonth,
+			&i.TurnNo,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const readGames = `-- name: ReadGames :many
 SELECT games.game_id,
        games.code,
@@ -238,3 +368,28 @@ func (q *Queries) UpdateGameActiveTurn(ctx context.Context, arg UpdateGameActive
 	_, err := q.db.ExecContext(ctx, updateGameActiveTurn, arg.Turn, arg.UpdatedAt, arg.GameID)
 	return err
 }
+
+const updateGameCalendar = `-- name: UpdateGameCalendar :exec
+UPDATE games
+SET active_turn = ?1,
+    orders_due  = ?2,
+    updated_at  = ?3
+WHERE game_id = ?4
+`
+
+type UpdateGameCalendarParams struct {
+	ActiveTurn string
+	OrdersDue  int64
+	UpdatedAt  int64
+	GameID     int64
+}
+
+func (q *Queries) UpdateGameCalendar(ctx context.Context, arg UpdateGameCalendarParams) error {
+	_, err := q.db.ExecContext(ctx, updateGameCalendar,
+		arg.ActiveTurn,
+		arg.OrdersDue,
+		arg.UpdatedAt,
+		arg.GameID,
+	)
+	return err
+}
@@ -0,0 +1,63 @@
+--  Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+-- name: CreateDocumentLink :one
+INSERT INTO document_links (document_id, revision, created_by, expires_at, created_at, updated_at)
+VALUES (:document_id, :revision, :created_by, :expires_at, :created_at, :updated_at)
+RETURNING link_id;
+
+-- ReadActiveDocumentLink returns the link if it can be used. It doesn't
+-- return links that have expired or been revoked, or links to documents
+-- in the trash.
+--
+-- name: ReadActiveDocumentLink :one
+SELECT link_id
+FROM document_links
+WHERE link_id = :link_id
+  AND document_id = :document_id
+  AND revision = :revision
+  AND revoked_at IS NULL
+  AND expires_at > :now
+  AND document_id IN (SELECT documents.document_id FROM documents WHERE documents.deleted_at IS NULL);
+
+-- name: ReadDocumentLatestRevision :one
+SELECT CAST(COALESCE(MAX(revision), 0) AS INTEGER) AS revision
+FROM document_revisions
+WHERE document_id = :document_id;
+
+-- name: ReadDocumentLinks :many
+SELECT document_links.link_id,
+       document_links.document_id,
+       document_links.revision,
+       users.handle AS created_by,
+       document_links.expires_at,
+       document_links.revoked_at,
+       document_links.access_count,
+       document_links.last_accessed_at,
+       document_links.created_at
+FROM document_links
+         JOIN users ON users.user_id = document_links.created_by
+WHERE document_links.document_id = :document_id
+ORDER BY document_links.link_id;
+
+-- name: RevokeDocumentLink :execrows
+UPDATE document_links
+SET revoked_at = :revoked_at,
+    updated_at = :revoked_at
+WHERE link_id = :link_id
+  AND document_id = :document_id
+  AND revoked_at IS NULL;
+
+-- UpdateDocumentLinkAccess counts a use of the link. It doesn't update
+-- links that have expired or been revoked, or links to documents in the
+-- trash.
+--
+-- name: UpdateDocumentLinkAccess :execrows
+UPDATE document_links
+SET access_count     = access_count + 1,
+    last_accessed_at = :now
+WHERE link_id = :link_id
+  AND document_id = :document_id
+  AND revision = :revision
+  AND revoked_at IS NULL
+  AND expires_at > :now
+  AND document_id IN (SELECT documents.document_id FROM documents WHERE documents.deleted_at IS NULL);
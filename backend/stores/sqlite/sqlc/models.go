@@ -4,6 +4,20 @@
 
 package sqlc
 
+import (
+	"database/sql"
+)
+
+type Blob struct {
+	ContentsHash  string
+	ContentLength int64
+	Encoding      string
+	StoredLength  int64
+	Data          []byte
+	CreatedAt     int64
+	Store         string
+}
+
 type Clan struct {
 	ClanID    int64
 	GameID    int64
@@ -30,17 +44,63 @@ type Document struct {
 	ModifiedAt   int64
 	CreatedAt    int64
 	UpdatedAt    int64
+	DeletedAt    sql.NullInt64
+	DeletedBy    sql.NullInt64
 }
 
 type DocumentContent struct {
 	DocumentID    int64
 	ContentLength int64
 	ContentsHash  string
-	Contents      []byte
 	CreatedAt     int64
 	UpdatedAt     int64
 }
 
+type DocumentLineage struct {
+	UpstreamID   int64
+	DownstreamID int64
+	Relation     string
+	UpstreamHash string
+	StaleAt      sql.NullInt64
+	CreatedAt    int64
+	UpdatedAt    int64
+}
+
+type DocumentLink struct {
+	LinkID         int64
+	DocumentID     int64
+	Revision       int64
+	CreatedBy      int64
+	ExpiresAt      int64
+	RevokedAt      sql.NullInt64
+	AccessCount    int64
+	LastAccessedAt sql.NullInt64
+	CreatedAt      int64
+	UpdatedAt      int64
+}
+
+type DocumentRevision struct {
+	DocumentID    int64
+	Revision      int64
+	ContentLength int64
+	ContentsHash  string
+	UploadedBy    int64
+	Reason        string
+	CreatedAt     int64
+}
+
+type DocumentShare struct {
+	ShareID    int64
+	DocumentID int64
+	ClanID     sql.NullInt64
+	UserID     sql.NullInt64
+	GrantedBy  int64
+	ExpiresAt  sql.NullInt64
+	RevokedAt  sql.NullInt64
+	CreatedAt  int64
+	UpdatedAt  int64
+}
+
 type DocumentType struct {
 	DocumentType string
 	DocumentExt  string
@@ -65,6 +125,7 @@ type Game struct {
 	OrdersDue   int64
 	CreatedAt   int64
 	UpdatedAt   int64
+	Timezone    string
 }
 
 type GameTurn struct {
@@ -77,6 +138,21 @@ type GameTurn struct {
 	UpdatedAt int64
 }
 
+type ReportParse struct {
+	DocumentID   int64
+	ReportFormat string
+	ParserName   string
+	ParserMajor  int64
+	ParserMinor  int64
+	ParserPatch  int64
+	ContentsHash string
+	ParseError   string
+	Output       []byte
+	OutputHash   string
+	CreatedAt    int64
+	UpdatedAt    int64
+}
+
 type SchemaMigration struct {
 	ID          int64
 	MigrationID string
@@ -0,0 +1,108 @@
+-- name: UpsertReportParse :exec
+INSERT INTO report_parses (document_id,
+                           report_format,
+                           parser_name, parser_major, parser_minor, parser_patch,
+                           contents_hash, parse_error, output, output_hash,
+                           created_at, updated_at)
+VALUES (:document_id,
+        :report_format,
+        :parser_name, :parser_major, :parser_minor, :parser_patch,
+        :contents_hash, :parse_error, :output, :output_hash,
+        :created_at, :updated_at)
+ON CONFLICT (document_id)
+    DO UPDATE SET report_format = excluded.report_format,
+                  parser_name   = excluded.parser_name,
+                  parser_major  = excluded.parser_major,
+                  parser_minor  = excluded.parser_minor,
+                  parser_patch  = excluded.parser_patch,
+                  contents_hash = excluded.contents_hash,
+                  parse_error   = excluded.parse_error,
+                  output        = excluded.output,
+                  output_hash   = excluded.output_hash,
+                  updated_at    = excluded.updated_at;
+
+-- name: ReadReportParse :one
+SELECT document_id,
+       report_format,
+       parser_name,
+       parser_major,
+       parser_minor,
+       parser_patch,
+       contents_hash,
+       parse_error,
+       output,
+       output_hash,
+       created_at,
+       updated_at
+FROM report_parses
+WHERE document_id = :document_id;
+
+-- name: ReadReportExtractParse :one
+SELECT documents.document_id,
+       documents.document_name,
+       clans.clan,
+       games.code,
+       document_contents.contents_hash
+FROM documents
+         JOIN clans ON clans.clan_id = documents.clan_id
+         JOIN games ON games.game_id = clans.game_id
+         JOIN document_contents ON document_contents.document_id = documents.document_id
+WHERE documents.document_id = :document_id
+  AND documents.document_type = 'turn-report-extract';
+
+-- name: ReadReportExtractParses :many
+SELECT documents.document_id,
+       documents.document_name,
+       clans.clan,
+       games.code,
+       document_contents.contents_hash,
+       report_parses.report_format,
+       report_parses.parser_name,
+       report_parses.parser_major,
+       report_parses.parser_minor,
+       report_parses.parser_patch,
+       report_parses.contents_hash as parsed_hash,
+       report_parses.output_hash
+FROM documents
+         JOIN clans ON clans.clan_id = documents.clan_id
+         JOIN games ON games.game_id = clans.game_id
+         JOIN document_contents ON document_contents.document_id = documents.document_id
+         LEFT JOIN report_parses ON report_parses.document_id = documents.document_id
+WHERE documents.document_type = 'turn-report-extract'
+  AND documents.deleted_at IS NULL
+ORDER BY games.code, documents.document_name;
+
+-- ReadClanMaps returns the clan's stored maps.
+--
+-- name: ReadClanMaps :many
+SELECT documents.document_id,
+       documents.document_name,
+       document_contents.contents_hash
+FROM documents
+         JOIN clans ON clans.clan_id = documents.clan_id
+         JOIN games ON games.game_id = clans.game_id
+         JOIN document_contents ON document_contents.document_id = documents.document_id
+WHERE games.code = :code
+  AND clans.clan = :clan
+  AND documents.document_type = 'worldographer-map'
+  AND documents.deleted_at IS NULL
+ORDER BY documents.document_name;
+
+-- ReadClanReportParses returns the clan's extracts with the output of
+-- their recorded parse. The output is null if the extract hasn't been parsed.
+--
+-- name: ReadClanReportParses :many
+SELECT documents.document_id,
+       documents.document_name,
+       document_contents.contents_hash,
+       report_parses.output
+FROM documents
+         JOIN clans ON clans.clan_id = documents.clan_id
+         JOIN games ON games.game_id = clans.game_id
+         JOIN document_contents ON document_contents.document_id = documents.document_id
+         LEFT JOIN report_parses ON report_parses.document_id = documents.document_id
+WHERE games.code = :code
+  AND clans.clan = :clan
+  AND documents.document_type = 'turn-report-extract'
+  AND documents.deleted_at IS NULL
+ORDER BY documents.document_name;
@@ -0,0 +1,52 @@
+--  Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+-- name: CreateReportExtractLine :exec
+INSERT INTO report_extract_lines (rowid, line, unit_id, document_id, line_no)
+VALUES ((:document_id << 20) + :line_no, :line, :unit_id, :document_id, :line_no);
+
+-- name: DeleteReportExtractLines :exec
+DELETE
+FROM report_extract_lines
+WHERE rowid BETWEEN (:document_id << 20) AND (:document_id << 20) + 1048575;
+
+-- name: ReadReportExtractsToIndex :many
+SELECT documents.document_id,
+       document_contents.contents_hash
+FROM documents,
+     document_contents
+WHERE documents.document_type = 'turn-report-extract'
+  AND document_contents.document_id = documents.document_id
+ORDER BY documents.document_id;
+
+-- SearchReportExtractsByUser returns the lines that match the query from
+-- the extracts the user owns or that are shared with the user, best match
+-- first. The matched terms in the snippet are wrapped in STX and ETX so
+-- that the caller can escape the text before marking them.
+--
+-- name: SearchReportExtractsByUser :many
+SELECT games.code,
+       clans.clan,
+       documents.document_id,
+       documents.document_name,
+       report_extract_lines.unit_id,
+       report_extract_lines.line_no,
+       snippet(report_extract_lines, 0, char(2), char(3), '…', 24) AS snippet
+FROM report_extract_lines,
+     documents,
+     clans,
+     games
+WHERE report_extract_lines MATCH :query
+  AND documents.document_id = report_extract_lines.document_id
+  AND clans.clan_id = documents.clan_id
+  AND documents.deleted_at IS NULL
+  AND (clans.user_id = :user_id
+    OR EXISTS (SELECT 1
+               FROM document_shares
+               WHERE document_shares.document_id = documents.document_id
+                 AND (document_shares.user_id = :user_id
+                   OR document_shares.clan_id IN (SELECT grantees.clan_id FROM clans AS grantees WHERE grantees.user_id = :user_id))
+                 AND document_shares.revoked_at IS NULL
+                 AND (document_shares.expires_at IS NULL OR document_shares.expires_at > :now)))
+  AND games.game_id = clans.game_id
+ORDER BY rank, documents.document_name, report_extract_lines.line_no
+LIMIT :limit OFFSET :offset;
@@ -0,0 +1,72 @@
+--  Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+-- name: CreateDocumentShare :one
+INSERT INTO document_shares (document_id, clan_id, user_id, granted_by, expires_at, created_at, updated_at)
+VALUES (:document_id, :clan_id, :user_id, :granted_by, :expires_at, :created_at, :updated_at)
+RETURNING share_id;
+
+-- IsDocumentSharedWithUser returns the number of active shares that
+-- give the user access to the document, either directly or through
+-- one of the user's clans.
+--
+-- name: IsDocumentSharedWithUser :one
+SELECT COUNT(*)
+FROM document_shares
+WHERE document_shares.document_id = :document_id
+  AND (document_shares.user_id = :user_id
+    OR document_shares.clan_id IN (SELECT clans.clan_id FROM clans WHERE clans.user_id = :user_id))
+  AND document_shares.revoked_at IS NULL
+  AND (document_shares.expires_at IS NULL OR document_shares.expires_at > :now);
+
+-- name: ReadDocumentShares :many
+SELECT document_shares.share_id,
+       document_shares.document_id,
+       clans.clan,
+       users.handle,
+       grantors.handle AS granted_by,
+       document_shares.expires_at,
+       document_shares.revoked_at,
+       document_shares.created_at
+FROM document_shares
+         LEFT JOIN clans ON clans.clan_id = document_shares.clan_id
+         LEFT JOIN users ON users.user_id = document_shares.user_id
+         JOIN users AS grantors ON grantors.user_id = document_shares.granted_by
+WHERE document_shares.document_id = :document_id
+ORDER BY document_shares.share_id;
+
+-- ReadDocumentsSharedWithUser returns the documents that other users have
+-- shared with the user, directly or through one of the user's clans.
+-- The expiry is the latest of the active shares; null if any never expire.
+--
+-- name: ReadDocumentsSharedWithUser :many
+SELECT clans.game_id,
+       clans.user_id,
+       clans.clan,
+       documents.document_id,
+       documents.clan_id,
+       documents.document_name,
+       documents.document_type,
+       documents.modified_at,
+       documents.created_at,
+       documents.updated_at,
+       CASE WHEN COUNT(*) = COUNT(document_shares.expires_at) THEN MAX(document_shares.expires_at) END AS expires_at
+FROM document_shares,
+     documents,
+     clans
+WHERE (document_shares.user_id = :user_id
+    OR document_shares.clan_id IN (SELECT grantees.clan_id FROM clans AS grantees WHERE grantees.user_id = :user_id))
+  AND document_shares.revoked_at IS NULL
+  AND (document_shares.expires_at IS NULL OR document_shares.expires_at > :now)
+  AND documents.document_id = document_shares.document_id
+  AND documents.deleted_at IS NULL
+  AND clans.clan_id = documents.clan_id
+  AND clans.user_id != :user_id
+GROUP BY documents.document_id;
+
+-- name: RevokeDocumentShare :execrows
+UPDATE document_shares
+SET revoked_at = :revoked_at,
+    updated_at = :revoked_at
+WHERE share_id = :share_id
+  AND document_id = :document_id
+  AND revoked_at IS NULL;
@@ -0,0 +1,81 @@
+--  Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+-- PurgeDeletedDocuments deletes the documents that were moved to the trash
+-- before the cutoff. Contents, revisions, shares, and links go with them;
+-- the blobs are left for the garbage collector.
+--
+-- name: PurgeDeletedDocuments :execrows
+DELETE
+FROM documents
+WHERE deleted_at IS NOT NULL
+  AND deleted_at < :cutoff;
+
+-- name: ReadDeletedDocumentOwner :one
+SELECT clans.game_id,
+       clans.user_id,
+       clans.clan_id,
+       clans.clan,
+       clans.setup_turn,
+       clans.is_active
+FROM documents,
+     clans
+WHERE documents.document_id = :document_id
+  AND clans.clan_id = documents.clan_id
+  AND documents.deleted_at IS NOT NULL;
+
+-- ReadDeletedDocuments returns every document in the trash, oldest first.
+--
+-- name: ReadDeletedDocuments :many
+SELECT games.code,
+       clans.clan,
+       owners.handle   AS owner,
+       documents.document_id,
+       documents.document_name,
+       documents.document_type,
+       documents.deleted_at,
+       deleters.handle AS deleted_by
+FROM documents
+         JOIN clans ON clans.clan_id = documents.clan_id
+         JOIN games ON games.game_id = clans.game_id
+         JOIN users AS owners ON owners.user_id = clans.user_id
+         LEFT JOIN users AS deleters ON deleters.user_id = documents.deleted_by
+WHERE documents.deleted_at IS NOT NULL
+ORDER BY documents.deleted_at, documents.document_id;
+
+-- ReadDeletedDocumentsByUser returns the user's documents in the trash,
+-- oldest first.
+--
+-- name: ReadDeletedDocumentsByUser :many
+SELECT games.code,
+       clans.clan,
+       owners.handle   AS owner,
+       documents.document_id,
+       documents.document_name,
+       documents.document_type,
+       documents.deleted_at,
+       deleters.handle AS deleted_by
+FROM documents
+         JOIN clans ON clans.clan_id = documents.clan_id
+         JOIN games ON games.game_id = clans.game_id
+         JOIN users AS owners ON owners.user_id = clans.user_id
+         LEFT JOIN users AS deleters ON deleters.user_id = documents.deleted_by
+WHERE documents.deleted_at IS NOT NULL
+  AND clans.user_id = :user_id
+ORDER BY documents.deleted_at, documents.document_id;
+
+-- name: RestoreDocument :execrows
+UPDATE documents
+SET deleted_at = NULL,
+    deleted_by = NULL,
+    updated_at = :updated_at
+WHERE document_id = :document_id
+  AND deleted_at IS NOT NULL;
+
+-- name: TrashDocument :execrows
+UPDATE documents
+SET deleted_at = :deleted_at,
+    deleted_by = :deleted_by,
+    updated_at = :deleted_at
+WHERE document_id = :document_id
+  AND clan_id = :clan_id
+  AND deleted_at IS NULL;
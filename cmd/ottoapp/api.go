@@ -10,14 +10,19 @@ import (
 	"time"
 
 	"github.com/playbymail/ottoapp"
+	"github.com/playbymail/ottoapp/backend/domains"
 	"github.com/playbymail/ottoapp/backend/iana"
 	"github.com/playbymail/ottoapp/backend/servers/rest"
 	"github.com/playbymail/ottoapp/backend/services/authn"
 	"github.com/playbymail/ottoapp/backend/services/authz"
+	"github.com/playbymail/ottoapp/backend/services/dag"
 	"github.com/playbymail/ottoapp/backend/services/documents"
 	"github.com/playbymail/ottoapp/backend/services/games"
+	"github.com/playbymail/ottoapp/backend/services/reports"
+	"github.com/playbymail/ottoapp/backend/services/search"
 	"github.com/playbymail/ottoapp/backend/services/users"
 	"github.com/playbymail/ottoapp/backend/sessions"
+	"github.com/playbymail/ottoapp/backend/stores/blobs"
 	"github.com/playbymail/ottoapp/backend/stores/sqlite"
 	"github.com/playbymail/ottoapp/backend/versions"
 	"github.com/spf13/cobra"
@@ -108,15 +113,34 @@ var cmdApiServe = &cobra.Command{
 			return errors.Join(fmt.Errorf("iana.new"), err)
 		}
 		usersSvc := users.New(db, authnSvc, authzSvc, tzSvc) // uses sqlite + domains
-		documentsSvc, err := documents.New(db, authzSvc, usersSvc, quiet, verbose, debug)
+		blobStore := blobs.New(db)
+		reportsSvc := reports.New(db, blobStore, nil, nil)
+		documentsSvc, err := documents.New(db, authzSvc, usersSvc, reportsSvc, blobStore, quiet, verbose, debug)
 		if err != nil {
 			return errors.Join(fmt.Errorf("sessions.new"), err)
 		}
+
+		// empty the document trash once a day
+		go func(actor *domains.Actor) {
+			ticker := time.NewTicker(24 * time.Hour)
+			for ; true; <-ticker.C {
+				if n, err := documentsSvc.PurgeDocuments(actor, documents.TrashRetention, false, quiet, verbose, debug); err != nil {
+					log.Printf("[serve] purge: %v\n", err)
+				} else if n != 0 {
+					log.Printf("[serve] purge: purged %d documents\n", n)
+				}
+			}
+		}(&domains.Actor{ID: authz.SysopId, Roles: domains.Roles{Sysop: true}})
 		sessionsSvc, err := sessions.New(db, authnSvc, authzSvc, usersSvc, 24*time.Hour, 15*time.Minute)
 		if err != nil {
 			return errors.Join(fmt.Errorf("sessions.new"), err)
 		}
-		gamesSvc, err := games.New(db, authnSvc, authzSvc, usersSvc, quiet, verbose, debug)
+		gamesSvc, err := games.New(db, authnSvc, authzSvc, usersSvc, blobStore, quiet, verbose, debug)
+		if err != nil {
+			return err
+		}
+		searchSvc := search.New(db, authzSvc, blobStore)
+		dagSvc, err := dag.New(db)
 		if err != nil {
 			return err
 		}
@@ -134,7 +158,7 @@ var cmdApiServe = &cobra.Command{
 			//}
 		}
 
-		s, err := rest.New(authnSvc, authzSvc, documentsSvc, gamesSvc, sessionsSvc, tzSvc, usersSvc, versionSvc, options...)
+		s, err := rest.New(authnSvc, authzSvc, dagSvc, documentsSvc, gamesSvc, reportsSvc, searchSvc, sessionsSvc, tzSvc, usersSvc, versionSvc, options...)
 		if err != nil {
 			return errors.Join(fmt.Errorf("rest.new"), err)
 		}
@@ -8,6 +8,13 @@ import (
 	"log"
 	"time"
 
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/services/authz"
+	"github.com/playbymail/ottoapp/backend/services/config"
+	"github.com/playbymail/ottoapp/backend/services/dag"
+	"github.com/playbymail/ottoapp/backend/services/documents"
+	"github.com/playbymail/ottoapp/backend/services/search"
+	"github.com/playbymail/ottoapp/backend/stores/blobs"
 	"github.com/playbymail/ottoapp/backend/stores/sqlite"
 	"github.com/spf13/cobra"
 )
@@ -22,11 +29,16 @@ func cmdDb() *cobra.Command {
 		Long:  `Manage the OttoMap database including migrations and seeding.`,
 	}
 	cmd.AddCommand(cmdDbBackup())
+	cmd.AddCommand(cmdDbBlobs())
 	cmd.AddCommand(cmdDbClone())
 	cmd.AddCommand(cmdDbCompact())
 	cmd.AddCommand(cmdDbCreate())
+	cmd.AddCommand(cmdDbGc())
 	cmd.AddCommand(cmdDbInit())
 	cmd.AddCommand(cmdDbMigrate())
+	cmd.AddCommand(cmdDbPurge())
+	cmd.AddCommand(cmdDbReindex())
+	cmd.AddCommand(cmdDbRelink())
 	//cmd.AddCommand(cmdDbSeed())
 	cmd.AddCommand(cmdDbVersion())
 	err := addFlags(cmd)
@@ -78,6 +90,94 @@ func cmdDbBackup() *cobra.Command {
 	return cmd
 }
 
+func cmdDbBlobs() *cobra.Command {
+	addFlags := func(cmd *cobra.Command) error {
+		return nil
+	}
+	cmd := &cobra.Command{
+		Use:   "blobs",
+		Short: "Document blob storage commands",
+		Long:  `Manage where document contents are stored.`,
+	}
+	cmd.AddCommand(cmdDbBlobsMigrate())
+	err := addFlags(cmd)
+	if err != nil {
+		log.Fatalf("db: blobs: %v\n", err)
+	}
+	return cmd
+}
+
+func cmdDbBlobsMigrate() *cobra.Command {
+	dryRun := false
+	path := ""
+	to := ""
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().BoolVar(&dryRun, "dry-run", dryRun, "verify the blobs that would be moved without moving them")
+		cmd.Flags().StringVar(&path, "path", path, "directory for the disk store (relative to the database directory)")
+		cmd.Flags().StringVar(&to, "to", to, "store to move blobs to (sqlite or disk)")
+		if err := cmd.MarkFlagRequired("to"); err != nil {
+			return err
+		}
+		return nil
+	}
+	cmd := &cobra.Command{
+		Use:          "migrate",
+		Short:        "Move document blobs to another store",
+		Long:         `Move document blobs between the database and the disk store, verifying each hash, and make that store the one for new uploads.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			const checkVersion = true
+			quiet, _ := cmd.Flags().GetBool("quiet")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			debug, _ := cmd.Flags().GetBool("debug")
+			if quiet {
+				verbose = false
+			}
+			if to != blobs.SQLite && to != blobs.Disk {
+				return fmt.Errorf("--to must be %q or %q", blobs.SQLite, blobs.Disk)
+			}
+
+			dbPath, err := cmd.Flags().GetString("db")
+			if err != nil {
+				return err
+			}
+			db, err := sqlite.Open(context.Background(), dbPath, checkVersion, quiet, verbose, debug)
+			if err != nil {
+				log.Fatalf("db: open: %v\n", err)
+			}
+			defer func() {
+				_ = db.Close()
+			}()
+
+			if path != "" && !dryRun {
+				configSvc, err := config.New(db)
+				if err != nil {
+					log.Fatalf("db: blobs: migrate: %v\n", err)
+				}
+				if err := configSvc.UpdateKeyValue(blobs.PathKey, path); err != nil {
+					log.Fatalf("db: blobs: migrate: %v\n", err)
+				}
+			}
+
+			stats, err := blobs.New(db).Migrate(db, to, dryRun, quiet, verbose, debug)
+			if err != nil {
+				log.Fatalf("db: blobs: migrate: %v\n", err)
+			}
+			if dryRun {
+				log.Printf("db: blobs: migrate: verified %d blobs\n", stats.Verified)
+			} else {
+				log.Printf("db: blobs: migrate: moved %d blobs (%d bytes) to %s\n", stats.Moved, stats.MovedBytes, to)
+			}
+			return nil
+		},
+	}
+	err := addFlags(cmd)
+	if err != nil {
+		log.Fatalf("db: blobs: migrate: %v\n", err)
+	}
+	return cmd
+}
+
 func cmdDbClone() *cobra.Command {
 	addFlags := func(cmd *cobra.Command) error {
 		return nil
@@ -168,6 +268,57 @@ func cmdDbCreate() *cobra.Command {
 	return cmd
 }
 
+func cmdDbGc() *cobra.Command {
+	dryRun := false
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().BoolVar(&dryRun, "dry-run", dryRun, "report what would be collected without changing the database")
+		return nil
+	}
+	cmd := &cobra.Command{
+		Use:          "gc",
+		Short:        "Collect unreferenced document blobs",
+		Long:         `Delete document blobs that no document or revision references and compress text blobs that were stored uncompressed.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			const checkVersion = true
+			quiet, _ := cmd.Flags().GetBool("quiet")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			debug, _ := cmd.Flags().GetBool("debug")
+			if quiet {
+				verbose = false
+			}
+
+			dbPath, err := cmd.Flags().GetString("db")
+			if err != nil {
+				return err
+			}
+			db, err := sqlite.Open(context.Background(), dbPath, checkVersion, quiet, verbose, debug)
+			if err != nil {
+				log.Fatalf("db: open: %v\n", err)
+			}
+			defer func() {
+				_ = db.Close()
+			}()
+
+			stats, err := blobs.New(db).Collect(db, dryRun, quiet, verbose, debug)
+			if err != nil {
+				log.Fatalf("db: gc: %v\n", err)
+			}
+			verb := "collected"
+			if dryRun {
+				verb = "would collect"
+			}
+			log.Printf("db: gc: %s %d blobs (%d bytes), compressed %d blobs (%d bytes saved)\n", verb, stats.Deleted, stats.DeletedBytes, stats.Compressed, stats.SavedBytes)
+			return nil
+		},
+	}
+	err := addFlags(cmd)
+	if err != nil {
+		log.Fatalf("db: gc: %v\n", err)
+	}
+	return cmd
+}
+
 func cmdDbInit() *cobra.Command {
 	overwrite := false
 	addFlags := func(cmd *cobra.Command) error {
@@ -310,6 +461,149 @@ func cmdDbMigrateStatus() *cobra.Command {
 	return cmd
 }
 
+func cmdDbPurge() *cobra.Command {
+	dryRun := false
+	retention := documents.TrashRetention
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().BoolVar(&dryRun, "dry-run", dryRun, "report what would be purged without changing the database")
+		cmd.Flags().DurationVar(&retention, "retention", retention, "purge documents that have been in the trash this long")
+		return nil
+	}
+	cmd := &cobra.Command{
+		Use:          "purge",
+		Short:        "Empty the document trash",
+		Long:         `Delete the documents that have been in the trash for longer than the retention window, along with their revisions, shares, and links.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			const checkVersion = true
+			quiet, _ := cmd.Flags().GetBool("quiet")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			debug, _ := cmd.Flags().GetBool("debug")
+			if quiet {
+				verbose = false
+			}
+			if retention < 0 {
+				return fmt.Errorf("retention must not be negative")
+			}
+
+			dbPath, err := cmd.Flags().GetString("db")
+			if err != nil {
+				return err
+			}
+			db, err := sqlite.Open(context.Background(), dbPath, checkVersion, quiet, verbose, debug)
+			if err != nil {
+				log.Fatalf("db: open: %v\n", err)
+			}
+			defer func() {
+				_ = db.Close()
+			}()
+
+			documentsSvc, err := documents.New(db, nil, nil, nil, nil, quiet, verbose, debug)
+			if err != nil {
+				log.Fatalf("db: purge: %v\n", err)
+			}
+			actor := &domains.Actor{ID: authz.SysopId, Roles: domains.Roles{Sysop: true}}
+			purged, err := documentsSvc.PurgeDocuments(actor, retention, dryRun, quiet, verbose, debug)
+			if err != nil {
+				log.Fatalf("db: purge: %v\n", err)
+			}
+			verb := "purged"
+			if dryRun {
+				verb = "would purge"
+			}
+			log.Printf("db: purge: %s %d documents\n", verb, purged)
+			return nil
+		},
+	}
+	err := addFlags(cmd)
+	if err != nil {
+		log.Fatalf("db: purge: %v\n", err)
+	}
+	return cmd
+}
+
+func cmdDbReindex() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "reindex",
+		Short:        "Rebuild the search index",
+		Long:         `Rebuild the full-text search index from the latest contents of every turn report extract.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			const checkVersion = true
+			quiet, _ := cmd.Flags().GetBool("quiet")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			debug, _ := cmd.Flags().GetBool("debug")
+			if quiet {
+				verbose = false
+			}
+
+			dbPath, err := cmd.Flags().GetString("db")
+			if err != nil {
+				return err
+			}
+			db, err := sqlite.Open(context.Background(), dbPath, checkVersion, quiet, verbose, debug)
+			if err != nil {
+				log.Fatalf("db: open: %v\n", err)
+			}
+			defer func() {
+				_ = db.Close()
+			}()
+
+			actor := &domains.Actor{ID: authz.SysopId, Roles: domains.Roles{Sysop: true}}
+			indexed, err := search.New(db, nil, nil).Reindex(actor, quiet, verbose, debug)
+			if err != nil {
+				log.Fatalf("db: reindex: %v\n", err)
+			}
+			log.Printf("db: reindex: indexed %d extracts\n", indexed)
+			return nil
+		},
+	}
+	return cmd
+}
+
+func cmdDbRelink() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "relink",
+		Short:        "Rebuild the document lineage",
+		Long:         `Add the missing lineage edges between turn report files, report extracts, and maps.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			const checkVersion = true
+			quiet, _ := cmd.Flags().GetBool("quiet")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			debug, _ := cmd.Flags().GetBool("debug")
+			if quiet {
+				verbose = false
+			}
+
+			dbPath, err := cmd.Flags().GetString("db")
+			if err != nil {
+				return err
+			}
+			db, err := sqlite.Open(context.Background(), dbPath, checkVersion, quiet, verbose, debug)
+			if err != nil {
+				log.Fatalf("db: open: %v\n", err)
+			}
+			defer func() {
+				_ = db.Close()
+			}()
+
+			dagSvc, err := dag.New(db)
+			if err != nil {
+				log.Fatalf("db: relink: %v\n", err)
+			}
+			actor := &domains.Actor{ID: authz.SysopId, Roles: domains.Roles{Sysop: true}}
+			linked, err := dagSvc.Relink(actor, quiet, verbose, debug)
+			if err != nil {
+				log.Fatalf("db: relink: %v\n", err)
+			}
+			log.Printf("db: relink: linked %d documents\n", linked)
+			return nil
+		},
+	}
+	return cmd
+}
+
 func cmdDbVersion() *cobra.Command {
 	addFlags := func(cmd *cobra.Command) error {
 		return nil
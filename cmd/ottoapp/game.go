@@ -3,9 +3,15 @@
 package main
 
 import (
+	"context"
 	_ "embed"
+	"errors"
+	"fmt"
 	"log"
+	"time"
 
+	"github.com/playbymail/ottoapp/backend/services/games"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +25,7 @@ func cmdGame() *cobra.Command {
 		SilenceUsage: true,
 	}
 
+	cmd.AddCommand(cmdGameCalendar())
 	cmd.AddCommand(cmdGameImport())
 
 	cmd.AddCommand(cmdGameUpload)
@@ -38,6 +45,100 @@ func cmdGame() *cobra.Command {
 	return cmd
 }
 
+func cmdGameCalendar() *cobra.Command {
+	var apply bool
+	var dueTime string
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().BoolVar(&apply, "apply", apply, "save the changes (refused if the reports disagree)")
+		cmd.Flags().StringVar(&dueTime, "due-time", dueTime, "time of day orders are due, HH:MM in the game's time zone (default is the time of the current deadline)")
+		return nil
+	}
+	cmd := &cobra.Command{
+		Use:          "calendar <game>",
+		Short:        "sync the game calendar from the turn reports",
+		Long:         `Read the "Current Turn" and "Next Turn" lines in the game's report extracts and propose changes to the active turn, the game turns, and the date that orders are due. The active turn is the first turn whose orders are due after now.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1), // require game code
+		RunE: func(cmd *cobra.Command, args []string) error {
+			const checkVersion = true
+			quiet, _ := cmd.Flags().GetBool("quiet")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			debug, _ := cmd.Flags().GetBool("debug")
+			if quiet {
+				verbose = false
+			}
+
+			dbPath, err := cmd.Flags().GetString("db")
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+			db, err := sqlite.Open(ctx, dbPath, checkVersion, quiet, verbose, debug)
+			if err != nil {
+				log.Fatalf("db: open: %v\n", err)
+			}
+			defer func() {
+				_ = db.Close()
+			}()
+
+			gamesSvc, err := games.New(db, nil, nil, nil, nil, quiet, verbose, debug)
+			if err != nil {
+				return err
+			}
+			gamesList, err := gamesSvc.ReadGames()
+			if err != nil {
+				return err
+			}
+			var cal *games.Calendar
+			for _, game := range gamesList {
+				if game.Code == args[0] {
+					cal, err = gamesSvc.SyncCalendar(game.ID, dueTime, apply, quiet, verbose, debug)
+					break
+				}
+			}
+			if cal == nil && err == nil {
+				return fmt.Errorf("%s: game not found", args[0])
+			} else if err != nil && !errors.Is(err, games.ErrCalendarDisagreement) && !errors.Is(err, games.ErrDueTimeNotSet) {
+				return err
+			}
+
+			for _, d := range cal.Disagreements {
+				fmt.Printf("disagree: %s\n", d)
+			}
+			if !cal.HasChanges() {
+				fmt.Printf("%s: calendar is up to date\n", cal.Code)
+				return err
+			}
+			if cal.NewActiveTurn != "" {
+				fmt.Printf("%s: active turn: %s => %s\n", cal.Code, cal.ActiveTurn, cal.NewActiveTurn)
+			}
+			if !cal.NewOrdersDue.IsZero() {
+				fmt.Printf("%s: orders due: %s => %s\n", cal.Code, formatOrdersDue(cal.OrdersDue), formatOrdersDue(cal.NewOrdersDue))
+			}
+			for _, t := range cal.NewTurns {
+				fmt.Printf("%s: turn: %s is turn #%d\n", cal.Code, t.Turn, t.TurnNo)
+			}
+			if err != nil {
+				return err
+			} else if !apply {
+				fmt.Printf("%s: run with --apply to save the changes\n", cal.Code)
+			}
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatalf("%s: %v\n", cmd.Use, err)
+	}
+	return cmd
+}
+
+func formatOrdersDue(t time.Time) string {
+	if t.IsZero() {
+		return "(not set)"
+	}
+	return t.Format("2006-01-02 15:04 MST")
+}
+
 func cmdGameImport() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:          "import <path>",
@@ -0,0 +1,37 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/playbymail/ottoapp"
+	"github.com/playbymail/ottoapp/backend/servers/lsp"
+	"github.com/spf13/cobra"
+)
+
+// cmdLsp runs the language server for report extracts. Editors start it
+// and talk to it over stdin and stdout, so nothing else may be written
+// to stdout. Logging goes to stderr.
+func cmdLsp() *cobra.Command {
+	addFlags := func(cmd *cobra.Command) error {
+		return nil
+	}
+	var cmd = &cobra.Command{
+		Use:          "lsp",
+		Short:        "Run the language server for turn report extracts",
+		Long:         `Run a language server (JSON-RPC over stdio) that checks report extracts and supports hover, go-to-definition, and document symbols.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			debug, _ := cmd.Flags().GetBool("debug")
+			log.SetOutput(os.Stderr)
+			return lsp.New(os.Stdin, os.Stdout, ottoapp.Version().Core(), debug).Serve()
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatalf("%s: %v\n", cmd.Use, err)
+	}
+	return cmd
+}
@@ -107,17 +107,28 @@ func main() {
 
 	cmdRoot.AddCommand(cmdGenerate())
 
+	cmdRoot.AddCommand(cmdLsp())
+
 	cmdRoot.AddCommand(cmdPhrase())
 
 	var cmdReport = &cobra.Command{
-		Use:   "report",
-		Short: "report management",
+		Use:     "report",
+		Aliases: []string{"reports"},
+		Short:   "report management",
 	}
 	cmdRoot.AddCommand(cmdReport)
 	cmdReport.AddCommand(cmdReportExtract)
 	cmdReportExtract.Flags().String("output", "report.txt", "file to create")
+	cmdReportExtract.Flags().Bool("headers-footers", false, "include page headers and footers")
+	cmdReportExtract.Flags().Bool("show-report", false, "list tables, text boxes, and skipped content")
 	cmdReport.AddCommand(cmdReportParse)
 	cmdReportParse.Flags().Bool("docxml-only", false, "parse to DocXML only")
+	cmdReport.AddCommand(cmdReportFmt())
+	cmdReport.AddCommand(cmdReportParity())
+	cmdReport.AddCommand(cmdReportReparse())
+	cmdReport.AddCommand(cmdReportVerify())
+	cmdReport.AddCommand(cmdReportGenerate())
+	cmdReport.AddCommand(cmdReportAnonymize())
 
 	cmdRoot.AddCommand(cmdRun())
 	cmdRoot.AddCommand(cmdTest())
@@ -0,0 +1,152 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/playbymail/ottoapp/backend/domains"
+	"github.com/playbymail/ottoapp/backend/parsers/parity"
+	"github.com/playbymail/ottoapp/backend/services/reports"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func cmdReportParity() *cobra.Command {
+	var stored, showDiffs bool
+	var game string
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().BoolVar(&stored, "stored", stored, "check the turn report extracts stored in the database")
+		cmd.Flags().StringVar(&game, "game", game, "only check stored extracts for this game")
+		cmd.Flags().BoolVar(&showDiffs, "show-diffs", true, "list every difference")
+		cmd.Flags().Bool("show-timing", true, "time command")
+		return nil
+	}
+	cmd := &cobra.Command{
+		Use:          "parity [path...]",
+		Short:        "compare the azul and bistre parsers",
+		Long:         `Run the azul and bistre parsers over a corpus of report extracts (files, folders of .txt files, or the extracts stored in the database) and report the differences for every unit and step.`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			quiet, _ := cmd.Flags().GetBool("quiet")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			debug, _ := cmd.Flags().GetBool("debug")
+			if quiet {
+				verbose = false
+			}
+			showTiming, _ := cmd.Flags().GetBool("show-timing")
+			if !stored && len(args) == 0 {
+				return fmt.Errorf("expected paths or --stored")
+			}
+			started := time.Now()
+
+			type corpusEntry struct {
+				name string
+				load func() ([]byte, error)
+			}
+			var corpus []corpusEntry
+			for _, arg := range args {
+				sb, err := os.Stat(arg)
+				if err != nil {
+					return err
+				}
+				var paths []string
+				if sb.IsDir() {
+					paths, err = filepath.Glob(filepath.Join(arg, "*.txt"))
+					if err != nil {
+						return err
+					}
+					sort.Strings(paths)
+				} else {
+					paths = append(paths, arg)
+				}
+				for _, path := range paths {
+					corpus = append(corpus, corpusEntry{name: path, load: func() ([]byte, error) {
+						return os.ReadFile(path)
+					}})
+				}
+			}
+			if stored {
+				const checkVersion = true
+				dbPath, err := cmd.Flags().GetString("db")
+				if err != nil {
+					return err
+				}
+				db, err := sqlite.Open(context.Background(), dbPath, checkVersion, quiet, verbose, debug)
+				if err != nil {
+					log.Fatalf("db: open: %v\n", err)
+				}
+				defer func() {
+					_ = db.Close()
+				}()
+				reportsSvc := reports.New(db, nil, nil, nil)
+				list, err := reportsSvc.ReadParseStatus()
+				if err != nil {
+					return err
+				}
+				for _, ps := range list {
+					if game != "" && ps.Game != game {
+						continue
+					}
+					corpus = append(corpus, corpusEntry{name: ps.DocumentName, load: func() ([]byte, error) {
+						_, contents, err := reportsSvc.ReadExtract(ps.DocumentID)
+						return contents, err
+					}})
+				}
+			}
+
+			var agree, disagree, failed, diffs int
+			for _, entry := range corpus {
+				text, err := entry.load()
+				if err != nil {
+					return err
+				}
+				result := parity.Check(filepath.Base(entry.name), domains.TurnIdFromName(filepath.Base(entry.name)), text)
+				switch {
+				case result.AzulErr != nil || result.BistreErr != nil:
+					failed++
+					if result.AzulErr != nil {
+						fmt.Printf("%s: %v\n", entry.name, result.AzulErr)
+					}
+					if result.BistreErr != nil {
+						fmt.Printf("%s: %v\n", entry.name, result.BistreErr)
+					}
+				case len(result.Diffs) == 0:
+					agree++
+					if verbose {
+						fmt.Printf("%s: ok\n", entry.name)
+					}
+				default:
+					disagree++
+					diffs += len(result.Diffs)
+					fmt.Printf("%s: %d differences\n", entry.name, len(result.Diffs))
+					if showDiffs {
+						var sb strings.Builder
+						for _, d := range result.Diffs {
+							sb.WriteString("  ")
+							sb.WriteString(d.String())
+							sb.WriteByte('\n')
+						}
+						fmt.Print(sb.String())
+					}
+				}
+			}
+			fmt.Printf("parity: %d reports: %d agree: %d disagree (%d differences): %d failed\n", len(corpus), agree, disagree, diffs, failed)
+			if showTiming {
+				log.Printf("parity: completed in %v\n", time.Since(started))
+			}
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatalf("%s: %v\n", cmd.Use, err)
+	}
+	return cmd
+}
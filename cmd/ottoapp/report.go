@@ -27,12 +27,24 @@ var cmdReportExtract = &cobra.Command{
 			return err
 		}
 
-		var docx *parsers.Docx
+		headersFooters, err := cmd.Flags().GetBool("headers-footers")
+		if err != nil {
+			return err
+		}
+		showReport, err := cmd.Flags().GetBool("show-report")
+		if err != nil {
+			return err
+		}
+
+		var docx *office.WordDocument
 		if input, err := os.ReadFile(path); err != nil {
 			log.Fatal(err)
-		} else if docx, err = parsers.ParseDocx(bytes.NewReader(input), false, false); err != nil {
+		} else if docx, err = office.Extract(input, office.Options{HeadersFooters: headersFooters}); err != nil {
 			log.Fatal(err)
 		}
+		if showReport {
+			fmt.Printf("%s: %s", path, docx.Report)
+		}
 
 		output := &bytes.Buffer{}
 		for _, line := range bytes.Split(docx.Text, []byte{'\n'}) {
@@ -69,7 +81,7 @@ var cmdReportParse = &cobra.Command{
 			}
 			fmt.Printf("%s\n", string(p))
 		} else {
-			docx, err := parsers.ParseDocx(bytes.NewReader(input), true, true)
+			docx, err := parsers.ParseReport(input, true, true)
 			if err != nil {
 				return errors.Join(fmt.Errorf("parser: parse file"), err)
 			}
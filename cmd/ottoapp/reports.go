@@ -0,0 +1,345 @@
+// Copyright (c) 2025 Michael D Henderson. All rights reserved.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/playbymail/ottoapp/backend/parsers/anonymizer"
+	"github.com/playbymail/ottoapp/backend/parsers/snapshots"
+	"github.com/playbymail/ottoapp/backend/parsers/synthetic"
+	"github.com/playbymail/ottoapp/backend/services/reports"
+	"github.com/playbymail/ottoapp/backend/services/reports/cst"
+	"github.com/playbymail/ottoapp/backend/stores/sqlite"
+	"github.com/spf13/cobra"
+)
+
+func cmdReportReparse() *cobra.Command {
+	var opts reports.ReparseOptions
+	ottomapPath := "ottomap"
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().BoolVar(&opts.All, "all", opts.All, "re-parse every report, not just the stale ones")
+		cmd.Flags().BoolVar(&opts.DryRun, "dry-run", opts.DryRun, "parse and compare without storing the results")
+		cmd.Flags().IntVar(&opts.Workers, "workers", opts.Workers, "number of parsers to run at once (default is number of CPUs)")
+		cmd.Flags().StringVar(&ottomapPath, "ottomap", ottomapPath, "path to the ottomap command that renders the maps")
+		cmd.Flags().Bool("show-timing", true, "time command")
+		return nil
+	}
+	cmd := &cobra.Command{
+		Use:          "reparse",
+		Short:        "re-parse stored turn reports after a parser upgrade",
+		Long:         `Re-parse the stored turn report extracts whose parse was made by an older parser and compare the new output with the previous output. The maps of the clans whose output changed are rendered again and compared with the stored maps; only the clans whose maps are different are listed. The stored maps are not replaced.`,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			const checkVersion = true
+			quiet, _ := cmd.Flags().GetBool("quiet")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			debug, _ := cmd.Flags().GetBool("debug")
+			if quiet {
+				verbose = false
+			}
+			showTiming, _ := cmd.Flags().GetBool("show-timing")
+
+			if _, err := exec.LookPath(ottomapPath); err != nil {
+				return err
+			}
+
+			dbPath, err := cmd.Flags().GetString("db")
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+			db, err := sqlite.Open(ctx, dbPath, checkVersion, quiet, verbose, debug)
+			if err != nil {
+				log.Fatalf("db: open: %v\n", err)
+			}
+			defer func() {
+				_ = db.Close()
+			}()
+
+			reportsSvc := reports.New(db, nil, nil, &reports.Ottomap{Path: ottomapPath})
+			summary, err := reportsSvc.Reparse(opts, quiet, verbose, debug)
+			if err != nil {
+				return err
+			}
+
+			for _, r := range summary.Results {
+				if r.Error != "" {
+					log.Printf("%s: %s\n", r.DocumentName, r.Error)
+				} else if verbose {
+					log.Printf("%s: %s: changed %v\n", r.DocumentName, r.Reason, r.Changed)
+				}
+			}
+			if len(summary.StaleMaps) == 0 {
+				fmt.Printf("no maps are out of date\n")
+			} else {
+				fmt.Printf("maps are out of date for %d clans\n", len(summary.StaleMaps))
+				for _, sm := range summary.StaleMaps {
+					if sm.Error != "" {
+						fmt.Printf("  %s.%04d: %s\n", sm.Game, sm.ClanNo, sm.Error)
+						continue
+					}
+					fmt.Printf("  %s.%04d: %d turns, starting %s\n", sm.Game, sm.ClanNo, len(sm.Turns), sm.Turns[0])
+				}
+			}
+			if opts.DryRun {
+				fmt.Printf("dry run: results were not stored\n")
+			}
+			if showTiming {
+				log.Printf("reparse: %d checked: %d reparsed: %d changed: %d failed: completed in %v\n", summary.Checked, summary.Reparsed, summary.Changed, summary.Failed, summary.Elapsed)
+			}
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatalf("%s: %v\n", cmd.Use, err)
+	}
+	return cmd
+}
+
+func cmdReportFmt() *cobra.Command {
+	var write, list bool
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().BoolVarP(&write, "write", "w", write, "write the result to the file instead of stdout")
+		cmd.Flags().BoolVarP(&list, "list", "l", list, "list the files whose formatting differs")
+		return nil
+	}
+	cmd := &cobra.Command{
+		Use:          "fmt <report-extract>...",
+		Short:        "format turn report extracts",
+		Long:         `Rewrite the unit, turn, and movement lines in report extracts with single spaces and the canonical spellings for directions and terrain. The formatted lines are parsed again and must give the same tree, so formatting never changes what the parser sees. Other lines are kept with trailing white space removed.`,
+		SilenceUsage: true,
+		Args:         cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			failed := 0
+			for _, path := range args {
+				input, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				output, err := cst.FormatReport(input)
+				if err != nil {
+					log.Printf("%s: %v\n", path, err)
+					failed++
+					continue
+				}
+				changed := !bytes.Equal(input, output)
+				if list && changed {
+					fmt.Println(path)
+				}
+				if write {
+					if changed {
+						if err := os.WriteFile(path, output, 0o644); err != nil {
+							return err
+						}
+					}
+				} else if !list {
+					_, _ = os.Stdout.Write(output)
+				}
+			}
+			if failed != 0 {
+				return fmt.Errorf("%d reports could not be formatted", failed)
+			}
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatalf("%s: %v\n", cmd.Use, err)
+	}
+	return cmd
+}
+
+func cmdReportVerify() *cobra.Command {
+	var update, showDiffs bool
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().BoolVar(&update, "update", update, "create missing snapshots and replace drifted ones")
+		cmd.Flags().BoolVar(&showDiffs, "show-diffs", true, "show the differences for drifted units")
+		return nil
+	}
+	cmd := &cobra.Command{
+		Use:          "verify <path-to-corpus>",
+		Short:        "verify the parser against report snapshots",
+		Long:         `Parse every report extract (*.txt) in the folder and compare the output with the expected snapshot (the same name with a .json extension). Reports with drift are listed unit by unit.`,
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1), // require path to corpus
+		RunE: func(cmd *cobra.Command, args []string) error {
+			results, err := snapshots.Verify(args[0], update)
+			if err != nil {
+				return err
+			}
+			counts := map[snapshots.Status]int{}
+			for _, r := range results {
+				counts[r.Status]++
+				switch r.Status {
+				case snapshots.Match:
+				case snapshots.Failed:
+					fmt.Printf("%s: %s: %v\n", r.Name, r.Status, r.Err)
+				case snapshots.Drift:
+					fmt.Printf("%s: %s: %d units\n", r.Name, r.Status, len(r.Drift))
+					for _, d := range r.Drift {
+						unit := d.Unit
+						if unit == "" {
+							unit = "turn"
+						}
+						if showDiffs {
+							fmt.Printf("  %s: (-snapshot +parser)\n%s", unit, d.Diff)
+						} else {
+							fmt.Printf("  %s\n", unit)
+						}
+					}
+				default:
+					fmt.Printf("%s: %s\n", r.Name, r.Status)
+				}
+			}
+			fmt.Printf("verify: %d reports: %d match: %d drift: %d missing: %d updated: %d failed\n",
+				len(results), counts[snapshots.Match], counts[snapshots.Drift], counts[snapshots.Missing], counts[snapshots.Updated], counts[snapshots.Failed])
+			if counts[snapshots.Drift] != 0 || counts[snapshots.Missing] != 0 || counts[snapshots.Failed] != 0 {
+				return fmt.Errorf("verify: snapshots do not match")
+			}
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatalf("%s: %v\n", cmd.Use, err)
+	}
+	return cmd
+}
+
+func cmdReportGenerate() *cobra.Command {
+	var opts synthetic.Options
+	var output string
+	var docx, roundTrip bool
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().Uint64Var(&opts.Seed, "seed", opts.Seed, "seed for the synthetic world")
+		cmd.Flags().IntVar(&opts.Clan, "clan", opts.Clan, "clan number (default is random)")
+		cmd.Flags().StringVar(&opts.TurnId, "turn", opts.TurnId, "turn as yyyy-mm (default is random)")
+		cmd.Flags().IntVar(&opts.Units, "units", opts.Units, "number of units in the report, 1...40 (0 picks the default of 5)")
+		cmd.Flags().StringVar(&output, "output", ".", "folder to create the report in")
+		cmd.Flags().BoolVar(&docx, "docx", docx, "also create the report as a Word document")
+		cmd.Flags().BoolVar(&roundTrip, "round-trip", roundTrip, "parse the report and check it against the generator")
+		return nil
+	}
+	cmd := &cobra.Command{
+		Use:          "generate",
+		Short:        "generate a synthetic turn report",
+		Long:         `Generate a turn report from a synthetic world. The same seed always creates the same report.`,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			m, err := synthetic.Generate(opts)
+			if err != nil {
+				return err
+			}
+			base := filepath.Join(output, fmt.Sprintf("%s.%s.report", m.TurnId, m.Clan))
+			if err := os.WriteFile(base+".txt", m.Text(), 0o644); err != nil {
+				return err
+			}
+			fmt.Printf("%s.txt: created\n", base)
+			if docx {
+				data, err := m.Docx()
+				if err != nil {
+					return err
+				} else if err = os.WriteFile(base+".docx", data, 0o644); err != nil {
+					return err
+				}
+				fmt.Printf("%s.docx: created\n", base)
+			}
+			if roundTrip {
+				diffs, err := synthetic.RoundTrip(m)
+				if err != nil {
+					return err
+				}
+				for _, diff := range diffs {
+					fmt.Printf("  %s\n", diff)
+				}
+				if len(diffs) != 0 {
+					return fmt.Errorf("round trip: %d differences", len(diffs))
+				}
+				fmt.Printf("round trip: parsed report matches the generator\n")
+			}
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatalf("%s: %v\n", cmd.Use, err)
+	}
+	return cmd
+}
+
+func cmdReportAnonymize() *cobra.Command {
+	var opts anonymizer.Options
+	var output string
+	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().Uint64Var(&opts.Seed, "seed", opts.Seed, "seed for the clan and name mappings")
+		cmd.Flags().IntVar(&opts.Clan, "clan", opts.Clan, "new number for the clan (default is picked from the seed)")
+		cmd.Flags().IntVar(&opts.GridRows, "shift-rows", opts.GridRows, "number of grids to shift the map down")
+		cmd.Flags().IntVar(&opts.GridColumns, "shift-columns", opts.GridColumns, "number of grids to shift the map right")
+		cmd.Flags().StringVar(&output, "output", ".", "folder to create the anonymized reports in")
+		return nil
+	}
+	cmd := &cobra.Command{
+		Use:          "anonymize <report-extract>...",
+		Short:        "anonymize turn report extracts",
+		Long:         `Remap the clan and unit ids, settlement names, and special hex names in turn report extracts, and optionally shift the map by whole grids. All the extracts given on the command line share the same mapping.`,
+		SilenceUsage: true,
+		Args:         cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			quiet, _ := cmd.Flags().GetBool("quiet")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			debug, _ := cmd.Flags().GetBool("debug")
+			if quiet {
+				verbose = false
+			}
+			a, err := anonymizer.New(opts)
+			if err != nil {
+				return err
+			}
+			for _, path := range args {
+				input, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				name := filepath.Base(path)
+				result, err := a.Anonymize(name, input, quiet, verbose, debug)
+				if err != nil {
+					return err
+				}
+				outputPath := filepath.Join(output, a.FileName(name))
+				if sameFile(path, outputPath) {
+					return fmt.Errorf("%s: would overwrite the input", outputPath)
+				} else if err = os.WriteFile(outputPath, result.Text, 0o644); err != nil {
+					return err
+				}
+				fmt.Printf("%s: created %s\n", path, outputPath)
+				for _, unit := range result.Unchecked {
+					fmt.Printf("  %s: could not be parsed; check it for settlement names by hand\n", unit)
+				}
+			}
+			return nil
+		},
+	}
+	if err := addFlags(cmd); err != nil {
+		log.Fatalf("%s: %v\n", cmd.Use, err)
+	}
+	return cmd
+}
+
+// sameFile returns true if both paths point to the same existing file.
+func sameFile(a, b string) bool {
+	sa, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	sb, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(sa, sb)
+}
@@ -12,6 +12,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/playbymail/ottoapp/backend/domains"
+	dialects "github.com/playbymail/ottoapp/backend/parsers"
 	"github.com/playbymail/ottoapp/backend/parsers/reports"
 	"github.com/playbymail/ottoapp/backend/services/reports/cst"
 	"github.com/playbymail/ottoapp/backend/services/reports/cst2"
@@ -69,13 +71,10 @@ func cmdRunLemonLex() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			showTiming, _ := cmd.Flags().GetBool("show-timing")
 			reportFile := args[0]
-			if strings.ToLower(filepath.Ext(reportFile)) != ".docx" {
-				return fmt.Errorf("report file must be .docx")
-			}
 			var docx *parsers.Docx
 			if input, err := os.ReadFile(reportFile); err != nil {
 				log.Fatal(err)
-			} else if docx, err = parsers.ParseDocx(bytes.NewReader(input), true, true); err != nil {
+			} else if docx, err = parsers.ParseReport(input, true, true); err != nil {
 				log.Fatal(err)
 			}
 			startedAt := time.Now()
@@ -113,13 +112,10 @@ func cmdRunLemonParse() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			showTiming, _ := cmd.Flags().GetBool("show-timing")
 			reportFile := args[0]
-			if strings.ToLower(filepath.Ext(reportFile)) != ".docx" {
-				return fmt.Errorf("report file must be .docx")
-			}
 			var docx *parsers.Docx
 			if input, err := os.ReadFile(reportFile); err != nil {
 				log.Fatal(err)
-			} else if docx, err = parsers.ParseDocx(bytes.NewReader(input), false, false); err != nil {
+			} else if docx, err = parsers.ParseReport(input, false, false); err != nil {
 				log.Fatal(err)
 			}
 			startedAt := time.Now()
@@ -272,9 +268,13 @@ func cmdRunParseCst() *cobra.Command {
 
 func cmdRunParseReportFile() *cobra.Command {
 	var outputPath string
+	format := string(dialects.DefaultFormat)
+	parseText := false
 	trimLeading := true
 	trimTrailing := true
 	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().StringVar(&format, "format", format, "format of the turn report (with --parse)")
+		cmd.Flags().BoolVar(&parseText, "parse", parseText, "parse the extracted text and output the JSON")
 		cmd.Flags().BoolVar(&trimLeading, "trim-leading", trimLeading, "trim leading spaces")
 		cmd.Flags().BoolVar(&trimTrailing, "trim-trailing", trimTrailing, "trim trailing spaces")
 		cmd.Flags().StringVar(&outputPath, "output", outputPath, "path to save parsed report to")
@@ -284,26 +284,40 @@ func cmdRunParseReportFile() *cobra.Command {
 	var cmd = &cobra.Command{
 		Use:   "report <turn-report-file-name>",
 		Short: "Parse a turn report file",
+		Long:  `Extract the text from a turn report file. With --parse, the text is parsed with the dialect registered for the format and game, and the output is JSON; the file name must follow the {game}.{turn}.{clan}.docx convention.`,
 		Args:  cobra.ExactArgs(1), // require path to turn report file
 		RunE: func(cmd *cobra.Command, args []string) error {
 			startedAt := time.Now()
 			report := args[0]
-			if strings.ToLower(filepath.Ext(report)) != ".docx" {
-				return fmt.Errorf("turn report file must be .docx")
-			}
-
 			var docx *parsers.Docx
 			if input, err := os.ReadFile(report); err != nil {
 				log.Fatal(err)
-			} else if docx, err = parsers.ParseDocx(bytes.NewReader(input), trimLeading, trimTrailing); err != nil {
+			} else if docx, err = parsers.ParseReport(input, trimLeading, trimTrailing); err != nil {
 				log.Fatal(err)
 			}
 
+			data := docx.Text
+			if parseText {
+				name := filepath.Base(report)
+				fields := strings.Split(name, ".")
+				if len(fields) < 3 {
+					return fmt.Errorf("%s: name must be {game}.{turn}.{clan}.docx", name)
+				}
+				result, err := dialects.DefaultRegistry().Parse(dialects.Format(format), fields[0], name, domains.TurnIdFromName(name), docx.Text)
+				if err != nil {
+					return err
+				}
+				log.Printf("%s: parsed by %s %s\n", name, result.Parser, result.Version.Core())
+				if data, err = json.MarshalIndent(result.Output, "", "  "); err != nil {
+					return err
+				}
+			}
+
 			if outputPath == "" {
-				fmt.Println(string(docx.Text))
+				fmt.Println(string(data))
 				return nil
 			}
-			if err := os.WriteFile(outputPath, docx.Text, 0o644); err != nil {
+			if err := os.WriteFile(outputPath, data, 0o644); err != nil {
 				log.Fatalf("error: %v\n", err)
 			}
 			log.Printf("%s: created in %v\n", outputPath, time.Since(startedAt))
@@ -322,6 +336,8 @@ func cmdRunParseTurnReport() *cobra.Command {
 	patchNA := false
 	rawExtractFile := ""
 	scrubbedFile := ""
+	scrubRules := ""
+	scrubAudit := false
 	showJson := false
 	showStats := false
 	showTiming := false
@@ -329,6 +345,8 @@ func cmdRunParseTurnReport() *cobra.Command {
 		cmd.Flags().BoolVar(&patchNA, "patch-na", patchNA, "patch N/A")
 		cmd.Flags().StringVar(&rawExtractFile, "raw-extract", rawExtractFile, "path to save raw extract to")
 		cmd.Flags().StringVar(&scrubbedFile, "scrubbed-extract", scrubbedFile, "path to save scrubbed extract to")
+		cmd.Flags().StringVar(&scrubRules, "scrub-rules", scrubRules, "path to JSON scrubber rules (default is built in)")
+		cmd.Flags().BoolVar(&scrubAudit, "scrub-audit", scrubAudit, "show lines dropped or rewritten by the scrubber")
 		cmd.Flags().BoolVar(&showJson, "show-json", showJson, "show json after extract")
 		cmd.Flags().BoolVar(&showStats, "show-stats", showStats, "show stats after extract")
 		cmd.Flags().BoolVar(&showTiming, "show-timing", showStats, "show timing after extract")
@@ -347,7 +365,7 @@ func cmdRunParseTurnReport() *cobra.Command {
 			docxFileName := args[0]
 			if data, err := os.ReadFile(docxFileName); err != nil {
 				return err
-			} else if docx, err = parsers.ParseDocx(bytes.NewReader(data), false, false); err != nil {
+			} else if docx, err = parsers.ParseReport(data, false, false); err != nil {
 				return err
 			}
 			if showTiming {
@@ -363,7 +381,19 @@ func cmdRunParseTurnReport() *cobra.Command {
 				}
 			}
 
-			lines := scrubbers.Scrub(bytes.Split(docx.Text, []byte{'\n'}), patchNA)
+			rules := scrubbers.DefaultRules()
+			if scrubRules != "" {
+				var err error
+				if rules, err = scrubbers.LoadRules(scrubRules); err != nil {
+					return err
+				}
+			}
+			lines, audit := scrubbers.New(rules, patchNA).Scrub(bytes.Split(docx.Text, []byte{'\n'}))
+			if scrubAudit {
+				for _, a := range audit {
+					log.Printf("scrub: %s\n", a)
+				}
+			}
 			if scrubbedFile != "" {
 				output := bytes.Join(lines, []byte{'\n'})
 				if len(output) == 0 {
@@ -6,18 +6,22 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/playbymail/ottoapp"
+	"github.com/playbymail/ottoapp/backend/domains"
 	"github.com/playbymail/ottoapp/backend/iana"
 	"github.com/playbymail/ottoapp/backend/services/authn"
 	"github.com/playbymail/ottoapp/backend/services/authz"
 	"github.com/playbymail/ottoapp/backend/services/config"
 	"github.com/playbymail/ottoapp/backend/services/documents"
 	"github.com/playbymail/ottoapp/backend/services/games"
+	"github.com/playbymail/ottoapp/backend/services/reports"
 	"github.com/playbymail/ottoapp/backend/services/sync"
 	"github.com/playbymail/ottoapp/backend/services/users"
+	"github.com/playbymail/ottoapp/backend/stores/blobs"
 	"github.com/playbymail/ottoapp/backend/stores/sqlite"
 	"github.com/spf13/cobra"
 )
@@ -42,14 +46,110 @@ func cmdSync() *cobra.Command {
 
 func cmdSyncExport() *cobra.Command {
 	addFlags := func(cmd *cobra.Command) error {
+		cmd.Flags().String("output", "", "path to the ZIP file (default {handle}.zip)")
+		cmd.Flags().String("kind", "", "only export documents of this type")
+		cmd.Flags().String("from", "", "only export turns on or after this turn (YYYY-MM)")
+		cmd.Flags().String("to", "", "only export turns on or before this turn (YYYY-MM)")
 		return nil
 	}
 	var cmd = &cobra.Command{
-		Use:   "export",
-		Short: "export files",
+		Use:          "export handle",
+		Short:        "export a user's documents to a ZIP file",
+		SilenceUsage: true,
+		Args:         cobra.ExactArgs(1), // require user handle
+		RunE: func(cmd *cobra.Command, args []string) error {
+			const checkVersion = true
+			quiet, _ := cmd.Flags().GetBool("quiet")
+			verbose, _ := cmd.Flags().GetBool("verbose")
+			debug, _ := cmd.Flags().GetBool("debug")
+			if quiet {
+				verbose = false
+			}
+
+			started := time.Now()
+			handle := args[0]
+			output, _ := cmd.Flags().GetString("output")
+			if output == "" {
+				output = handle + ".zip"
+			}
+			kind, _ := cmd.Flags().GetString("kind")
+			fromTurn, _ := cmd.Flags().GetString("from")
+			toTurn, _ := cmd.Flags().GetString("to")
+			filter := documents.ArchiveFilter{
+				Type:     domains.DocumentType(kind),
+				FromTurn: fromTurn,
+				ToTurn:   toTurn,
+			}
+			if filter.Type != "" && !filter.Type.IsValid() {
+				return fmt.Errorf("kind: %q: unknown type", kind)
+			} else if filter.FromTurn != "" && !documents.IsTurnId(filter.FromTurn) {
+				return fmt.Errorf("from: %q: invalid turn", fromTurn)
+			} else if filter.ToTurn != "" && !documents.IsTurnId(filter.ToTurn) {
+				return fmt.Errorf("to: %q: invalid turn", toTurn)
+			}
+
+			dbPath, err := cmd.Flags().GetString("db")
+			if err != nil {
+				return err
+			}
+			ctx := context.Background()
+			db, err := sqlite.Open(ctx, dbPath, checkVersion, quiet, verbose, debug)
+			if err != nil {
+				log.Fatalf("db: open: %v\n", err)
+			}
+			defer func() {
+				_ = db.Close()
+			}()
+			log.Printf("%s: connected\n", dbPath)
+
+			authzSvc := authz.New(db)
+			authnSvc := authn.New(db, authzSvc)
+			ianaSvc, err := iana.New(db, quiet, verbose, debug)
+			if err != nil {
+				return err
+			}
+			usersSvc := users.New(db, authnSvc, authzSvc, ianaSvc)
+			blobStore := blobs.New(db)
+			reportsSvc := reports.New(db, blobStore, nil, nil)
+			documentsSvc, err := documents.New(db, authzSvc, usersSvc, reportsSvc, blobStore, quiet, verbose, debug)
+			if err != nil {
+				return err
+			}
+
+			userId, err := usersSvc.GetUserIDByHandle(handle)
+			if err != nil {
+				return fmt.Errorf("%s: %w", handle, err)
+			}
+			actor := &domains.Actor{ID: authz.SysopId, Roles: domains.Roles{Sysop: true}}
+			entries, err := documentsSvc.ReadArchive(actor, userId, filter, quiet, verbose, debug)
+			if err != nil {
+				return err
+			}
+
+			fd, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+			err = documentsSvc.WriteArchive(fd, entries, quiet, verbose, debug)
+			if cerr := fd.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				_ = os.Remove(output)
+				return err
+			}
+			if !quiet {
+				log.Printf("sync: export: %s: %d documents\n", output, len(entries))
+			}
+
+			if showTiming, _ := cmd.Flags().GetBool("show-timing"); showTiming {
+				log.Printf("sync: export: completed in %v\n", time.Since(started))
+			}
+			return nil
+		},
 	}
 	if err := addFlags(cmd); err != nil {
-		log.Fatal(err)
+		log.Fatalf("%s: %v\n", cmd.Use, err)
 	}
 	return cmd
 }
@@ -119,11 +219,13 @@ func cmdSyncImportGames() *cobra.Command {
 				return err
 			}
 			usersSvc := users.New(db, authnSvc, authzSvc, ianaSvc)
-			documentsSvc, err := documents.New(db, authzSvc, usersSvc, quiet, verbose, debug)
+			blobStore := blobs.New(db)
+			reportsSvc := reports.New(db, blobStore, nil, nil)
+			documentsSvc, err := documents.New(db, authzSvc, usersSvc, reportsSvc, blobStore, quiet, verbose, debug)
 			if err != nil {
 				return err
 			}
-			gamesSvc, err := games.New(db, authnSvc, authzSvc, usersSvc, quiet, verbose, debug)
+			gamesSvc, err := games.New(db, authnSvc, authzSvc, usersSvc, blobStore, quiet, verbose, debug)
 			if err != nil {
 				return err
 			}
@@ -197,11 +299,13 @@ func cmdSyncImportMapFiles() *cobra.Command {
 				return err
 			}
 			usersSvc := users.New(db, authnSvc, authzSvc, ianaSvc)
-			documentsSvc, err := documents.New(db, authzSvc, usersSvc, quiet, verbose, debug)
+			blobStore := blobs.New(db)
+			reportsSvc := reports.New(db, blobStore, nil, nil)
+			documentsSvc, err := documents.New(db, authzSvc, usersSvc, reportsSvc, blobStore, quiet, verbose, debug)
 			if err != nil {
 				return err
 			}
-			gamesSvc, err := games.New(db, authnSvc, authzSvc, usersSvc, quiet, verbose, debug)
+			gamesSvc, err := games.New(db, authnSvc, authzSvc, usersSvc, blobStore, quiet, verbose, debug)
 			if err != nil {
 				return err
 			}
@@ -269,11 +373,13 @@ func cmdSyncImportOttoAppConfig() *cobra.Command {
 				return err
 			}
 			usersSvc := users.New(db, authnSvc, authzSvc, ianaSvc)
-			documentsSvc, err := documents.New(db, authzSvc, usersSvc, quiet, verbose, debug)
+			blobStore := blobs.New(db)
+			reportsSvc := reports.New(db, blobStore, nil, nil)
+			documentsSvc, err := documents.New(db, authzSvc, usersSvc, reportsSvc, blobStore, quiet, verbose, debug)
 			if err != nil {
 				return err
 			}
-			gamesSvc, err := games.New(db, authnSvc, authzSvc, usersSvc, quiet, verbose, debug)
+			gamesSvc, err := games.New(db, authnSvc, authzSvc, usersSvc, blobStore, quiet, verbose, debug)
 			if err != nil {
 				return err
 			}
@@ -348,11 +454,13 @@ func cmdSyncImportReportExtractFiles() *cobra.Command {
 				return err
 			}
 			usersSvc := users.New(db, authnSvc, authzSvc, ianaSvc)
-			documentsSvc, err := documents.New(db, authzSvc, usersSvc, quiet, verbose, debug)
+			blobStore := blobs.New(db)
+			reportsSvc := reports.New(db, blobStore, nil, nil)
+			documentsSvc, err := documents.New(db, authzSvc, usersSvc, reportsSvc, blobStore, quiet, verbose, debug)
 			if err != nil {
 				return err
 			}
-			gamesSvc, err := games.New(db, authnSvc, authzSvc, usersSvc, quiet, verbose, debug)
+			gamesSvc, err := games.New(db, authnSvc, authzSvc, usersSvc, blobStore, quiet, verbose, debug)
 			if err != nil {
 				return err
 			}
@@ -427,11 +535,13 @@ func cmdSyncImportTurnReportFiles() *cobra.Command {
 				return err
 			}
 			usersSvc := users.New(db, authnSvc, authzSvc, ianaSvc)
-			documentsSvc, err := documents.New(db, authzSvc, usersSvc, quiet, verbose, debug)
+			blobStore := blobs.New(db)
+			reportsSvc := reports.New(db, blobStore, nil, nil)
+			documentsSvc, err := documents.New(db, authzSvc, usersSvc, reportsSvc, blobStore, quiet, verbose, debug)
 			if err != nil {
 				return err
 			}
-			gamesSvc, err := games.New(db, authnSvc, authzSvc, usersSvc, quiet, verbose, debug)
+			gamesSvc, err := games.New(db, authnSvc, authzSvc, usersSvc, blobStore, quiet, verbose, debug)
 			if err != nil {
 				return err
 			}
@@ -501,11 +611,13 @@ func cmdSyncImportUsers() *cobra.Command {
 				return err
 			}
 			usersSvc := users.New(db, authnSvc, authzSvc, ianaSvc)
-			documentsSvc, err := documents.New(db, authzSvc, usersSvc, quiet, verbose, debug)
+			blobStore := blobs.New(db)
+			reportsSvc := reports.New(db, blobStore, nil, nil)
+			documentsSvc, err := documents.New(db, authzSvc, usersSvc, reportsSvc, blobStore, quiet, verbose, debug)
 			if err != nil {
 				return err
 			}
-			gamesSvc, err := games.New(db, authnSvc, authzSvc, usersSvc, quiet, verbose, debug)
+			gamesSvc, err := games.New(db, authnSvc, authzSvc, usersSvc, blobStore, quiet, verbose, debug)
 			if err != nil {
 				return err
 			}